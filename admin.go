@@ -1,6 +1,9 @@
 package admin
 
-import "github.com/go-advanced-admin/admin/internal/adminpanel"
+import (
+	"github.com/go-advanced-admin/admin/internal/adminpanel"
+	"github.com/go-advanced-admin/admin/internal/logging"
+)
 
 // ORMIntegrator defines the interface for ORM integrations with the admin panel.
 type ORMIntegrator = adminpanel.ORMIntegrator
@@ -8,6 +11,27 @@ type ORMIntegrator = adminpanel.ORMIntegrator
 // WebIntegrator defines the interface for web framework integrations with the admin panel.
 type WebIntegrator = adminpanel.WebIntegrator
 
+// ResponseWebIntegrator defines the optional interface for web integrations writing full responses.
+type ResponseWebIntegrator = adminpanel.ResponseWebIntegrator
+
+// Response represents a complete HTTP response produced by an admin panel handler.
+type Response = adminpanel.Response
+
+// ResponseHandlerFunc represents a handler function returning a full Response.
+type ResponseHandlerFunc = adminpanel.ResponseHandlerFunc
+
+// AdaptResponseHandler wraps a ResponseHandlerFunc into a HandlerFunc for web integrations writing (status, string) results.
+var AdaptResponseHandler = adminpanel.AdaptResponseHandler
+
+// HTTPWebIntegrator is a web integrator built on net/http's ServeMux.
+type HTTPWebIntegrator = adminpanel.HTTPWebIntegrator
+
+// HTTPContext is the context passed to handlers registered through HTTPWebIntegrator.
+type HTTPContext = adminpanel.HTTPContext
+
+// NewHTTPWebIntegrator creates a new net/http web integrator registering its routes on the given mux.
+var NewHTTPWebIntegrator = adminpanel.NewHTTPWebIntegrator
+
 // HandlerFunc represents a handler function used in the admin panel routes.
 type HandlerFunc = adminpanel.HandlerFunc
 
@@ -17,12 +41,177 @@ type PermissionRequest = adminpanel.PermissionRequest
 // PermissionFunc defines a function type for checking permissions in the admin panel.
 type PermissionFunc = adminpanel.PermissionFunc
 
+// BatchPermissionChecker evaluates many permission requests in a single call.
+type BatchPermissionChecker = adminpanel.BatchPermissionChecker
+
+// ContextPermissionFunc is the context-aware variant of PermissionFunc, set as Config.ContextPermissionChecker.
+type ContextPermissionFunc = adminpanel.ContextPermissionFunc
+
+// ContextUserFetchFunction is the context-aware variant of the user fetch function, set as Config.ContextUserFetcher.
+type ContextUserFetchFunction = adminpanel.ContextUserFetchFunction
+
+// PrimaryKeyFieldORMIntegrator defines the optional interface for ORM integrations naming the primary key field.
+type PrimaryKeyFieldORMIntegrator = adminpanel.PrimaryKeyFieldORMIntegrator
+
+// ContextORMIntegrator defines the optional interface for ORM integrations running queries with the request context.
+type ContextORMIntegrator = adminpanel.ContextORMIntegrator
+
+// ContextWebIntegrator defines the optional interface for web integrations providing the context of a request.
+type ContextWebIntegrator = adminpanel.ContextWebIntegrator
+
+// RequestFromContext returns the request object a context passed to context-aware functions was derived from.
+var RequestFromContext = adminpanel.RequestFromContext
+
+// ErrFieldNotWritable is returned when a request sets a field the user is not allowed to update.
+var ErrFieldNotWritable = adminpanel.ErrFieldNotWritable
+
+// ErrRequestTooLarge is returned when the body of a request exceeds the size accepted by the web integrator.
+var ErrRequestTooLarge = adminpanel.ErrRequestTooLarge
+
+// FilterExpression represents a single constraint on a field of a model.
+type FilterExpression = adminpanel.FilterExpression
+
+// QueryScopeFunc returns the constraints limiting the instances of a model the user may see and modify.
+type QueryScopeFunc = adminpanel.QueryScopeFunc
+
+// ScopedORMIntegrator defines the optional interface for ORM integrations applying query scopes in the database.
+type ScopedORMIntegrator = adminpanel.ScopedORMIntegrator
+
+// ErrOutOfScope is returned when an instance does not exist within the query scope of the user.
+var ErrOutOfScope = adminpanel.ErrOutOfScope
+
+// TransactionalORMIntegrator defines the optional interface for ORM integrations running mutations in transactions.
+type TransactionalORMIntegrator = adminpanel.TransactionalORMIntegrator
+
+// TransactionalLogStore defines the optional interface for log stores inserting entries in the transaction of a mutation.
+type TransactionalLogStore = adminpanel.TransactionalLogStore
+
+// LogEntry represents a single entry of the admin panel's audit log.
+type LogEntry = logging.LogEntry
+
+// LogStore defines the interface for storing the admin panel's log entries.
+type LogStore = logging.LogStore
+
+// ContextLogStore is the context-aware variant of LogStore.
+type ContextLogStore = logging.ContextLogStore
+
+// QueryableLogStore defines the optional interface for log stores filtering and paginating entries themselves.
+type QueryableLogStore = logging.QueryableLogStore
+
+// AdaptLogStore returns a log store as a ContextLogStore.
+var AdaptLogStore = logging.AdaptLogStore
+
+// ContextLogStoreAdapter adapts a ContextLogStore to LogStore.
+type ContextLogStoreAdapter = logging.ContextLogStoreAdapter
+
+// NewContextLogStoreAdapter adapts a ContextLogStore to LogStore.
+var NewContextLogStoreAdapter = logging.NewContextLogStoreAdapter
+
+// LogQuery describes a lookup of log entries.
+type LogQuery = logging.LogQuery
+
+// LogPage holds a page of log entries matching a query.
+type LogPage = logging.LogPage
+
+// HistoryEntry is a log entry about an instance along with the field changes it records.
+type HistoryEntry = adminpanel.HistoryEntry
+
+// FieldChange is the change of a single field recorded in an update log entry.
+type FieldChange = adminpanel.FieldChange
+
+// FieldDiff is the change of a field recorded by an update log entry.
+type FieldDiff = logging.FieldDiff
+
+// RetentionPolicy describes which log entries a store keeps.
+type RetentionPolicy = logging.RetentionPolicy
+
+// NewInMemoryLogStore creates a log store keeping the given number of most recent entries in memory.
+var NewInMemoryLogStore = logging.NewInMemoryLogStore
+
+// ChainedLogStore wraps a log store to make its entries tamper-evident with a hash chain.
+type ChainedLogStore = logging.ChainedLogStore
+
+// ChainReport is the result of verifying a chain of log entries.
+type ChainReport = logging.ChainReport
+
+// VerifiableLogStore is the optional interface for log stores able to prove their entries were not altered.
+type VerifiableLogStore = logging.VerifiableLogStore
+
+// NewChainedLogStore wraps a log store, continuing the chain from its newest entry.
+var NewChainedLogStore = logging.NewChainedLogStore
+
+// LogFailurePolicy decides what happens when a log entry cannot be recorded.
+type LogFailurePolicy = adminpanel.LogFailurePolicy
+
+// LogFailOpen lets the request succeed and reports log failures to the OnLogError hook.
+const LogFailOpen = adminpanel.LogFailOpen
+
+// LogFailClosed fails the request when its log entry cannot be recorded.
+const LogFailClosed = adminpanel.LogFailClosed
+
+// LogBufferAndRetry buffers the entries the log store fails to insert and retries them in the background.
+const LogBufferAndRetry = adminpanel.LogBufferAndRetry
+
+// RetryBuffer inserts log entries into a store in the background, retrying those the store fails to insert.
+type RetryBuffer = logging.RetryBuffer
+
+// RetryBufferOptions configures a RetryBuffer.
+type RetryBufferOptions = logging.RetryBufferOptions
+
+// NewRetryBuffer creates a retry buffer for a log store.
+var NewRetryBuffer = logging.NewRetryBuffer
+
+// LogSink receives the log entries streamed by a LogStreamer.
+type LogSink = logging.LogSink
+
+// ContextLogSink defines the optional interface for log sinks whose deliveries can be interrupted on close.
+type ContextLogSink = logging.ContextLogSink
+
+// LogStreamer fans log entries out to sinks asynchronously through bounded queues.
+type LogStreamer = logging.LogStreamer
+
+// LogStreamerOptions configures a LogStreamer.
+type LogStreamerOptions = logging.LogStreamerOptions
+
+// NewLogStreamer starts streaming log entries to the sinks.
+var NewLogStreamer = logging.NewLogStreamer
+
+// NewSlogSink creates a sink writing log entries to a slog.Handler.
+var NewSlogSink = logging.NewSlogSink
+
+// NewWriterSink creates a sink writing log entries to an io.Writer as JSON lines.
+var NewWriterSink = logging.NewWriterSink
+
+// NewWebhookSink creates a sink posting log entries as JSON to a URL, retrying failed deliveries.
+var NewWebhookSink = logging.NewWebhookSink
+
+// JSONLLogStore is an append-only log store writing entries as JSON lines to rotated files.
+type JSONLLogStore = logging.JSONLLogStore
+
+// JSONLLogStoreOptions configures a JSONLLogStore.
+type JSONLLogStoreOptions = logging.JSONLLogStoreOptions
+
+// NewJSONLLogStore creates a JSONL log store, recovering its index from the existing files.
+var NewJSONLLogStore = logging.NewJSONLLogStore
+
+// ORMLogStore is a log store persisting entries through an ORM integrator.
+type ORMLogStore = adminpanel.ORMLogStore
+
+// LogEntryRecord is the model ORMLogStore persists log entries as.
+type LogEntryRecord = adminpanel.LogEntryRecord
+
+// NewORMLogStore creates a log store persisting entries through the ORM integrator.
+var NewORMLogStore = adminpanel.NewORMLogStore
+
 // Panel represents the admin panel, which manages apps, models, and permissions.
 type Panel = adminpanel.AdminPanel
 
 // App represents an application within the admin panel, grouping related models together.
 type App = adminpanel.App
 
+// Model represents a model registered in an app. Action handlers receive it bound to the running transaction.
+type Model = adminpanel.Model
+
 // Config holds configuration settings for the admin panel.
 type Config = adminpanel.AdminConfig
 
@@ -1,7 +1,13 @@
 module github.com/go-advanced-admin/admin
 
-go 1.16
+go 1.22
 
 require github.com/stretchr/testify v1.9.0
 
 require github.com/google/uuid v1.6.0
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
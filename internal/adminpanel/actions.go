@@ -0,0 +1,232 @@
+package adminpanel
+
+import (
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"github.com/go-advanced-admin/admin/internal/utils"
+	"net/http"
+	"reflect"
+)
+
+// DeleteSelectedActionName is the name of the built-in bulk action deleting the selected instances.
+const DeleteSelectedActionName = "delete_selected"
+
+// ModelActionFunc performs a bulk action on the instances with the given primary keys. The model it receives is bound
+// to the transaction the action runs in, so writes made through its ORM integrator are rolled back with the action.
+type ModelActionFunc = func(tm *Model, ctx interface{}, ids []interface{}) error
+
+// ModelAction represents a bulk action that can be run on selected instances from the model list view.
+type ModelAction struct {
+	Name        string
+	DisplayName string
+	Handler     ModelActionFunc
+	Permission  Action
+	LogLevel    logging.LogStoreLevel
+}
+
+// BulkAction returns the permission action checked before running the bulk action with the given name.
+func BulkAction(name string) Action {
+	return Action("bulk:" + name)
+}
+
+// RegisterAction registers a bulk action on the model. Users need the permission returned by BulkAction(name) on
+// every selected instance to run it.
+func (m *Model) RegisterAction(name, displayName string, handler ModelActionFunc) error {
+	if handler == nil {
+		return fmt.Errorf("action handler cannot be nil")
+	}
+	if name == "" || !utils.IsURLSafe(name) {
+		return fmt.Errorf("action name '%s' is not URL safe", name)
+	}
+	if _, exists := m.GetAction(name); exists {
+		return fmt.Errorf("action '%s' already exists in model '%s'. Actions cannot be registered more than once", name, m.Name)
+	}
+	m.Actions = append(m.Actions, &ModelAction{
+		Name:        name,
+		DisplayName: displayName,
+		Handler:     handler,
+		Permission:  BulkAction(name),
+		LogLevel:    logging.LogStoreLevelAction,
+	})
+	return nil
+}
+
+// GetAction returns the bulk action with the given name.
+func (m *Model) GetAction(name string) (*ModelAction, bool) {
+	for _, action := range m.Actions {
+		if action.Name == name {
+			return action, true
+		}
+	}
+	return nil, false
+}
+
+// GetActionLink returns the relative URL path to run bulk actions on the model.
+func (m *Model) GetActionLink() string {
+	return fmt.Sprintf("%s/action", m.GetLink())
+}
+
+// GetFullActionLink returns the full URL path to run bulk actions on the model.
+func (m *Model) GetFullActionLink() string {
+	return m.App.Panel.Config.GetLink(m.GetActionLink())
+}
+
+// GetActionsWithPermission returns the bulk actions the user may run on the model.
+func (m *Model) GetActionsWithPermission(data interface{}) ([]*ModelAction, error) {
+	actions := make([]*ModelAction, 0)
+	for _, action := range m.Actions {
+		permission := action.Permission
+		allowed, err := m.App.Panel.PermissionChecker.HasPermission(PermissionRequest{AppName: &m.App.Name, ModelName: &m.Name, Action: &permission}, data)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			actions = append(actions, action)
+		}
+	}
+	return actions, nil
+}
+
+// ParseInstanceID converts a primary key from its string representation to the model's primary key type.
+func (m *Model) ParseInstanceID(instanceIDStr string) (interface{}, error) {
+	primaryKeyType, err := m.GetPrimaryKeyType()
+	if err != nil {
+		return nil, err
+	}
+	if primaryKeyType == nil {
+		return instanceIDStr, nil
+	}
+
+	primaryKeyValue := reflect.New(primaryKeyType).Elem()
+	if err = utils.SetStringsAsType(primaryKeyValue, instanceIDStr); err != nil {
+		return nil, fmt.Errorf("invalid instance id: %v", err)
+	}
+	return primaryKeyValue.Interface(), nil
+}
+
+// CreateActionLog creates a log entry when a bulk action is run on the instance.
+func (i *Instance) CreateActionLog(ctx interface{}, level logging.LogStoreLevel, message string) error {
+	return i.Model.App.Panel.CreateLog(ctx, level, i.Model.GetLogContentType(), i.InstanceID, i.GetRepr(), message)
+}
+
+func newDeleteSelectedAction() *ModelAction {
+	return &ModelAction{
+		Name:        DeleteSelectedActionName,
+		DisplayName: "Delete selected",
+		Handler: func(tm *Model, ctx interface{}, ids []interface{}) error {
+			scope, err := tm.GetQueryScope(ctx)
+			if err != nil {
+				return err
+			}
+			for _, id := range ids {
+				if err = tm.DeleteInstanceInScope(id, scope); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Permission: DeleteAction,
+		LogLevel:   logging.LogStoreLevelDelete,
+	}
+}
+
+// GetActionHandler returns the HTTP handler function for running a bulk action. The first submission renders a
+// confirmation page; the action runs once the confirmation is submitted.
+func (m *Model) GetActionHandler() HandlerFunc {
+	return func(data interface{}) (uint, string) {
+		m := m.withRequestContext(data)
+
+		formData := m.App.Panel.Web.GetFormData(data)
+		if formData == nil {
+			return GetErrorHTML(http.StatusBadRequest, fmt.Errorf("form data is required"))
+		}
+
+		var actionName string
+		if values := formData["action"]; len(values) > 0 {
+			actionName = values[0]
+		}
+		action, ok := m.GetAction(actionName)
+		if !ok {
+			return GetErrorHTML(http.StatusBadRequest, fmt.Errorf("unknown action '%s'", actionName))
+		}
+
+		idStrings := formData["ids"]
+		if len(idStrings) == 0 {
+			return GetErrorHTML(http.StatusBadRequest, fmt.Errorf("no instances selected"))
+		}
+
+		scope, err := m.GetQueryScope(data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+
+		instances := make([]*Instance, 0, len(idStrings))
+		ids := make([]interface{}, 0, len(idStrings))
+		for _, idStr := range idStrings {
+			id, err := m.ParseInstanceID(idStr)
+			if err != nil {
+				return GetErrorHTML(http.StatusBadRequest, err)
+			}
+
+			permission := action.Permission
+			allowed, err := m.App.Panel.PermissionChecker.HasPermission(PermissionRequest{AppName: &m.App.Name, ModelName: &m.Name, Action: &permission, InstanceID: id}, data)
+			if err != nil {
+				return GetErrorHTML(http.StatusInternalServerError, err)
+			}
+			if !allowed {
+				return GetErrorHTML(http.StatusForbidden, fmt.Errorf("you are not allowed to run '%s' on instance %v", action.DisplayName, id))
+			}
+
+			instanceData, err := m.FetchInstanceInScope(id, nil, scope)
+			if err != nil {
+				return GetErrorHTML(http.StatusInternalServerError, err)
+			}
+			if isNilInstance(instanceData) {
+				return GetErrorHTML(http.StatusNotFound, fmt.Errorf("%w: %v", ErrOutOfScope, id))
+			}
+			instances = append(instances, &Instance{InstanceID: id, Data: instanceData, Model: m})
+			ids = append(ids, id)
+		}
+
+		var confirmed bool
+		if values := formData["confirm"]; len(values) > 0 {
+			confirmed = values[0] == "yes"
+		}
+
+		if !confirmed {
+			apps, err := GetAppsWithReadPermissions(m.App.Panel, data)
+			if err != nil {
+				return GetErrorHTML(http.StatusInternalServerError, err)
+			}
+
+			html, err := m.App.Panel.Config.Renderer.RenderTemplate("action_confirmation", map[string]interface{}{
+				"apps":        apps,
+				"navBarItems": m.App.Panel.Config.GetNavBarItems(data),
+				"model":       m,
+				"action":      action,
+				"instances":   instances,
+			})
+			if err != nil {
+				return GetErrorHTML(http.StatusInternalServerError, err)
+			}
+			return http.StatusOK, html
+		}
+
+		err = m.WithTransaction(data, func(tm *Model) error {
+			if err := action.Handler(tm, data, ids); err != nil {
+				return err
+			}
+			for _, instance := range instances {
+				if err := instance.CreateActionLog(data, action.LogLevel, action.Name); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+
+		return http.StatusSeeOther, m.GetFullLink()
+	}
+}
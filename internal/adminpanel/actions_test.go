@@ -0,0 +1,140 @@
+package adminpanel
+
+import (
+	"errors"
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type ActionsORMIntegrator struct {
+	MockORMIntegrator
+	Missing bool
+}
+
+func (o *ActionsORMIntegrator) FetchInstance(interface{}, interface{}) (interface{}, error) {
+	if o.Missing {
+		return (*TestModel)(nil), nil
+	}
+	return &TestModel{}, nil
+}
+
+type DeleteRecorderORMIntegrator struct {
+	ActionsORMIntegrator
+	Deleted []interface{}
+}
+
+func (o *DeleteRecorderORMIntegrator) DeleteInstance(_ interface{}, id interface{}) error {
+	o.Deleted = append(o.Deleted, id)
+	return nil
+}
+
+func TestModel_RegisterAction(t *testing.T) {
+	model := newTestModel(t, &TestModel{}, &ActionsORMIntegrator{})
+	handler := func(*Model, interface{}, []interface{}) error { return nil }
+
+	if err := model.RegisterAction("mark_paid", "Mark as paid", handler); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := model.RegisterAction("mark_paid", "Mark as paid", handler); err == nil {
+		t.Error("expected an error when registering the same action twice")
+	}
+	if err := model.RegisterAction(DeleteSelectedActionName, "Delete", handler); err == nil {
+		t.Error("expected an error when overriding the built-in delete action")
+	}
+	if err := model.RegisterAction("not safe", "Not Safe", handler); err == nil {
+		t.Error("expected an error for an unsafe action name")
+	}
+	if err := model.RegisterAction("empty", "Empty", nil); err == nil {
+		t.Error("expected an error for a nil handler")
+	}
+
+	action, ok := model.GetAction("mark_paid")
+	if !ok {
+		t.Fatalf("expected action to be registered")
+	}
+	if action.Permission != BulkAction("mark_paid") {
+		t.Errorf("expected permission %s, got %s", BulkAction("mark_paid"), action.Permission)
+	}
+}
+
+func TestModel_GetActionHandler(t *testing.T) {
+	t.Run("Confirmation", func(t *testing.T) {
+		model := newTestModel(t, &TestModel{}, &ActionsORMIntegrator{})
+		code, html := model.GetActionHandler()(map[string][]string{"action": {DeleteSelectedActionName}, "ids": {"1", "2"}})
+		if code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", code, html)
+		}
+		if !strings.Contains(html, `name="confirm" value="yes"`) {
+			t.Error("expected confirmation form to be rendered")
+		}
+	})
+
+	t.Run("DeleteSelected", func(t *testing.T) {
+		orm := &DeleteRecorderORMIntegrator{}
+		model := newTestModel(t, &TestModel{}, orm)
+		store := logging.NewInMemoryLogStore(100)
+		model.App.Panel.Config.LogStore = store
+
+		code, _ := model.GetActionHandler()(map[string][]string{"action": {DeleteSelectedActionName}, "ids": {"1", "2"}, "confirm": {"yes"}})
+		if code != http.StatusSeeOther {
+			t.Fatalf("expected status 303, got %d", code)
+		}
+		if len(orm.Deleted) != 2 {
+			t.Errorf("expected 2 deleted instances, got %d", len(orm.Deleted))
+		}
+		entries, _ := store.GetLogEntries()
+		if len(entries) != 2 || entries[0].ActionFlag != logging.LogStoreLevelDelete {
+			t.Errorf("expected 2 delete log entries, got %v", entries)
+		}
+	})
+
+	t.Run("CustomAction", func(t *testing.T) {
+		model := newTestModel(t, &TestModel{}, &ActionsORMIntegrator{})
+		var received []interface{}
+		err := model.RegisterAction("archive", "Archive", func(_ *Model, _ interface{}, ids []interface{}) error {
+			received = ids
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		code, _ := model.GetActionHandler()(map[string][]string{"action": {"archive"}, "ids": {"7"}, "confirm": {"yes"}})
+		if code != http.StatusSeeOther {
+			t.Fatalf("expected status 303, got %d", code)
+		}
+		if len(received) != 1 || received[0] != "7" {
+			t.Errorf("expected action to receive id 7, got %v", received)
+		}
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		model := newTestModel(t, &TestModel{}, &ActionsORMIntegrator{})
+		model.App.Panel.PermissionChecker = func(req PermissionRequest, _ interface{}) (bool, error) {
+			return *req.Action != DeleteAction, nil
+		}
+		code, _ := model.GetActionHandler()(map[string][]string{"action": {DeleteSelectedActionName}, "ids": {"1"}, "confirm": {"yes"}})
+		if code != http.StatusForbidden {
+			t.Errorf("expected status 403, got %d", code)
+		}
+	})
+
+	t.Run("ActionError", func(t *testing.T) {
+		model := newTestModel(t, &TestModel{}, &ActionsORMIntegrator{})
+		_ = model.RegisterAction("fail", "Fail", func(*Model, interface{}, []interface{}) error { return errors.New("failed") })
+		code, _ := model.GetActionHandler()(map[string][]string{"action": {"fail"}, "ids": {"1"}, "confirm": {"yes"}})
+		if code != http.StatusInternalServerError {
+			t.Errorf("expected status 500, got %d", code)
+		}
+	})
+
+	t.Run("UnknownAction", func(t *testing.T) {
+		model := newTestModel(t, &TestModel{}, &ActionsORMIntegrator{})
+		code, _ := model.GetActionHandler()(map[string][]string{"action": {"unknown"}, "ids": {"1"}})
+		if code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", code)
+		}
+	})
+}
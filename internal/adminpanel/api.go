@@ -0,0 +1,552 @@
+package adminpanel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/form"
+	"github.com/go-advanced-admin/admin/internal/utils"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// APIMaxPerPage is the maximum number of instances returned in a single page of the JSON API.
+const APIMaxPerPage uint = 1000
+
+// APIInstance is the JSON representation of a model instance.
+type APIInstance struct {
+	ID     interface{}            `json:"id"`
+	Repr   string                 `json:"repr"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// APIList is the JSON representation of a page of model instances.
+type APIList struct {
+	Count      uint          `json:"count"`
+	Page       uint          `json:"page"`
+	PerPage    uint          `json:"perPage"`
+	TotalPages uint          `json:"totalPages"`
+	Results    []APIInstance `json:"results"`
+}
+
+// APIError is the JSON representation of an error. Validation errors are reported per field in FieldErrors.
+type APIError struct {
+	Error       string              `json:"error"`
+	FormErrors  []string            `json:"formErrors,omitempty"`
+	FieldErrors map[string][]string `json:"fieldErrors,omitempty"`
+}
+
+// NewAPIErrorResponse creates a new JSON error response with the given status code.
+func NewAPIErrorResponse(code uint, err error) *Response {
+	return NewJSONResponse(code, APIError{Error: err.Error()})
+}
+
+// GetAPILink returns the relative URL path to the model's JSON API.
+func (m *Model) GetAPILink() string {
+	return m.App.Panel.Config.GetAPIPrefix() + m.GetLink()
+}
+
+// GetFullAPILink returns the full URL path to the model's JSON API.
+func (m *Model) GetFullAPILink() string {
+	return m.App.Panel.Config.GetLink(m.GetAPILink())
+}
+
+// GetAPIInstanceLink returns the relative URL path to an instance in the model's JSON API.
+func (m *Model) GetAPIInstanceLink(instanceID interface{}) string {
+	return fmt.Sprintf("%s/%v", m.GetAPILink(), instanceID)
+}
+
+// RegisterAPIRoutes registers the model's JSON API routes with the web integrator.
+func (m *Model) RegisterAPIRoutes() {
+	panel := m.App.Panel
+	link := panel.Config.GetPrefix() + m.GetAPILink()
+	panel.HandleResponseRoute("GET", link, m.GetAPIListHandler())
+	panel.HandleResponseRoute("POST", link, m.GetAPICreateHandler())
+	panel.HandleResponseRoute("GET", link+"/:id", m.GetAPIRetrieveHandler())
+	panel.HandleResponseRoute("PATCH", link+"/:id", m.GetAPIUpdateHandler())
+	panel.HandleResponseRoute("DELETE", link+"/:id", m.GetAPIDeleteHandler())
+}
+
+// NewAPIInstance creates the JSON representation of an instance including the given fields.
+func (m *Model) NewAPIInstance(instanceID interface{}, instanceData interface{}, fields []FieldConfig) (APIInstance, error) {
+	apiInstance := APIInstance{ID: instanceID, Fields: make(map[string]interface{}, len(fields))}
+	instance := &Instance{InstanceID: instanceID, Data: instanceData, Model: m}
+	apiInstance.Repr = instance.GetRepr()
+	for _, fieldConfig := range fields {
+		value, err := utils.GetFieldValue(instanceData, fieldConfig.Name)
+		if err != nil {
+			return APIInstance{}, err
+		}
+		apiInstance.Fields[fieldConfig.Name] = value
+	}
+	return apiInstance, nil
+}
+
+func (m *Model) getFieldsWhere(include func(FieldConfig) bool) []FieldConfig {
+	fields := make([]FieldConfig, 0)
+	for _, fieldConfig := range m.Fields {
+		if include(fieldConfig) {
+			fields = append(fields, fieldConfig)
+		}
+	}
+	return fields
+}
+
+func getFieldNames(fields []FieldConfig) []string {
+	names := make([]string, len(fields))
+	for i, fieldConfig := range fields {
+		names[i] = fieldConfig.Name
+	}
+	return names
+}
+
+// GetAPIListHandler returns the JSON API handler listing the model's instances, supporting the same pagination,
+// search, filter and ordering query parameters as the list view.
+func (m *Model) GetAPIListHandler() ResponseHandlerFunc {
+	return func(data interface{}) *Response {
+		m := m.withRequestContext(data)
+
+		allowed, err := m.App.Panel.PermissionChecker.HasModelReadPermission(m.App.Name, m.Name, data)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		if !allowed {
+			return NewAPIErrorResponse(http.StatusForbidden, fmt.Errorf("forbidden"))
+		}
+
+		page := uint(1)
+		if p, err := strconv.Atoi(m.App.Panel.Web.GetQueryParam(data, "page")); err == nil && p > 0 {
+			page = uint(p)
+		}
+		perPage := m.App.Panel.Config.DefaultInstancesPerPage
+		if pp, err := strconv.Atoi(m.App.Panel.Web.GetQueryParam(data, "perPage")); err == nil && pp > 0 {
+			perPage = uint(pp)
+		}
+		if perPage == 0 {
+			perPage = 1
+		}
+		if perPage > APIMaxPerPage {
+			perPage = APIMaxPerPage
+		}
+
+		listFields, err := m.FilterReadableFields(m.getFieldsWhere(func(fieldConfig FieldConfig) bool {
+			return fieldConfig.IncludeInListDisplay
+		}), nil, data)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		listed, err := m.fetchListPage(data, page, perPage)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+
+		list := APIList{
+			Count:      listed.TotalCount,
+			Page:       page,
+			PerPage:    perPage,
+			TotalPages: (listed.TotalCount + perPage - 1) / perPage,
+			Results:    make([]APIInstance, 0, len(listed.Instances)),
+		}
+		for _, instance := range listed.Instances {
+			id, err := m.GetPrimaryKeyValue(instance)
+			if err != nil {
+				return NewAPIErrorResponse(http.StatusInternalServerError, err)
+			}
+			apiInstance, err := m.NewAPIInstance(id, instance, listFields)
+			if err != nil {
+				return NewAPIErrorResponse(http.StatusInternalServerError, err)
+			}
+			list.Results = append(list.Results, apiInstance)
+		}
+
+		if err = m.CreateViewLog(data); err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		return NewJSONResponse(http.StatusOK, list)
+	}
+}
+
+// GetAPIRetrieveHandler returns the JSON API handler retrieving a single instance.
+func (m *Model) GetAPIRetrieveHandler() ResponseHandlerFunc {
+	return func(data interface{}) *Response {
+		m := m.withRequestContext(data)
+
+		instanceID, errResponse := m.getAPIInstanceID(data)
+		if errResponse != nil {
+			return errResponse
+		}
+
+		allowed, err := m.App.Panel.PermissionChecker.HasInstanceReadPermission(m.App.Name, m.Name, instanceID, data)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		if !allowed {
+			return NewAPIErrorResponse(http.StatusForbidden, fmt.Errorf("you are not allowed to view this instance"))
+		}
+
+		apiInstance, errResponse := m.fetchAPIInstance(instanceID, data)
+		if errResponse != nil {
+			return errResponse
+		}
+
+		instance := &Instance{InstanceID: instanceID, Data: apiInstance.instanceData, Model: m}
+		if err = instance.CreateViewLog(data); err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		return NewJSONResponse(http.StatusOK, apiInstance.APIInstance)
+	}
+}
+
+// GetAPICreateHandler returns the JSON API handler creating a new instance through the model's add form.
+func (m *Model) GetAPICreateHandler() ResponseHandlerFunc {
+	return func(data interface{}) *Response {
+		m := m.withRequestContext(data)
+
+		allowed, err := m.App.Panel.PermissionChecker.HasModelCreatePermission(m.App.Name, m.Name, data)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		if !allowed {
+			return NewAPIErrorResponse(http.StatusForbidden, fmt.Errorf("forbidden"))
+		}
+
+		formInstance, err := m.NewAddForm()
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+
+		values, errResponse := m.getAPIValues(data, formInstance)
+		if errResponse != nil {
+			return errResponse
+		}
+		if errResponse = validateAPIValues(formInstance, values); errResponse != nil {
+			return errResponse
+		}
+
+		var instanceID, instanceData interface{}
+		err = m.WithTransaction(data, func(tm *Model) error {
+			bindForm(formInstance, tm)
+			instanceData, err = formInstance.Save(values)
+			if err != nil {
+				return err
+			}
+			instanceID, err = m.GetPrimaryKeyValue(instanceData)
+			if err != nil {
+				return err
+			}
+			if instanceID == nil {
+				return fmt.Errorf("instance id is nil")
+			}
+
+			instance := &Instance{InstanceID: instanceID, Data: instanceData, Model: m}
+			return instance.CreateCreateLog(data)
+		})
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+
+		instanceFields, err := m.FilterReadableFields(m.getFieldsWhere(func(fieldConfig FieldConfig) bool {
+			return fieldConfig.IncludeInInstanceView
+		}), instanceID, data)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		apiInstance, err := m.NewAPIInstance(instanceID, instanceData, instanceFields)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		response := NewJSONResponse(http.StatusCreated, apiInstance)
+		response.Header().Set("Location", m.App.Panel.Config.GetLink(m.GetAPIInstanceLink(instanceID)))
+		return response
+	}
+}
+
+// GetAPIUpdateHandler returns the JSON API handler partially updating an instance through the model's edit form.
+// Fields missing from the request keep their current value.
+func (m *Model) GetAPIUpdateHandler() ResponseHandlerFunc {
+	return func(data interface{}) *Response {
+		m := m.withRequestContext(data)
+
+		instanceID, errResponse := m.getAPIInstanceID(data)
+		if errResponse != nil {
+			return errResponse
+		}
+
+		allowed, err := m.App.Panel.PermissionChecker.HasInstanceUpdatePermission(m.App.Name, m.Name, instanceID, data)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		if !allowed {
+			return NewAPIErrorResponse(http.StatusForbidden, fmt.Errorf("you are not allowed to update this instance"))
+		}
+
+		scope, err := m.GetQueryScope(data)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		existing, err := m.FetchInstanceInScope(instanceID, nil, scope)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		if isNilInstance(existing) {
+			return NewAPIErrorResponse(http.StatusNotFound, ErrOutOfScope)
+		}
+
+		fieldPermissions, err := m.GetFieldPermissions(instanceID, data)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		formInstance, err := m.NewEditFormWithPermissions(instanceID, fieldPermissions)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		formInstance.(*ModelEditForm).Scope = scope
+		readOnlyFields := formInstance.(*ModelEditForm).ReadOnlyFields
+
+		values, errResponse := m.getAPIValues(data, formInstance)
+		if errResponse != nil {
+			return errResponse
+		}
+		for _, field := range formInstance.GetFields() {
+			if _, ok := values[field.GetName()]; ok || readOnlyFields[field.GetName()] {
+				continue
+			}
+			value, err := utils.GetFieldValue(existing, field.GetName())
+			if err != nil {
+				return NewAPIErrorResponse(http.StatusInternalServerError, err)
+			}
+			htmlValue, err := field.GoTypeToHTMLType(dereferenceImportValue(value))
+			if err != nil {
+				return NewAPIErrorResponse(http.StatusInternalServerError, err)
+			}
+			values[field.GetName()] = htmlValue
+		}
+		if errResponse = validateAPIValues(formInstance, values); errResponse != nil {
+			return errResponse
+		}
+
+		cleanValues, err := form.GetCleanData(formInstance, values)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		for fieldName := range readOnlyFields {
+			delete(cleanValues, fieldName)
+		}
+		diff, err := m.DiffInstance(existing, cleanValues)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		err = m.WithTransaction(data, func(tm *Model) error {
+			bindForm(formInstance, tm)
+			instanceData, err := formInstance.Save(values)
+			if err != nil {
+				return err
+			}
+			instance := &Instance{InstanceID: instanceID, Data: instanceData, Model: m}
+			return instance.CreateUpdateLog(data, diff)
+		})
+		if errors.Is(err, ErrFieldNotWritable) {
+			return NewAPIErrorResponse(http.StatusForbidden, err)
+		} else if errors.Is(err, ErrOutOfScope) {
+			return NewAPIErrorResponse(http.StatusNotFound, err)
+		} else if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+
+		apiInstance, errResponse := m.fetchAPIInstance(instanceID, data)
+		if errResponse != nil {
+			return errResponse
+		}
+		return NewJSONResponse(http.StatusOK, apiInstance.APIInstance)
+	}
+}
+
+// GetAPIDeleteHandler returns the JSON API handler deleting an instance.
+func (m *Model) GetAPIDeleteHandler() ResponseHandlerFunc {
+	return func(data interface{}) *Response {
+		m := m.withRequestContext(data)
+
+		instanceID, errResponse := m.getAPIInstanceID(data)
+		if errResponse != nil {
+			return errResponse
+		}
+
+		allowed, err := m.App.Panel.PermissionChecker.HasInstanceDeletePermission(m.App.Name, m.Name, instanceID, data)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		if !allowed {
+			return NewAPIErrorResponse(http.StatusForbidden, fmt.Errorf("you are not allowed to delete this instance"))
+		}
+
+		scope, err := m.GetQueryScope(data)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		err = m.WithTransaction(data, func(tm *Model) error {
+			if err := tm.DeleteInstanceInScope(instanceID, scope); err != nil {
+				return err
+			}
+			instance := &Instance{InstanceID: instanceID, Model: m}
+			return instance.CreateDeleteLog(data)
+		})
+		if errors.Is(err, ErrOutOfScope) {
+			return NewAPIErrorResponse(http.StatusNotFound, err)
+		} else if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		return &Response{StatusCode: http.StatusNoContent}
+	}
+}
+
+func (m *Model) getAPIInstanceID(data interface{}) (interface{}, *Response) {
+	instanceIDStr := m.App.Panel.Web.GetPathParam(data, "id")
+	if instanceIDStr == "" {
+		return nil, NewAPIErrorResponse(http.StatusBadRequest, fmt.Errorf("instance id is required"))
+	}
+	instanceID, err := m.ParseInstanceID(instanceIDStr)
+	if err != nil {
+		return nil, NewAPIErrorResponse(http.StatusBadRequest, err)
+	}
+	return instanceID, nil
+}
+
+type fetchedAPIInstance struct {
+	APIInstance
+	instanceData interface{}
+}
+
+func (m *Model) fetchAPIInstance(instanceID interface{}, data interface{}) (*fetchedAPIInstance, *Response) {
+	instanceFields, err := m.FilterReadableFields(m.getFieldsWhere(func(fieldConfig FieldConfig) bool {
+		return fieldConfig.IncludeInInstanceView
+	}), instanceID, data)
+	if err != nil {
+		return nil, NewAPIErrorResponse(http.StatusInternalServerError, err)
+	}
+	scope, err := m.GetQueryScope(data)
+	if err != nil {
+		return nil, NewAPIErrorResponse(http.StatusInternalServerError, err)
+	}
+	instanceData, err := m.FetchInstanceInScope(instanceID, getFieldNames(instanceFields), scope)
+	if err != nil {
+		return nil, NewAPIErrorResponse(http.StatusInternalServerError, err)
+	}
+	if isNilInstance(instanceData) {
+		return nil, NewAPIErrorResponse(http.StatusNotFound, ErrOutOfScope)
+	}
+	apiInstance, err := m.NewAPIInstance(instanceID, instanceData, instanceFields)
+	if err != nil {
+		return nil, NewAPIErrorResponse(http.StatusInternalServerError, err)
+	}
+	return &fetchedAPIInstance{APIInstance: apiInstance, instanceData: instanceData}, nil
+}
+
+// getAPIValues reads the values of the form's fields from the request. The JSON request body is used when the web
+// integrator implements RequestBodyWebIntegrator, the form data otherwise. Known fields outside the form are
+// read-only and ignored, unknown fields are reported as errors.
+func (m *Model) getAPIValues(data interface{}, formInstance form.Form) (map[string]form.HTMLType, *Response) {
+	var rawValues map[string]string
+	if web, ok := m.App.Panel.Web.(RequestBodyWebIntegrator); ok {
+		body, err := web.GetRequestBody(data)
+		if errors.Is(err, ErrRequestTooLarge) {
+			return nil, NewAPIErrorResponse(http.StatusRequestEntityTooLarge, err)
+		}
+		if err != nil {
+			return nil, NewAPIErrorResponse(http.StatusBadRequest, err)
+		}
+		object := make(map[string]interface{})
+		if len(strings.TrimSpace(string(body))) > 0 {
+			decoder := json.NewDecoder(strings.NewReader(string(body)))
+			decoder.UseNumber()
+			if err = decoder.Decode(&object); err != nil {
+				return nil, NewAPIErrorResponse(http.StatusBadRequest, fmt.Errorf("invalid JSON body: %v", err))
+			}
+		}
+		rawValues = stringifyImportObject(object)
+	} else {
+		rawValues = make(map[string]string)
+		for key, values := range m.App.Panel.Web.GetFormData(data) {
+			if len(values) > 0 {
+				rawValues[key] = values[0]
+			}
+		}
+	}
+
+	formFields := make(map[string]bool)
+	for _, field := range formInstance.GetFields() {
+		formFields[field.GetName()] = true
+	}
+
+	values := make(map[string]form.HTMLType)
+	fieldErrors := make(map[string][]string)
+	for key, value := range rawValues {
+		if formFields[key] {
+			values[key] = form.HTMLType(value)
+		} else if _, ok := m.GetFieldConfig(key); !ok {
+			fieldErrors[key] = []string{"unknown field"}
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return nil, NewJSONResponse(http.StatusBadRequest, APIError{Error: "validation failed", FieldErrors: fieldErrors})
+	}
+	return values, nil
+}
+
+func validateAPIValues(formInstance form.Form, values map[string]form.HTMLType) *Response {
+	_, formErrs, fieldErrs, err := validateFormValues(formInstance, values)
+	if err != nil {
+		return NewAPIErrorResponse(http.StatusInternalServerError, err)
+	}
+	if len(formErrs) == 0 && len(fieldErrs) == 0 {
+		return nil
+	}
+	apiError := APIError{Error: "validation failed", FieldErrors: make(map[string][]string)}
+	for _, formErr := range formErrs {
+		apiError.FormErrors = append(apiError.FormErrors, formErr.Error())
+	}
+	for fieldName, errs := range fieldErrs {
+		for _, fieldErr := range errs {
+			apiError.FieldErrors[fieldName] = append(apiError.FieldErrors[fieldName], fieldErr.Error())
+		}
+	}
+	return NewJSONResponse(http.StatusBadRequest, apiError)
+}
+
+// validateFormValues converts the values through the form's fields and validates them. Conversion errors are reported
+// as field errors, in which case the form's validation functions are not run.
+func validateFormValues(formInstance form.Form, values map[string]form.HTMLType) (map[string]interface{}, []error, map[string][]error, error) {
+	cleanValues := make(map[string]interface{})
+	fieldErrs := make(map[string][]error)
+	for _, field := range formInstance.GetFields() {
+		cleanValue, err := field.HTMLTypeToGoType(values[field.GetName()])
+		if err != nil {
+			fieldErrs[field.GetName()] = append(fieldErrs[field.GetName()], err)
+			continue
+		}
+		cleanValues[field.GetName()] = cleanValue
+	}
+	if len(fieldErrs) > 0 {
+		return cleanValues, nil, fieldErrs, nil
+	}
+
+	formErrs, validationErrs, err := form.ValuesAreValid(formInstance, cleanValues)
+	if err != nil {
+		return cleanValues, nil, nil, err
+	}
+	for fieldName, errs := range validationErrs {
+		if len(errs) > 0 {
+			fieldErrs[fieldName] = errs
+		}
+	}
+	return cleanValues, formErrs, fieldErrs, nil
+}
+
+func isNilInstance(instance interface{}) bool {
+	if instance == nil {
+		return true
+	}
+	val := reflect.ValueOf(instance)
+	return val.Kind() == reflect.Ptr && val.IsNil()
+}
@@ -0,0 +1,254 @@
+package adminpanel
+
+import (
+	"encoding/json"
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// apiTestConfig serves the JSON API and the OpenAPI document, recording log entries in memory.
+func apiTestConfig(config *AdminConfig) {
+	config.APIPrefix = "api"
+	config.OpenAPIRoute = "openapi"
+	config.LogStore = logging.NewInMemoryLogStore(100)
+}
+
+// newAPITestBob returns the second instance served by the API tests.
+func newAPITestBob() *ImportTestModel {
+	return &ImportTestModel{ID: 2, Name: "Bob", Age: 25}
+}
+
+func serveAPIRequest(web *HTTPWebIntegrator, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	web.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestModel_APIList(t *testing.T) {
+	web, model, _ := newHTTPTestPanel(t, MockPermissionFunc, apiTestConfig, newAPITestBob())
+	store := model.App.Panel.Config.LogStore.(*logging.InMemoryLogStore)
+
+	rec := serveAPIRequest(web, http.MethodGet, model.GetFullAPILink()+"?perPage=1&page=2&ordering=ID", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected JSON content type, got %q", got)
+	}
+
+	var list APIList
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.Count != 2 || list.TotalPages != 2 || len(list.Results) != 1 {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+	if list.Results[0].Fields["Name"] != "Bob" {
+		t.Errorf("expected Bob on the second page, got %v", list.Results[0].Fields)
+	}
+
+	rec = serveAPIRequest(web, http.MethodGet, model.GetFullAPILink()+"?search=Alice", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.Count != 1 || list.Results[0].Fields["Name"] != "Alice" {
+		t.Errorf("expected search to match Alice only, got %+v", list)
+	}
+
+	entries, _ := store.GetLogEntries()
+	if len(entries) != 2 || entries[0].ActionFlag != logging.LogStoreLevelListView {
+		t.Errorf("expected list view log entries, got %v", entries)
+	}
+}
+
+func TestModel_APIList_UnreadableInstances(t *testing.T) {
+	hideAlice := func(request PermissionRequest, _ interface{}) (bool, error) {
+		return request.InstanceID != uint(1), nil
+	}
+	web, model, _ := newHTTPTestPanel(t, hideAlice, apiTestConfig, newAPITestBob(), &ImportTestModel{ID: 3, Name: "Carol"})
+
+	rec := serveAPIRequest(web, http.MethodGet, model.GetFullAPILink()+"?perPage=1&ordering=ID", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var list APIList
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.Count != 2 || list.TotalPages != 2 || len(list.Results) != 1 {
+		t.Fatalf("expected the unreadable instance to be left out of the count and pages, got %+v", list)
+	}
+	if list.Results[0].Fields["Name"] != "Bob" {
+		t.Errorf("expected Bob on the first page, got %v", list.Results[0].Fields)
+	}
+}
+
+func TestModel_APIRetrieve(t *testing.T) {
+	web, model, _ := newHTTPTestPanel(t, MockPermissionFunc, apiTestConfig, newAPITestBob())
+
+	rec := serveAPIRequest(web, http.MethodGet, model.GetFullAPILink()+"/1", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var instance APIInstance
+	if err := json.Unmarshal(rec.Body.Bytes(), &instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if instance.Fields["Name"] != "Alice" || instance.ID != float64(1) {
+		t.Errorf("unexpected instance: %+v", instance)
+	}
+
+	if rec = serveAPIRequest(web, http.MethodGet, model.GetFullAPILink()+"/9", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rec.Code)
+	}
+	if rec = serveAPIRequest(web, http.MethodGet, model.GetFullAPILink()+"/abc", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestModel_APICreate(t *testing.T) {
+	web, model, orm := newHTTPTestPanel(t, MockPermissionFunc, apiTestConfig, newAPITestBob())
+	store := model.App.Panel.Config.LogStore.(*logging.InMemoryLogStore)
+
+	rec := serveAPIRequest(web, http.MethodPost, model.GetFullAPILink(), `{"Name": "Carol", "Age": 41, "ID": 7}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(orm.Created) != 1 || orm.Created[0].Name != "Carol" || orm.Created[0].Age != 41 {
+		t.Fatalf("expected Carol to be created, got %v", orm.Created)
+	}
+	if got := rec.Header().Get("Location"); got != model.GetFullAPILink()+"/100" {
+		t.Errorf("unexpected location %q", got)
+	}
+
+	entries, _ := store.GetLogEntries()
+	if len(entries) != 1 || entries[0].ActionFlag != logging.LogStoreLevelCreate {
+		t.Errorf("expected a create log entry, got %v", entries)
+	}
+}
+
+func TestModel_APIValidationErrors(t *testing.T) {
+	web, model, orm := newHTTPTestPanel(t, MockPermissionFunc, apiTestConfig, newAPITestBob())
+
+	tests := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{"Required", `{"Age": 3}`, "Name"},
+		{"MaxLength", `{"Name": "A very long name"}`, "Name"},
+		{"InvalidType", `{"Name": "Dave", "Age": "abc"}`, "Age"},
+		{"UnknownField", `{"Name": "Dave", "Nickname": "D"}`, "Nickname"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveAPIRequest(web, http.MethodPost, model.GetFullAPILink(), tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+			var apiError APIError
+			if err := json.Unmarshal(rec.Body.Bytes(), &apiError); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(apiError.FieldErrors[tt.field]) == 0 {
+				t.Errorf("expected an error for field %s, got %+v", tt.field, apiError)
+			}
+		})
+	}
+
+	if rec := serveAPIRequest(web, http.MethodPost, model.GetFullAPILink(), `{"Name":`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid JSON, got %d", rec.Code)
+	}
+	if len(orm.Created) != 0 {
+		t.Errorf("expected nothing to be created, got %v", orm.Created)
+	}
+}
+
+func TestModel_APIUpdate(t *testing.T) {
+	web, model, orm := newHTTPTestPanel(t, MockPermissionFunc, apiTestConfig, newAPITestBob())
+	store := model.App.Panel.Config.LogStore.(*logging.InMemoryLogStore)
+
+	rec := serveAPIRequest(web, http.MethodPatch, model.GetFullAPILink()+"/1", `{"Age": 31}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if updated := orm.Updated[1]; updated == nil || updated.Name != "Alice" || updated.Age != 31 {
+		t.Fatalf("expected a partial update keeping the name, got %v", updated)
+	}
+	var instance APIInstance
+	if err := json.Unmarshal(rec.Body.Bytes(), &instance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if instance.Fields["Age"] != float64(31) {
+		t.Errorf("expected the updated instance to be returned, got %+v", instance)
+	}
+
+	entries, _ := store.GetLogEntries()
+	if len(entries) != 1 || entries[0].ActionFlag != logging.LogStoreLevelUpdate || entries[0].Message != `{"Age":{"old":30,"new":31}}` {
+		t.Errorf("expected an update log entry with the diff, got %v", entries)
+	}
+
+	if rec = serveAPIRequest(web, http.MethodPatch, model.GetFullAPILink()+"/9", `{"Age": 1}`); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rec.Code)
+	}
+}
+
+func TestModel_APIDelete(t *testing.T) {
+	web, model, orm := newHTTPTestPanel(t, MockPermissionFunc, apiTestConfig, newAPITestBob())
+	store := model.App.Panel.Config.LogStore.(*logging.InMemoryLogStore)
+
+	rec := serveAPIRequest(web, http.MethodDelete, model.GetFullAPILink()+"/2", "")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if _, ok := orm.Existing[2]; ok {
+		t.Error("expected the instance to be deleted")
+	}
+	entries, _ := store.GetLogEntries()
+	if len(entries) != 1 || entries[0].ActionFlag != logging.LogStoreLevelDelete {
+		t.Errorf("expected a delete log entry, got %v", entries)
+	}
+}
+
+func TestModel_APIPermissions(t *testing.T) {
+	readOnly := func(request PermissionRequest, _ interface{}) (bool, error) {
+		return request.Action != nil && *request.Action == ReadAction, nil
+	}
+	web, model, orm := newHTTPTestPanel(t, readOnly, apiTestConfig, newAPITestBob())
+
+	if rec := serveAPIRequest(web, http.MethodGet, model.GetFullAPILink()+"/1", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected status 200 for a read, got %d", rec.Code)
+	}
+	requests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodPost, model.GetFullAPILink(), `{"Name": "Eve"}`},
+		{http.MethodPatch, model.GetFullAPILink() + "/1", `{"Age": 1}`},
+		{http.MethodDelete, model.GetFullAPILink() + "/1", ""},
+	}
+	for _, request := range requests {
+		if rec := serveAPIRequest(web, request.method, request.path, request.body); rec.Code != http.StatusForbidden {
+			t.Errorf("%s %s: expected status 403, got %d", request.method, request.path, rec.Code)
+		}
+	}
+	if len(orm.Created) != 0 || len(orm.Updated) != 0 || len(orm.Existing) != 2 {
+		t.Error("expected no changes without permission")
+	}
+}
+
+func TestModel_APIDisabled(t *testing.T) {
+	web, model, _ := newHTTPTestPanel(t, MockPermissionFunc, nil)
+
+	rec := serveAPIRequest(web, http.MethodGet, "/admin/api"+model.GetLink(), "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 when the API is disabled, got %d", rec.Code)
+	}
+}
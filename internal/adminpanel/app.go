@@ -73,6 +73,7 @@ func (a *App) RegisterModel(model interface{}, orm ORMIntegrator) (*Model, error
 	}
 
 	var fieldConfigs []FieldConfig
+	var listFilters []ListFilter
 	for i := 0; i < modelType.NumField(); i++ {
 		field := modelType.Field(i)
 		fieldName := field.Name
@@ -90,6 +91,12 @@ func (a *App) RegisterModel(model interface{}, orm ORMIntegrator) (*Model, error
 		includeInList := true
 		includeInFetch := true
 		includeInSearch := true
+		sortableTagPresent := false
+		listFilter := false
+		sensitive := false
+		exportTagPresent := false
+		includeInExport := true
+		sortable := true
 		includeInInstanceView := true
 		includeInAddForm := true
 		includeInEditForm := true
@@ -135,6 +142,40 @@ func (a *App) RegisterModel(model interface{}, orm ORMIntegrator) (*Model, error
 					} else {
 						return nil, fmt.Errorf("invalid value for 'search' tag: %s", value)
 					}
+				case "sortable":
+					sortableTagPresent = true
+					if value == "exclude" {
+						sortable = false
+					} else if value == "include" {
+						sortable = true
+					} else {
+						return nil, fmt.Errorf("invalid value for 'sortable' tag: %s", value)
+					}
+				case "export":
+					exportTagPresent = true
+					if value == "exclude" {
+						includeInExport = false
+					} else if value == "include" {
+						includeInExport = true
+					} else {
+						return nil, fmt.Errorf("invalid value for 'export' tag: %s", value)
+					}
+				case "filter":
+					if value == "" || value == "include" {
+						listFilter = true
+					} else if value == "exclude" {
+						listFilter = false
+					} else {
+						return nil, fmt.Errorf("invalid value for 'filter' tag: %s", value)
+					}
+				case "sensitive":
+					if value == "" || value == "include" {
+						sensitive = true
+					} else if value == "exclude" {
+						sensitive = false
+					} else {
+						return nil, fmt.Errorf("invalid value for 'sensitive' tag: %s", value)
+					}
 				case "view":
 					if value == "exclude" {
 						includeInInstanceView = false
@@ -171,6 +212,12 @@ func (a *App) RegisterModel(model interface{}, orm ORMIntegrator) (*Model, error
 				}
 			}
 		}
+		if !sortableTagPresent {
+			sortable = includeInList
+		}
+		if !exportTagPresent {
+			includeInExport = includeInList
+		}
 
 		var formField form.Field
 		if includeInAddForm || includeInEditForm {
@@ -369,7 +416,7 @@ func (a *App) RegisterModel(model interface{}, orm ORMIntegrator) (*Model, error
 			}
 		}
 
-		fieldConfigs = append(fieldConfigs, FieldConfig{
+		fieldConfig := FieldConfig{
 			Name:                  fieldName,
 			DisplayName:           fieldDisplayName,
 			FieldType:             underlyingType,
@@ -377,10 +424,22 @@ func (a *App) RegisterModel(model interface{}, orm ORMIntegrator) (*Model, error
 			IncludeInListDisplay:  includeInList,
 			IncludeInListFetch:    includeInFetch,
 			IncludeInSearch:       includeInSearch,
+			Sortable:              sortable,
+			IncludeInExport:       includeInExport,
 			IncludeInInstanceView: includeInInstanceView,
+			Sensitive:             sensitive,
 			AddFormField:          formAddField,
 			EditFormField:         formEditField,
-		})
+		}
+		fieldConfigs = append(fieldConfigs, fieldConfig)
+
+		if listFilter {
+			filter, err := newTagListFilter(fieldConfig)
+			if err != nil {
+				return nil, err
+			}
+			listFilters = append(listFilters, filter)
+		}
 	}
 
 	modelInstance := &Model{
@@ -391,13 +450,48 @@ func (a *App) RegisterModel(model interface{}, orm ORMIntegrator) (*Model, error
 		Fields:      fieldConfigs,
 		ORM:         orm,
 	}
-	a.Panel.Web.HandleRoute("GET", a.Panel.Config.GetPrefix()+modelInstance.GetLink(), modelInstance.GetViewHandler())
-	a.Panel.Web.HandleRoute("GET", a.Panel.Config.GetPrefix()+modelInstance.GetLink()+"/:id/view", modelInstance.GetInstanceViewHandler())
-	a.Panel.Web.HandleRoute("DELETE", a.Panel.Config.GetPrefix()+modelInstance.GetLink()+"/:id/view", modelInstance.GetInstanceDeleteHandler())
-	a.Panel.Web.HandleRoute("GET", a.Panel.Config.GetPrefix()+modelInstance.GetLink()+"/add", modelInstance.GetAddHandler())
-	a.Panel.Web.HandleRoute("POST", a.Panel.Config.GetPrefix()+modelInstance.GetLink()+"/add", modelInstance.GetAddHandler())
-	a.Panel.Web.HandleRoute("GET", a.Panel.Config.GetPrefix()+modelInstance.GetLink()+"/:id/edit", modelInstance.GetEditHandler())
-	a.Panel.Web.HandleRoute("POST", a.Panel.Config.GetPrefix()+modelInstance.GetLink()+"/:id/edit", modelInstance.GetEditHandler())
+	modelInstance.Actions = []*ModelAction{newDeleteSelectedAction()}
+
+	if filterer, ok := model.(AdminListFiltersInterface); ok {
+		for _, filter := range filterer.AdminListFilters() {
+			if _, exists := modelInstance.GetFieldConfig(filter.Field); !exists {
+				return nil, fmt.Errorf("list filter field '%s' not found in model '%s'", filter.Field, name)
+			}
+			for i := 0; i < len(listFilters); i++ {
+				if listFilters[i].Field == filter.Field {
+					listFilters = append(listFilters[:i], listFilters[i+1:]...)
+					i--
+				}
+			}
+			listFilters = append(listFilters, filter)
+		}
+	}
+	modelInstance.ListFilters = listFilters
+
+	if orderer, ok := model.(AdminModelDefaultOrderingInterface); ok {
+		defaultOrdering, err := parseDefaultOrdering(modelInstance, orderer.AdminDefaultOrdering())
+		if err != nil {
+			return nil, err
+		}
+		modelInstance.DefaultOrdering = defaultOrdering
+	}
+
+	a.Panel.HandleRoute("GET", a.Panel.Config.GetPrefix()+modelInstance.GetLink(), modelInstance.GetViewHandler())
+	a.Panel.HandleRoute("GET", a.Panel.Config.GetPrefix()+modelInstance.GetLink()+"/:id/view", modelInstance.GetInstanceViewHandler())
+	a.Panel.HandleRoute("DELETE", a.Panel.Config.GetPrefix()+modelInstance.GetLink()+"/:id/view", modelInstance.GetInstanceDeleteHandler())
+	a.Panel.HandleRoute("GET", a.Panel.Config.GetPrefix()+modelInstance.GetLink()+"/:id/history", modelInstance.GetInstanceHistoryHandler())
+	a.Panel.HandleResponseRoute("GET", a.Panel.Config.GetPrefix()+modelInstance.GetExportLink(), modelInstance.GetExportHandler())
+	a.Panel.HandleRoute("GET", a.Panel.Config.GetPrefix()+modelInstance.GetImportLink(), modelInstance.GetImportHandler())
+	a.Panel.HandleRoute("POST", a.Panel.Config.GetPrefix()+modelInstance.GetImportLink(), modelInstance.GetImportHandler())
+	a.Panel.HandleRoute("POST", a.Panel.Config.GetPrefix()+modelInstance.GetActionLink(), modelInstance.GetActionHandler())
+	a.Panel.HandleRoute("POST", a.Panel.Config.GetPrefix()+modelInstance.GetLink()+"/:id/action/:name", modelInstance.GetInstanceActionHandler())
+	a.Panel.HandleRoute("GET", a.Panel.Config.GetPrefix()+modelInstance.GetLink()+"/add", modelInstance.GetAddHandler())
+	a.Panel.HandleRoute("POST", a.Panel.Config.GetPrefix()+modelInstance.GetLink()+"/add", modelInstance.GetAddHandler())
+	a.Panel.HandleRoute("GET", a.Panel.Config.GetPrefix()+modelInstance.GetLink()+"/:id/edit", modelInstance.GetEditHandler())
+	a.Panel.HandleRoute("POST", a.Panel.Config.GetPrefix()+modelInstance.GetLink()+"/:id/edit", modelInstance.GetEditHandler())
+	if a.Panel.Config.APIPrefix != "" {
+		modelInstance.RegisterAPIRoutes()
+	}
 	a.ModelsSlice = append(a.ModelsSlice, modelInstance)
 	a.Models[name] = modelInstance
 	return modelInstance, nil
@@ -1,29 +1,70 @@
 package adminpanel
 
 import (
+	"context"
 	"fmt"
 	"github.com/go-advanced-admin/admin/internal/logging"
 	"github.com/google/uuid"
+	"log"
 	"time"
 )
 
 // AdminConfig holds configuration settings for the admin panel.
 type AdminConfig struct {
-	Name                    string
-	Prefix                  string
-	Renderer                TemplateRenderer
-	AssetsPrefix            string
-	GroupPrefix             string
-	DefaultInstancesPerPage uint
-	NavBarGenerators        []NavBarGenerator
-	UserFetcher             UserFetchFunction
-	LogStore                logging.LogStore
-	LogStoreLevel           logging.LogStoreLevel
+	Name                     string
+	Prefix                   string
+	Renderer                 TemplateRenderer
+	AssetsPrefix             string
+	GroupPrefix              string
+	APIPrefix                string
+	APIVersion               string
+	OpenAPIRoute             string
+	DefaultInstancesPerPage  uint
+	NavBarGenerators         []NavBarGenerator
+	UserFetcher              UserFetchFunction
+	ContextUserFetcher       ContextUserFetchFunction
+	ContextPermissionChecker ContextPermissionFunc
+	BatchPermissionChecker   BatchPermissionChecker
+	QueryScope               QueryScopeFunc
+	LogStore                 logging.LogStore
+	LogStoreLevel            logging.LogStoreLevel
+	LogStreamer              *logging.LogStreamer
+	LogFailurePolicy         LogFailurePolicy
+	OnLogError               LogErrorHook
+	LogRetryBuffer           *logging.RetryBuffer
+
+	requestContext func(data interface{}) context.Context
 }
 
+// LogFailurePolicy decides what happens when a log entry cannot be recorded, because the user cannot be fetched or the
+// log store fails.
+type LogFailurePolicy string
+
+const (
+	// LogFailOpen lets the request succeed and reports the failure to the OnLogError hook. It is the default.
+	LogFailOpen LogFailurePolicy = "fail_open"
+	// LogFailClosed fails the request when its log entry cannot be recorded.
+	LogFailClosed LogFailurePolicy = "fail_closed"
+	// LogBufferAndRetry hands the entries to the LogRetryBuffer, which inserts them in the background and retries
+	// those the log store fails to insert, so requests never wait for the store. Entries are streamed once inserted.
+	// The admin panel creates the buffer when it is not set; a buffer set in the configuration streams entries through
+	// its OnInsert option. AdminPanel.Close flushes and closes the buffer. Entries dropped because the buffer is full
+	// are reported to the OnLogError hook.
+	LogBufferAndRetry LogFailurePolicy = "buffer_and_retry"
+)
+
+// LogErrorHook receives the failures to record log entries that do not fail the request. The entry lacks the user when
+// fetching it failed.
+type LogErrorHook = func(entry *logging.LogEntry, err error)
+
 // UserFetchFunction defines a function type for fetching user information from the context.
 type UserFetchFunction = func(ctx interface{}) (userID interface{}, repr string, err error)
 
+// ContextUserFetchFunction is the context-aware variant of UserFetchFunction. It receives the context of the request,
+// from which RequestFromContext retrieves the request itself. Set it as AdminConfig.ContextUserFetcher, which takes
+// precedence over UserFetcher.
+type ContextUserFetchFunction = func(ctx context.Context) (userID interface{}, repr string, err error)
+
 // DefaultAdminConfig provides default configuration settings for the admin panel.
 var DefaultAdminConfig = NewDefaultAdminConfig()
 
@@ -48,36 +89,125 @@ func NewDefaultAdminConfig() *AdminConfig {
 	}
 }
 
-// CreateLog creates a log entry using the admin panel's log store.
+// CreateLog creates a log entry using the admin panel's log store. Failures are handled according to the
+// LogFailurePolicy; only LogFailClosed returns them.
 func (c *AdminConfig) CreateLog(ctx interface{}, action logging.LogStoreLevel, contentType string, objectID interface{}, objectRepr string, message string) error {
-	if !c.LogStoreLevel.AssessLevel(action) {
-		return nil
+	logEntry, err := c.newLogEntry(ctx, action, contentType, objectID, objectRepr, message)
+	if logEntry == nil || err != nil {
+		return err
 	}
+	status, err := c.insertLogEntry(context.WithoutCancel(c.getContext(ctx)), nil, logEntry)
+	if status == logRecorded {
+		c.streamLogEntry(logEntry)
+	}
+	return err
+}
 
-	var userId interface{}
-	var userRepr string
-	var err error
-	if c.UserFetcher != nil {
-		userId, userRepr, err = c.UserFetcher(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to fetch user: %w", err)
-		}
+// getContext returns the context of the request. Outside an admin panel, it is context.Background() carrying the
+// request.
+func (c *AdminConfig) getContext(data interface{}) context.Context {
+	if c.requestContext != nil {
+		return c.requestContext(data)
+	}
+	return context.WithValue(context.Background(), requestContextKey{}, data)
+}
+
+func (c *AdminConfig) fetchUser(ctx interface{}) (interface{}, string, error) {
+	if c.ContextUserFetcher != nil {
+		return c.ContextUserFetcher(c.getContext(ctx))
 	}
-	logEntry := logging.LogEntry{
+	return c.UserFetcher(ctx)
+}
+
+func (c *AdminConfig) newLogEntry(ctx interface{}, action logging.LogStoreLevel, contentType string, objectID interface{}, objectRepr string, message string) (*logging.LogEntry, error) {
+	if !c.LogStoreLevel.AssessLevel(action) {
+		return nil, nil
+	}
+
+	logEntry := &logging.LogEntry{
 		ID:          uuid.New(),
 		ActionTime:  time.Now(),
-		UserID:      userId,
-		UserRepr:    userRepr,
 		ActionFlag:  action,
 		ContentType: contentType,
 		ObjectID:    objectID,
 		ObjectRepr:  objectRepr,
 		Message:     message,
 	}
+	if c.UserFetcher != nil || c.ContextUserFetcher != nil {
+		userId, userRepr, err := c.fetchUser(ctx)
+		if err != nil {
+			err = fmt.Errorf("failed to fetch user: %w", err)
+			if c.LogFailurePolicy == LogFailClosed {
+				return nil, err
+			}
+			c.reportLogError(logEntry, err)
+		} else {
+			logEntry.UserID = userId
+			logEntry.UserRepr = userRepr
+		}
+	}
+	return logEntry, nil
+}
 
-	err = c.LogStore.InsertLogEntry(&logEntry)
+// InsertLogEntry records the entry in the log store and streams it, handling failures according to the
+// LogFailurePolicy.
+func (c *AdminConfig) InsertLogEntry(entry *logging.LogEntry) error {
+	status, err := c.insertLogEntry(context.Background(), nil, entry)
+	if status == logRecorded {
+		c.streamLogEntry(entry)
+	}
+	return err
+}
 
-	return nil
+// logInsertStatus is the outcome of recording a log entry.
+type logInsertStatus int
+
+const (
+	// logNotRecorded means the entry could not be recorded.
+	logNotRecorded logInsertStatus = iota
+	// logBuffered means the entry waits in the LogRetryBuffer, which streams it once the log store inserted it.
+	logBuffered
+	// logRecorded means the log store inserted the entry.
+	logRecorded
+)
+
+// insertLogEntry records the entry in store, or in the log store when store is nil, and reports whether it was
+// recorded or only buffered.
+func (c *AdminConfig) insertLogEntry(ctx context.Context, store logging.LogStore, entry *logging.LogEntry) (logInsertStatus, error) {
+	var err error
+	status := logRecorded
+	if store == nil && c.LogFailurePolicy == LogBufferAndRetry && c.LogRetryBuffer != nil {
+		err = c.LogRetryBuffer.InsertLogEntryContext(ctx, entry)
+		status = logBuffered
+	} else {
+		if store == nil {
+			store = c.LogStore
+		}
+		err = logging.AdaptLogStore(store).InsertLogEntryContext(ctx, entry)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to record log entry: %w", err)
+		if c.LogFailurePolicy == LogFailClosed {
+			return logNotRecorded, err
+		}
+		c.reportLogError(entry, err)
+		return logNotRecorded, nil
+	}
+	return status, nil
+}
+
+func (c *AdminConfig) streamLogEntry(entry *logging.LogEntry) {
+	if c.LogStreamer != nil {
+		c.LogStreamer.Stream(entry)
+	}
+}
+
+func (c *AdminConfig) reportLogError(entry *logging.LogEntry, err error) {
+	if c.OnLogError != nil {
+		c.OnLogError(entry, err)
+		return
+	}
+	log.Printf("admin: %v (%s %s %v)", err, entry.ActionFlag, entry.ContentType, entry.ObjectID)
 }
 
 // GetPrefix returns the URL prefix for the admin panel.
@@ -96,6 +226,32 @@ func (c *AdminConfig) GetAssetsPrefix() string {
 	return "/" + c.AssetsPrefix
 }
 
+// GetAPIPrefix returns the URL prefix of the JSON API, relative to the admin prefix. It is empty when the API is
+// disabled.
+func (c *AdminConfig) GetAPIPrefix() string {
+	if c.APIPrefix == "" {
+		return ""
+	}
+	return "/" + c.APIPrefix
+}
+
+// GetAPIVersion returns the version of the JSON API reported in the OpenAPI document, defaulting to 1.0.0.
+func (c *AdminConfig) GetAPIVersion() string {
+	if c.APIVersion == "" {
+		return "1.0.0"
+	}
+	return c.APIVersion
+}
+
+// GetOpenAPIRoute returns the URL path of the OpenAPI document, relative to the admin prefix. It is empty when the
+// document is not served.
+func (c *AdminConfig) GetOpenAPIRoute() string {
+	if c.OpenAPIRoute == "" {
+		return ""
+	}
+	return "/" + c.OpenAPIRoute
+}
+
 // GetLink constructs a full link by combining the group prefix, admin prefix, and the provided link.
 func (c *AdminConfig) GetLink(link string) string {
 	return c.GroupPrefix + c.GetPrefix() + link
@@ -1,6 +1,10 @@
 package adminpanel
 
 import (
+	"bytes"
+	"errors"
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"strings"
 	"testing"
 )
 
@@ -87,3 +91,89 @@ func TestAdminConfig_GetAssetLink(t *testing.T) {
 		})
 	}
 }
+
+func TestAdminConfig_CreateLog_Streams(t *testing.T) {
+	var buffer bytes.Buffer
+	config := NewDefaultAdminConfig()
+	config.LogStore = logging.NewInMemoryLogStore(10)
+	config.LogStoreLevel = logging.LogStoreLevelCreate
+	config.LogStreamer = logging.NewLogStreamer(logging.LogStreamerOptions{}, logging.NewWriterSink(&buffer))
+
+	if err := config.CreateLog(nil, logging.LogStoreLevelCreate, "Shop | Orders", 1, "Order 1", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := config.CreateLog(nil, logging.LogStoreLevelListView, "Shop | Orders", nil, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = config.LogStreamer.Close()
+
+	if lines := strings.Count(buffer.String(), "\n"); lines != 1 || !strings.Contains(buffer.String(), `"ObjectRepr":"Order 1"`) {
+		t.Errorf("expected only the stored entry to be streamed, got %q", buffer.String())
+	}
+}
+
+type failingLogStore struct {
+	*logging.InMemoryLogStore
+	failing bool
+}
+
+func (s *failingLogStore) InsertLogEntry(entry *logging.LogEntry) error {
+	if s.failing {
+		return errors.New("store unavailable")
+	}
+	return s.InMemoryLogStore.InsertLogEntry(entry)
+}
+
+func TestAdminConfig_CreateLog_FailurePolicy(t *testing.T) {
+	failingUser := func(interface{}) (interface{}, string, error) { return nil, "", errors.New("no session") }
+
+	tests := []struct {
+		name        string
+		policy      LogFailurePolicy
+		storeFails  bool
+		userFails   bool
+		expectErr   bool
+		expectHook  bool
+		expectStore int
+	}{
+		{"OpenStore", LogFailOpen, true, false, false, true, 0},
+		{"OpenUser", "", false, true, false, true, 1},
+		{"ClosedStore", LogFailClosed, true, false, true, false, 0},
+		{"ClosedUser", LogFailClosed, false, true, true, false, 0},
+		{"BufferStore", LogBufferAndRetry, true, false, false, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &failingLogStore{InMemoryLogStore: logging.NewInMemoryLogStore(10), failing: tt.storeFails}
+			config := NewDefaultAdminConfig()
+			config.LogStore = store
+			config.LogStoreLevel = logging.LogStoreLevelCreate
+			config.LogFailurePolicy = tt.policy
+			var hookErr error
+			config.OnLogError = func(_ *logging.LogEntry, err error) { hookErr = err }
+			if tt.userFails {
+				config.UserFetcher = failingUser
+			}
+			if tt.policy == LogBufferAndRetry {
+				config.LogRetryBuffer = logging.NewRetryBuffer(store, logging.RetryBufferOptions{})
+				defer func() { _ = config.LogRetryBuffer.Close() }()
+			}
+
+			err := config.CreateLog(nil, logging.LogStoreLevelCreate, "Shop | Orders", 1, "Order 1", "")
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if (hookErr != nil) != tt.expectHook {
+				t.Errorf("expected the hook to be called %v, got %v", tt.expectHook, hookErr)
+			}
+
+			store.failing = false
+			if config.LogRetryBuffer != nil {
+				_ = config.LogRetryBuffer.Flush()
+			}
+			if entries, _ := store.GetLogEntries(); len(entries) != tt.expectStore {
+				t.Errorf("expected %d stored entries, got %d", tt.expectStore, len(entries))
+			}
+		})
+	}
+}
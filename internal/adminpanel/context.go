@@ -0,0 +1,33 @@
+package adminpanel
+
+import "context"
+
+type requestContextKey struct{}
+
+// GetContext returns the context.Context of the request: the one provided by the web integrator when it implements
+// ContextWebIntegrator, or context.Background() otherwise. The context carries the request itself, which
+// RequestFromContext retrieves.
+func (ap *AdminPanel) GetContext(data interface{}) context.Context {
+	ctx := context.Background()
+	if web, ok := ap.Web.(ContextWebIntegrator); ok {
+		if requestCtx := web.GetContext(data); requestCtx != nil {
+			ctx = requestCtx
+		}
+	}
+	return context.WithValue(ctx, requestContextKey{}, data)
+}
+
+// RequestFromContext returns the request the context was derived from by AdminPanel.GetContext, as passed to the
+// handlers by the web integrator. Context-aware permission and user fetch functions use it to reach the request object
+// of their web framework.
+func RequestFromContext(ctx context.Context) interface{} {
+	return ctx.Value(requestContextKey{})
+}
+
+// adaptContextPermissionFunc adapts a context-aware permission function to a PermissionFunc evaluating it with the
+// context of the request.
+func (ap *AdminPanel) adaptContextPermissionFunc(fn ContextPermissionFunc) PermissionFunc {
+	return func(request PermissionRequest, data interface{}) (bool, error) {
+		return fn(ap.GetContext(data), request)
+	}
+}
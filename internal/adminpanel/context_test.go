@@ -0,0 +1,108 @@
+package adminpanel
+
+import (
+	"context"
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testContextKey struct{}
+
+type ContextImportORMIntegrator struct {
+	*ImportORMIntegrator
+	Contexts []context.Context
+}
+
+func (o *ContextImportORMIntegrator) WithContext(ctx context.Context) ORMIntegrator {
+	o.Contexts = append(o.Contexts, ctx)
+	return o.ImportORMIntegrator
+}
+
+func newContextTestRequest(method, target string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	return req.WithContext(context.WithValue(req.Context(), testContextKey{}, "trace"))
+}
+
+func TestAdminPanel_GetContext(t *testing.T) {
+	web, model, _ := newHTTPTestPanel(t, MockPermissionFunc, nil)
+	data := &HTTPContext{Request: newContextTestRequest(http.MethodGet, "/")}
+
+	ctx := model.App.Panel.GetContext(data)
+	if ctx.Value(testContextKey{}) != "trace" || RequestFromContext(ctx) != data {
+		t.Errorf("expected the context of the request carrying the request, got %v", ctx)
+	}
+	if web.GetContext("unknown") != nil {
+		t.Error("expected no context for an unknown request")
+	}
+
+	panel, _ := NewMockAdminPanel()
+	if ctx = panel.GetContext("request"); ctx.Err() != nil || RequestFromContext(ctx) != "request" {
+		t.Errorf("expected a background context carrying the request, got %v", ctx)
+	}
+}
+
+func TestModel_ContextORMIntegrator(t *testing.T) {
+	web, model, orm := newHTTPTestPanel(t, MockPermissionFunc, nil)
+	contextORM := &ContextImportORMIntegrator{ImportORMIntegrator: orm}
+	model.ORM = contextORM
+
+	rec := httptest.NewRecorder()
+	web.ServeHTTP(rec, newContextTestRequest(http.MethodGet, model.GetFullLink()+"/1/view"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(contextORM.Contexts) == 0 {
+		t.Fatal("expected the integrator to be bound to the request context")
+	}
+	for _, ctx := range contextORM.Contexts {
+		if ctx.Value(testContextKey{}) != "trace" {
+			t.Errorf("expected every query to run with the request context, got %v", ctx)
+		}
+	}
+}
+
+func TestNewAdminPanel_ContextPermissionChecker(t *testing.T) {
+	config := NewDefaultAdminConfig()
+	config.ContextPermissionChecker = func(ctx context.Context, _ PermissionRequest) (bool, error) {
+		return RequestFromContext(ctx) == "alice", nil
+	}
+	panel, err := NewAdminPanel(&MockORMIntegrator{}, &MockWebIntegrator{}, nil, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if allowed, _ := panel.PermissionChecker.HasReadPermission("alice"); !allowed {
+		t.Error("expected the context permission checker to allow alice")
+	}
+	if allowed, _ := panel.PermissionChecker.HasReadPermission("bob"); allowed {
+		t.Error("expected the context permission checker to deny bob")
+	}
+
+	if _, err = NewAdminPanel(&MockORMIntegrator{}, &MockWebIntegrator{}, nil, NewDefaultAdminConfig()); err == nil {
+		t.Error("expected an error without any permission function")
+	}
+}
+
+func TestAdminConfig_CreateLog_ContextUserFetcher(t *testing.T) {
+	_, model, _ := newHTTPTestPanel(t, MockPermissionFunc, nil)
+	config := &model.App.Panel.Config
+	store := logging.NewInMemoryLogStore(10)
+	config.LogStore = store
+	config.LogStoreLevel = logging.LogStoreLevelCreate
+	config.ContextUserFetcher = func(ctx context.Context) (interface{}, string, error) {
+		return uint(1), ctx.Value(testContextKey{}).(string), nil
+	}
+
+	req, cancel := context.WithCancel(context.Background())
+	cancel()
+	data := &HTTPContext{Request: newContextTestRequest(http.MethodPost, "/").WithContext(context.WithValue(req, testContextKey{}, "alice"))}
+	if err := config.CreateLog(data, logging.LogStoreLevelCreate, "TestApp | Test", 1, "Test 1", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries, _ := store.GetLogEntries()
+	if len(entries) != 1 || entries[0].UserRepr != "alice" {
+		t.Errorf("expected the entry to be recorded despite the cancelled request, got %v", entries)
+	}
+}
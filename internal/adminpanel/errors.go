@@ -1,9 +1,20 @@
 package adminpanel
 
 import (
+	"errors"
 	"fmt"
 )
 
+// ErrFieldNotWritable is returned when a request sets a field the user is not allowed to update.
+var ErrFieldNotWritable = errors.New("you are not allowed to update this field")
+
+// ErrOutOfScope is returned when an instance does not exist within the query scope of the user, or an update would
+// move it outside that scope.
+var ErrOutOfScope = errors.New("instance not found")
+
+// ErrRequestTooLarge is returned when the body of a request exceeds the size accepted by the web integrator.
+var ErrRequestTooLarge = errors.New("request body too large")
+
 // GetErrorHTML generates an HTML string representing an error message with the given code and error.
 func GetErrorHTML(code uint, err error) (uint, string) {
 	if err == nil {
@@ -0,0 +1,369 @@
+package adminpanel
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"github.com/go-advanced-admin/admin/internal/utils"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFormat represents a file format model instances can be exported to or imported from.
+type ExportFormat string
+
+const (
+	// ExportFormatCSV exports instances as comma separated values with a header row.
+	ExportFormatCSV ExportFormat = "csv"
+	// ExportFormatJSON exports instances as a JSON array of objects.
+	ExportFormatJSON ExportFormat = "json"
+	// ExportFormatJSONL exports instances as one JSON object per line.
+	ExportFormatJSONL ExportFormat = "jsonl"
+)
+
+// ExportFormats lists the supported export formats.
+var ExportFormats = []ExportFormat{ExportFormatCSV, ExportFormatJSON, ExportFormatJSONL}
+
+// ExportBatchSize is the number of instances fetched at once while exporting.
+const ExportBatchSize uint = 500
+
+// ExportLink represents a link to export the model list in a given format.
+type ExportLink struct {
+	Format ExportFormat
+	Link   string
+}
+
+// ContentType returns the MIME type of the export format.
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case ExportFormatJSON:
+		return "application/json"
+	case ExportFormatJSONL:
+		return "application/jsonl"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// ParseExportFormat returns the export format with the given name. An empty name defaults to CSV.
+func ParseExportFormat(name string) (ExportFormat, error) {
+	if name == "" {
+		return ExportFormatCSV, nil
+	}
+	for _, format := range ExportFormats {
+		if string(format) == strings.ToLower(name) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported export format '%s'", name)
+}
+
+type instanceExporter interface {
+	WriteHeader(fields []FieldConfig) error
+	WriteInstance(fields []FieldConfig, instance interface{}) error
+	Flush() error
+	Close() error
+}
+
+// GetExportFields returns the fields included in exports. Sensitive fields are never exported.
+func (m *Model) GetExportFields() []FieldConfig {
+	exportFields := make([]FieldConfig, 0)
+	for _, fieldConfig := range m.Fields {
+		if fieldConfig.IncludeInExport && !fieldConfig.Sensitive {
+			exportFields = append(exportFields, fieldConfig)
+		}
+	}
+	return exportFields
+}
+
+// ExportInstances writes the instances matching the query to w in the given format, one batch at a time. Instances
+// the user may not read are skipped, and fields the user may not read on every instance are left out. It returns the
+// number of exported instances.
+func (m *Model) ExportInstances(w io.Writer, format ExportFormat, query InstancesQuery, data interface{}) (uint, error) {
+	var exporter instanceExporter
+	switch format {
+	case ExportFormatCSV:
+		exporter = &csvExporter{writer: csv.NewWriter(w)}
+	case ExportFormatJSON:
+		exporter = &jsonExporter{writer: w, array: true}
+	case ExportFormatJSONL:
+		exporter = &jsonExporter{writer: w}
+	default:
+		return 0, fmt.Errorf("unsupported export format '%s'", format)
+	}
+
+	exportFields, err := m.FilterReadableFields(m.GetExportFields(), nil, data)
+	if err != nil {
+		return 0, err
+	}
+	for _, fieldConfig := range exportFields {
+		if !utils.ContainsString(query.Fields, fieldConfig.Name) {
+			query.Fields = append(query.Fields, fieldConfig.Name)
+		}
+	}
+
+	if err := exporter.WriteHeader(exportFields); err != nil {
+		return 0, err
+	}
+
+	var count uint
+	err = m.ForEachInstanceBatch(query, ExportBatchSize, func(instances []interface{}) error {
+		ids := make([]interface{}, len(instances))
+		requests := make([]PermissionRequest, 0, len(instances))
+		for i, instance := range instances {
+			if isNilInstance(instance) {
+				continue
+			}
+			id, err := m.GetPrimaryKeyValue(instance)
+			if err != nil {
+				return err
+			}
+			ids[i] = id
+			requests = append(requests, newModelPermissionRequests(m.App.Name, m.Name, id, ReadAction)...)
+		}
+		if err := m.App.Panel.PreloadPermissions(requests, data); err != nil {
+			return err
+		}
+
+		for i, instance := range instances {
+			if isNilInstance(instance) {
+				continue
+			}
+			allowed, err := m.App.Panel.PermissionChecker.HasInstanceReadPermission(m.App.Name, m.Name, ids[i], data)
+			if err != nil {
+				return err
+			}
+			if !allowed {
+				continue
+			}
+			count++
+			if err = exporter.WriteInstance(exportFields, instance); err != nil {
+				return err
+			}
+		}
+		return exporter.Flush()
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, exporter.Close()
+}
+
+type csvExporter struct {
+	writer *csv.Writer
+}
+
+func (e *csvExporter) WriteHeader(fields []FieldConfig) error {
+	header := make([]string, len(fields))
+	for i, fieldConfig := range fields {
+		header[i] = fieldConfig.DisplayName
+	}
+	return e.writer.Write(header)
+}
+
+func (e *csvExporter) WriteInstance(fields []FieldConfig, instance interface{}) error {
+	record := make([]string, len(fields))
+	for i, fieldConfig := range fields {
+		value, err := utils.GetFieldValue(instance, fieldConfig.Name)
+		if err != nil {
+			return err
+		}
+		record[i] = escapeCSVFormula(formatExportValue(value))
+	}
+	return e.writer.Write(record)
+}
+
+func (e *csvExporter) Flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *csvExporter) Close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+type jsonExporter struct {
+	writer  io.Writer
+	array   bool
+	written bool
+}
+
+func (e *jsonExporter) WriteHeader([]FieldConfig) error {
+	if e.array {
+		_, err := io.WriteString(e.writer, "[")
+		return err
+	}
+	return nil
+}
+
+func (e *jsonExporter) WriteInstance(fields []FieldConfig, instance interface{}) error {
+	var builder strings.Builder
+	if e.array {
+		if e.written {
+			builder.WriteString(",")
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString("{")
+	for i, fieldConfig := range fields {
+		value, err := utils.GetFieldValue(instance, fieldConfig.Name)
+		if err != nil {
+			return err
+		}
+		key, err := json.Marshal(fieldConfig.Name)
+		if err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.Write(key)
+		builder.WriteString(":")
+		builder.Write(encoded)
+	}
+	builder.WriteString("}")
+	if !e.array {
+		builder.WriteString("\n")
+	}
+	e.written = true
+	_, err := io.WriteString(e.writer, builder.String())
+	return err
+}
+
+func (e *jsonExporter) Flush() error {
+	return nil
+}
+
+func (e *jsonExporter) Close() error {
+	if e.array {
+		_, err := io.WriteString(e.writer, "\n]\n")
+		return err
+	}
+	return nil
+}
+
+// escapeCSVFormula prefixes cells spreadsheet applications would evaluate as formulas with a quote, so exported values
+// cannot run formulas when the file is opened. Numbers are left as they are.
+func escapeCSVFormula(cell string) string {
+	if cell == "" || !strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return cell
+	}
+	if _, err := strconv.ParseFloat(cell, 64); err == nil {
+		return cell
+	}
+	return "'" + cell
+}
+
+func formatExportValue(value interface{}) string {
+	val := reflect.ValueOf(value)
+	for val.IsValid() && val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return ""
+		}
+		val = val.Elem()
+	}
+	if !val.IsValid() {
+		return ""
+	}
+	if t, ok := val.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprint(val.Interface())
+}
+
+// GetExportLink returns the relative URL path to export the model's instances.
+func (m *Model) GetExportLink() string {
+	return fmt.Sprintf("%s/export", m.GetLink())
+}
+
+// GetFullExportLink returns the full URL path to export the model's instances.
+func (m *Model) GetFullExportLink() string {
+	return m.App.Panel.Config.GetLink(m.GetExportLink())
+}
+
+// GetExportLinks returns a link per export format. The given query parameters are preserved in the links.
+func (m *Model) GetExportLinks(params url.Values) []ExportLink {
+	links := make([]ExportLink, 0, len(ExportFormats))
+	for _, format := range ExportFormats {
+		linkParams := url.Values{}
+		for key, values := range params {
+			linkParams[key] = values
+		}
+		linkParams.Del("page")
+		linkParams.Del("perPage")
+		linkParams.Set("format", string(format))
+		links = append(links, ExportLink{Format: format, Link: fmt.Sprintf("%s?%s", m.GetFullExportLink(), linkParams.Encode())})
+	}
+	return links
+}
+
+// CreateExportLog creates a log entry when the model's instances are exported, recording the format along with the
+// search and filters selecting the exported instances. It is created before the export starts streaming, so a failure
+// to record it can still be reported to the client.
+func (m *Model) CreateExportLog(ctx interface{}, format ExportFormat, search string, filterValues map[string]string) error {
+	message, err := json.Marshal(map[string]interface{}{"format": format, "search": search, "filters": filterValues})
+	if err != nil {
+		return err
+	}
+	return m.App.Panel.Config.CreateLog(ctx, logging.LogStoreLevelExport, m.GetLogContentType(), nil, "", string(message))
+}
+
+// GetExportFileName returns the name of the file the model's instances are exported to in the given format.
+func (m *Model) GetExportFileName(format ExportFormat) string {
+	return fmt.Sprintf("%s-%s.%s", m.App.Name, m.Name, format)
+}
+
+// GetExportHandler returns the HTTP handler function streaming the model's instances matching the request's
+// search, filters and ordering as a file download.
+func (m *Model) GetExportHandler() ResponseHandlerFunc {
+	return func(data interface{}) *Response {
+		m := m.withRequestContext(data)
+
+		allowed, err := m.App.Panel.PermissionChecker.HasModelReadPermission(m.App.Name, m.Name, data)
+		if err != nil {
+			return NewResponseFromResult(GetErrorHTML(http.StatusInternalServerError, err))
+		}
+		if !allowed {
+			return NewResponseFromResult(GetErrorHTML(http.StatusForbidden, fmt.Errorf("forbidden")))
+		}
+
+		format, err := ParseExportFormat(m.App.Panel.Web.GetQueryParam(data, "format"))
+		if err != nil {
+			return NewResponseFromResult(GetErrorHTML(http.StatusBadRequest, err))
+		}
+
+		var fieldsToFetch []string
+		for _, fieldConfig := range m.Fields {
+			if fieldConfig.IncludeInListFetch {
+				fieldsToFetch = append(fieldsToFetch, fieldConfig.Name)
+			}
+		}
+		query, filterValues, err := m.GetScopedListQuery(data, fieldsToFetch)
+		if err != nil {
+			return NewResponseFromResult(GetErrorHTML(http.StatusInternalServerError, err))
+		}
+		if err = m.CreateExportLog(data, format, query.Search, filterValues); err != nil {
+			return NewResponseFromResult(GetErrorHTML(http.StatusInternalServerError, err))
+		}
+
+		response := NewStreamResponse(format.ContentType(), func(w io.Writer) error {
+			_, err := m.ExportInstances(w, format, query, data)
+			return err
+		})
+		response.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, m.GetExportFileName(format)))
+		return response
+	}
+}
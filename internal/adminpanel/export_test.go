@@ -0,0 +1,154 @@
+package adminpanel
+
+import (
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type ExportTestModel struct {
+	ID     uint
+	Name   string
+	Note   *string
+	Secret string `admin:"export:exclude"`
+	Token  string `admin:"sensitive"`
+}
+
+type ExportORMIntegrator struct {
+	MockORMIntegrator
+	Instances []*ExportTestModel
+}
+
+func (o *ExportORMIntegrator) GetPrimaryKeyValue(instance interface{}) (interface{}, error) {
+	return instance.(*ExportTestModel).ID, nil
+}
+
+func (o *ExportORMIntegrator) FetchInstancesOnlyFields(interface{}, []string) (interface{}, error) {
+	return o.Instances, nil
+}
+
+// newExportTestORM returns an ORM integrator holding two export test instances.
+func newExportTestORM() *ExportORMIntegrator {
+	note := "a \"quoted\", note"
+	return &ExportORMIntegrator{Instances: []*ExportTestModel{
+		{ID: 1, Name: "First", Note: &note, Secret: "s1", Token: "t1"},
+		{ID: 2, Name: "Second", Secret: "s2", Token: "t2"},
+	}}
+}
+
+func TestModel_ExportInstances(t *testing.T) {
+	tests := []struct {
+		format   ExportFormat
+		expected string
+	}{
+		{ExportFormatCSV, "ID,Name,Note\n1,First,\"a \"\"quoted\"\", note\"\n2,Second,\n"},
+		{ExportFormatJSON, "[\n{\"ID\":1,\"Name\":\"First\",\"Note\":\"a \\\"quoted\\\", note\"},\n{\"ID\":2,\"Name\":\"Second\",\"Note\":null}\n]\n"},
+		{ExportFormatJSONL, "{\"ID\":1,\"Name\":\"First\",\"Note\":\"a \\\"quoted\\\", note\"}\n{\"ID\":2,\"Name\":\"Second\",\"Note\":null}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			model := newTestModel(t, &ExportTestModel{}, newExportTestORM())
+			var builder strings.Builder
+			count, err := model.ExportInstances(&builder, tt.format, InstancesQuery{}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if count != 2 {
+				t.Errorf("expected 2 exported instances, got %d", count)
+			}
+			if builder.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, builder.String())
+			}
+		})
+	}
+
+	t.Run("InstancePermissions", func(t *testing.T) {
+		model := newTestModel(t, &ExportTestModel{}, newExportTestORM())
+		model.App.Panel.PermissionChecker = func(req PermissionRequest, _ interface{}) (bool, error) {
+			return req.InstanceID != uint(2), nil
+		}
+		var builder strings.Builder
+		count, err := model.ExportInstances(&builder, ExportFormatCSV, InstancesQuery{}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if count != 1 || strings.Contains(builder.String(), "Second") {
+			t.Errorf("expected only the first instance to be exported, got %q", builder.String())
+		}
+	})
+}
+
+func TestModel_ExportInstances_CSVFormulas(t *testing.T) {
+	note := "-1"
+	orm := &ExportORMIntegrator{Instances: []*ExportTestModel{
+		{ID: 1, Name: "=HYPERLINK(\"x\")", Note: &note},
+		{ID: 2, Name: "@SUM(A1)"},
+		{ID: 3, Name: "+1+cmd"},
+	}}
+	model := newTestModel(t, &ExportTestModel{}, orm)
+
+	var builder strings.Builder
+	if _, err := model.ExportInstances(&builder, ExportFormatCSV, InstancesQuery{}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "ID,Name,Note\n1,\"'=HYPERLINK(\"\"x\"\")\",-1\n2,'@SUM(A1),\n3,'+1+cmd,\n"
+	if builder.String() != expected {
+		t.Errorf("expected %q, got %q", expected, builder.String())
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	if format, err := ParseExportFormat(""); err != nil || format != ExportFormatCSV {
+		t.Errorf("expected csv by default, got %s, %v", format, err)
+	}
+	if format, err := ParseExportFormat("JSONL"); err != nil || format != ExportFormatJSONL {
+		t.Errorf("expected jsonl, got %s, %v", format, err)
+	}
+	if _, err := ParseExportFormat("xml"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestModel_GetExportHandler(t *testing.T) {
+	model := newTestModel(t, &ExportTestModel{}, newExportTestORM())
+	store := logging.NewInMemoryLogStore(100)
+	model.App.Panel.Config.LogStore = store
+
+	response := model.GetExportHandler()(map[string]string{"format": "jsonl", "ordering": "-ID"})
+	if response.ContentType != ExportFormatJSONL.ContentType() {
+		t.Errorf("expected content type %q, got %q", ExportFormatJSONL.ContentType(), response.ContentType)
+	}
+	if disposition := response.Header().Get("Content-Disposition"); !strings.Contains(disposition, "attachment") {
+		t.Errorf("expected an attachment disposition, got %q", disposition)
+	}
+	code, body := response.ToResult()
+	if code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", code, body)
+	}
+	if !strings.HasPrefix(body, `{"ID":2`) {
+		t.Errorf("expected export to follow the requested ordering, got %q", body)
+	}
+
+	entries, _ := store.GetLogEntries()
+	if len(entries) != 1 || entries[0].ActionFlag != logging.LogStoreLevelExport {
+		t.Errorf("expected one export log entry, got %v", entries)
+	}
+
+	code, _ = model.GetExportHandler()(map[string]string{"format": "xml"}).ToResult()
+	if code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", code)
+	}
+}
+
+func TestModel_GetExportHandler_LogFailClosed(t *testing.T) {
+	model := newTestModel(t, &ExportTestModel{}, newExportTestORM())
+	model.App.Panel.Config.LogStore = &failingLogStore{InMemoryLogStore: logging.NewInMemoryLogStore(10), failing: true}
+	model.App.Panel.Config.LogFailurePolicy = LogFailClosed
+
+	code, body := model.GetExportHandler()(map[string]string{"format": "csv"}).ToResult()
+	if code != http.StatusInternalServerError || strings.Contains(body, "First") {
+		t.Errorf("expected the export to fail before streaming, got %d: %q", code, body)
+	}
+}
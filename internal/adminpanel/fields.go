@@ -14,7 +14,10 @@ type FieldConfig struct {
 	IncludeInListFetch    bool
 	IncludeInListDisplay  bool
 	IncludeInSearch       bool
+	Sortable              bool
+	IncludeInExport       bool
 	IncludeInInstanceView bool
+	Sensitive             bool
 	AddFormField          form.Field
 	EditFormField         form.Field
 }
@@ -24,3 +27,60 @@ type AdminFormFieldInterface interface {
 	// AdminFormField returns a custom form field for the given field name and operation (isEdit).
 	AdminFormField(name string, isEdit bool) form.Field
 }
+
+// FilterReadableFields returns the given fields the user may read. A nil instance ID checks the fields of every
+// instance of the model, as done by the list and export views.
+func (m *Model) FilterReadableFields(fields []FieldConfig, instanceID interface{}, data interface{}) ([]FieldConfig, error) {
+	readable := make([]FieldConfig, 0, len(fields))
+	requests := make([]PermissionRequest, 0, len(fields))
+	for _, fieldConfig := range fields {
+		requests = append(requests, m.newFieldPermissionRequests(instanceID, fieldConfig.Name, ReadAction)...)
+	}
+	if err := m.App.Panel.PreloadPermissions(requests, data); err != nil {
+		return nil, err
+	}
+
+	for _, fieldConfig := range fields {
+		allowed, err := m.App.Panel.PermissionChecker.HasFieldReadPermission(m.App.Name, m.Name, instanceID, fieldConfig.Name, data)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			readable = append(readable, fieldConfig)
+		}
+	}
+	return readable, nil
+}
+
+// GetFieldPermissions returns the read and update permissions of every field of the instance, keyed by field name.
+func (m *Model) GetFieldPermissions(instanceID interface{}, data interface{}) (map[string]Permissions, error) {
+	permissions := make(map[string]Permissions, len(m.Fields))
+	requests := make([]PermissionRequest, 0, len(m.Fields)*2)
+	for _, fieldConfig := range m.Fields {
+		requests = append(requests, m.newFieldPermissionRequests(instanceID, fieldConfig.Name, ReadAction, UpdateAction)...)
+	}
+	if err := m.App.Panel.PreloadPermissions(requests, data); err != nil {
+		return nil, err
+	}
+
+	for _, fieldConfig := range m.Fields {
+		readAllowed, err := m.App.Panel.PermissionChecker.HasFieldReadPermission(m.App.Name, m.Name, instanceID, fieldConfig.Name, data)
+		if err != nil {
+			return nil, err
+		}
+		updateAllowed, err := m.App.Panel.PermissionChecker.HasFieldUpdatePermission(m.App.Name, m.Name, instanceID, fieldConfig.Name, data)
+		if err != nil {
+			return nil, err
+		}
+		permissions[fieldConfig.Name] = Permissions{Read: readAllowed, Update: updateAllowed}
+	}
+	return permissions, nil
+}
+
+func (m *Model) newFieldPermissionRequests(instanceID interface{}, fieldName string, actions ...Action) []PermissionRequest {
+	requests := newModelPermissionRequests(m.App.Name, m.Name, instanceID, actions...)
+	for i := range requests {
+		requests[i].FieldName = &fieldName
+	}
+	return requests
+}
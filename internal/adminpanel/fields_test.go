@@ -0,0 +1,181 @@
+package adminpanel
+
+import (
+	"bytes"
+	"errors"
+	"github.com/go-advanced-admin/admin/internal/form"
+	"github.com/go-advanced-admin/admin/internal/form/fields"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// fieldPermissionFunc hides the Age field and makes the Name field read-only.
+func fieldPermissionFunc(request PermissionRequest, _ interface{}) (bool, error) {
+	if request.FieldName == nil {
+		return true, nil
+	}
+	switch *request.FieldName {
+	case "Age":
+		return false, nil
+	case "Name":
+		return *request.Action == ReadAction, nil
+	}
+	return true, nil
+}
+
+func TestModel_FieldPermissions(t *testing.T) {
+	_, model, _ := newHTTPTestPanel(t, fieldPermissionFunc, nil)
+
+	readable, err := model.FilterReadableFields(model.Fields, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names := getFieldNames(readable); strings.Join(names, ",") != "ID,Name" {
+		t.Errorf("expected ID and Name to be readable, got %v", names)
+	}
+
+	permissions, err := model.GetFieldPermissions(uint(1), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if permissions["Name"] != (Permissions{Read: true}) || permissions["Age"] != (Permissions{}) {
+		t.Errorf("unexpected field permissions %+v", permissions)
+	}
+}
+
+func TestModel_GetListQuery_UnreadableFields(t *testing.T) {
+	_, model, _ := newHTTPTestPanel(t, fieldPermissionFunc, nil)
+	model.ListFilters = []ListFilter{{Field: "Age", Kind: ListFilterValues, Choices: []fields.Choice{{Value: "30", Label: "30"}}}}
+	for i := range model.Fields {
+		model.Fields[i].IncludeInSearch = true
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/?ordering=-Age,Name&filter.Age=30&search=3", nil)
+	query, filterValues, err := model.GetListQuery(req, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(query.Ordering) != 1 || query.Ordering[0].Field != "Name" {
+		t.Errorf("expected ordering by the unreadable Age field to be ignored, got %v", query.Ordering)
+	}
+	if len(query.Filters) != 0 || len(filterValues) != 0 {
+		t.Errorf("expected the filter on the unreadable Age field to be ignored, got %v and %v", query.Filters, filterValues)
+	}
+	for _, field := range query.SearchFields {
+		if field == "Age" {
+			t.Errorf("expected the unreadable Age field not to be searched, got %v", query.SearchFields)
+		}
+	}
+}
+
+func TestModelEditForm_ReadOnlyFields(t *testing.T) {
+	_, model, orm := newHTTPTestPanel(t, fieldPermissionFunc, nil)
+
+	permissions, _ := model.GetFieldPermissions(uint(1), nil)
+	formInstance, err := model.NewEditFormWithPermissions(uint(1), permissions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fields := formInstance.GetFields()
+	if len(fields) != 1 || fields[0].GetName() != "Name" {
+		t.Fatalf("expected only the Name field, got %d fields", len(fields))
+	}
+	if _, ok := fields[0].(*form.ReadOnlyField); !ok {
+		t.Errorf("expected Name to be read-only, got %T", fields[0])
+	}
+
+	_, err = formInstance.Save(map[string]form.HTMLType{"Name": "Mallory"})
+	if !errors.Is(err, ErrFieldNotWritable) {
+		t.Errorf("expected ErrFieldNotWritable, got %v", err)
+	}
+	_, err = formInstance.Save(map[string]form.HTMLType{"Age": "99"})
+	if !errors.Is(err, ErrFieldNotWritable) {
+		t.Errorf("expected ErrFieldNotWritable for a hidden field, got %v", err)
+	}
+	if orm.Existing[1].Name != "Alice" || orm.Existing[1].Age != 30 {
+		t.Errorf("rejected values should not be saved, got %+v", orm.Existing[1])
+	}
+}
+
+func TestFieldPermissions_Views(t *testing.T) {
+	web, model, _ := newHTTPTestPanel(t, fieldPermissionFunc, nil)
+
+	tests := []struct {
+		name        string
+		path        string
+		contains    string
+		notContains string
+	}{
+		{"List", model.GetFullLink(), "Alice", ">Age<"},
+		{"Instance", model.GetFullLink() + "/1/view", "Alice", "Age:"},
+		{"Export", model.GetFullExportLink() + "?format=csv", "ID,Name\n1,Alice", "Age"},
+		{"Edit", model.GetFullLink() + "/1/edit", `<input disabled `, `name="Age"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			web.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			body := rec.Body.String()
+			if !strings.Contains(body, tt.contains) {
+				t.Errorf("expected body to contain %q, got %s", tt.contains, body)
+			}
+			if strings.Contains(body, tt.notContains) {
+				t.Errorf("expected body not to contain %q, got %s", tt.notContains, body)
+			}
+		})
+	}
+}
+
+func TestFieldPermissions_EditRejectsReadOnlyField(t *testing.T) {
+	web, model, orm := newHTTPTestPanel(t, fieldPermissionFunc, nil)
+
+	post := func(values url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, model.GetFullLink()+"/1/edit", strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		web.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := post(url.Values{"Name": {"Mallory"}})
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = post(url.Values{})
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if orm.Existing[1].Name != "Alice" || orm.Existing[1].Age != 30 {
+		t.Errorf("read-only and hidden fields should keep their values, got %+v", orm.Existing[1])
+	}
+}
+
+func TestFieldPermissions_API(t *testing.T) {
+	web, model, orm := newHTTPTestPanel(t, fieldPermissionFunc, func(config *AdminConfig) {
+		config.APIPrefix = "api"
+	})
+	link := model.App.Panel.Config.GetLink(model.GetAPIInstanceLink(uint(1)))
+
+	rec := httptest.NewRecorder()
+	web.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, link, nil))
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), "Age") {
+		t.Errorf("expected Age to be hidden, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	web.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, link, bytes.NewBufferString(`{"Name":"Mallory"}`)))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected status 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if orm.Existing[1].Name != "Alice" {
+		t.Errorf("expected Name to be unchanged, got %s", orm.Existing[1].Name)
+	}
+}
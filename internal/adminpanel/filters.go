@@ -0,0 +1,278 @@
+package adminpanel
+
+import (
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/form/fields"
+	"github.com/go-advanced-admin/admin/internal/utils"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// FilterOperator represents a comparison operator used in filter expressions.
+type FilterOperator string
+
+const (
+	// FilterEqual matches values equal to the expression value.
+	FilterEqual FilterOperator = "eq"
+	// FilterGreaterThanOrEqual matches values greater than or equal to the expression value.
+	FilterGreaterThanOrEqual FilterOperator = "gte"
+	// FilterLessThan matches values less than the expression value.
+	FilterLessThan FilterOperator = "lt"
+)
+
+// FilterExpression represents a single constraint on a field of a model. A query matches an instance when all of
+// its filter expressions match.
+type FilterExpression struct {
+	Field    string
+	Operator FilterOperator
+	Value    interface{}
+}
+
+// Matches reports whether the instance satisfies the expression.
+func (e FilterExpression) Matches(instance interface{}) (bool, error) {
+	value, err := utils.GetFieldValue(instance, e.Field)
+	if err != nil {
+		return false, err
+	}
+	result := utils.CompareValues(value, e.Value)
+	switch e.Operator {
+	case FilterEqual:
+		return result == 0, nil
+	case FilterGreaterThanOrEqual:
+		return result >= 0, nil
+	case FilterLessThan:
+		return result < 0, nil
+	default:
+		return false, fmt.Errorf("unsupported filter operator: %s", e.Operator)
+	}
+}
+
+// ListFilterKind represents the kind of a list view filter.
+type ListFilterKind string
+
+const (
+	// ListFilterBoolean filters boolean fields by yes or no.
+	ListFilterBoolean ListFilterKind = "boolean"
+	// ListFilterChoice filters fields by one of the choices of their ChoiceField.
+	ListFilterChoice ListFilterKind = "choice"
+	// ListFilterDate filters date fields by a range relative to the current date.
+	ListFilterDate ListFilterKind = "date"
+	// ListFilterValues filters fields, such as foreign keys, by one of a list of values.
+	ListFilterValues ListFilterKind = "values"
+)
+
+// ListFilter describes a filter of the model list view.
+type ListFilter struct {
+	Field   string
+	Kind    ListFilterKind
+	Choices []fields.Choice
+}
+
+// AdminListFiltersInterface allows a model to define the filters of its list view, for example to offer the values
+// of a foreign key.
+type AdminListFiltersInterface interface {
+	// AdminListFilters returns the filters of the list view.
+	AdminListFilters() []ListFilter
+}
+
+// ListFilterOption represents a selectable option of a list filter.
+type ListFilterOption struct {
+	Label    string
+	Link     string
+	Selected bool
+}
+
+// ListFilterState represents a list filter along with its options for the current request.
+type ListFilterState struct {
+	Filter      ListFilter
+	DisplayName string
+	Options     []ListFilterOption
+}
+
+var dateFilterChoices = []fields.Choice{
+	{Value: "today", Label: "Today"},
+	{Value: "past_7_days", Label: "Past 7 days"},
+	{Value: "this_month", Label: "This month"},
+	{Value: "this_year", Label: "This year"},
+}
+
+// GetParam returns the query parameter name holding the filter value.
+func (f ListFilter) GetParam() string {
+	return "filter." + f.Field
+}
+
+// GetChoices returns the choices offered by the filter, excluding the choice that disables it.
+func (f ListFilter) GetChoices() []fields.Choice {
+	switch f.Kind {
+	case ListFilterBoolean:
+		return []fields.Choice{{Value: "true", Label: "Yes"}, {Value: "false", Label: "No"}}
+	case ListFilterDate:
+		return dateFilterChoices
+	default:
+		return f.Choices
+	}
+}
+
+// Expressions converts a filter value into filter expressions. Empty and invalid values yield no expressions.
+func (f ListFilter) Expressions(value string, fieldType reflect.Type, now time.Time) []FilterExpression {
+	if value == "" {
+		return nil
+	}
+	switch f.Kind {
+	case ListFilterDate:
+		start, end, ok := dateFilterRange(value, now)
+		if !ok {
+			return nil
+		}
+		return []FilterExpression{
+			{Field: f.Field, Operator: FilterGreaterThanOrEqual, Value: start},
+			{Field: f.Field, Operator: FilterLessThan, Value: end},
+		}
+	default:
+		converted, err := utils.ConvertStringToType(value, fieldType)
+		if err != nil {
+			return nil
+		}
+		return []FilterExpression{{Field: f.Field, Operator: FilterEqual, Value: converted}}
+	}
+}
+
+func dateFilterRange(value string, now time.Time) (time.Time, time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch value {
+	case "today":
+		return today, today.AddDate(0, 0, 1), true
+	case "past_7_days":
+		return today.AddDate(0, 0, -7), today.AddDate(0, 0, 1), true
+	case "this_month":
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0), true
+	case "this_year":
+		start := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(1, 0, 0), true
+	default:
+		return time.Time{}, time.Time{}, false
+	}
+}
+
+func newTagListFilter(fieldConfig FieldConfig) (ListFilter, error) {
+	filter := ListFilter{Field: fieldConfig.Name}
+	if choiceField, ok := fieldConfig.EditFormField.(*fields.ChoiceField); ok {
+		filter.Kind = ListFilterChoice
+		filter.Choices = choiceField.Choices
+		return filter, nil
+	}
+	if choiceField, ok := fieldConfig.AddFormField.(*fields.ChoiceField); ok {
+		filter.Kind = ListFilterChoice
+		filter.Choices = choiceField.Choices
+		return filter, nil
+	}
+	if fieldConfig.FieldType == reflect.TypeOf(time.Time{}) {
+		filter.Kind = ListFilterDate
+		return filter, nil
+	}
+	if fieldConfig.FieldType.Kind() == reflect.Bool {
+		filter.Kind = ListFilterBoolean
+		return filter, nil
+	}
+	return filter, fmt.Errorf("field '%s' cannot be filtered by tag; provide its values through AdminListFilters", fieldConfig.Name)
+}
+
+// GetFilterValues returns the values of the list filters found in the request.
+func (m *Model) GetFilterValues(data interface{}) map[string]string {
+	values := make(map[string]string)
+	for _, filter := range m.ListFilters {
+		if value := m.App.Panel.Web.GetQueryParam(data, filter.GetParam()); value != "" {
+			values[filter.Field] = value
+		}
+	}
+	return values
+}
+
+// GetFilterExpressions converts list filter values into filter expressions.
+func (m *Model) GetFilterExpressions(values map[string]string, now time.Time) []FilterExpression {
+	expressions := make([]FilterExpression, 0)
+	for _, filter := range m.ListFilters {
+		fieldConfig, ok := m.GetFieldConfig(filter.Field)
+		if !ok {
+			continue
+		}
+		expressions = append(expressions, filter.Expressions(values[filter.Field], fieldConfig.FieldType, now)...)
+	}
+	return expressions
+}
+
+// GetListFilterStates returns the list filters with links to each of their options. The given query parameters are
+// preserved in the links.
+func (m *Model) GetListFilterStates(values map[string]string, params url.Values) []ListFilterState {
+	states := make([]ListFilterState, 0, len(m.ListFilters))
+	for _, filter := range m.ListFilters {
+		displayName := utils.HumanizeName(filter.Field)
+		if fieldConfig, ok := m.GetFieldConfig(filter.Field); ok {
+			displayName = fieldConfig.DisplayName
+		}
+		state := ListFilterState{Filter: filter, DisplayName: displayName}
+		current := values[filter.Field]
+
+		allLabel := "All"
+		if filter.Kind == ListFilterDate {
+			allLabel = "Any date"
+		}
+		state.Options = append(state.Options, ListFilterOption{
+			Label:    allLabel,
+			Link:     m.getFilterLink(params, filter, ""),
+			Selected: current == "",
+		})
+		for _, choice := range filter.GetChoices() {
+			state.Options = append(state.Options, ListFilterOption{
+				Label:    choice.Label,
+				Link:     m.getFilterLink(params, filter, choice.Value),
+				Selected: current == choice.Value,
+			})
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+func (m *Model) getFilterLink(params url.Values, filter ListFilter, value string) string {
+	linkParams := url.Values{}
+	for key, values := range params {
+		linkParams[key] = values
+	}
+	if value == "" {
+		linkParams.Del(filter.GetParam())
+	} else {
+		linkParams.Set(filter.GetParam(), value)
+	}
+	linkParams.Del("page")
+	if len(linkParams) == 0 {
+		return m.GetFullLink()
+	}
+	return fmt.Sprintf("%s?%s", m.GetFullLink(), linkParams.Encode())
+}
+
+func filterInstances(instances []interface{}, filters []FilterExpression) ([]interface{}, error) {
+	if len(filters) == 0 {
+		return instances, nil
+	}
+	filtered := make([]interface{}, 0, len(instances))
+	for _, instance := range instances {
+		matches := true
+		for _, filter := range filters {
+			ok, err := filter.Matches(instance)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			filtered = append(filtered, instance)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,168 @@
+package adminpanel
+
+import (
+	"github.com/go-advanced-admin/admin/internal/form/fields"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type FilteredTestModel struct {
+	ID             uint
+	Active         bool      `admin:"filter"`
+	Created        time.Time `admin:"filter;addForm:exclude;editForm:exclude"`
+	OrganizationID uint
+}
+
+func (m *FilteredTestModel) AdminListFilters() []ListFilter {
+	return []ListFilter{{
+		Field:   "OrganizationID",
+		Kind:    ListFilterValues,
+		Choices: []fields.Choice{{Value: "1", Label: "Acme"}, {Value: "2", Label: "Globex"}},
+	}}
+}
+
+type FilterORMIntegrator struct {
+	MockORMIntegrator
+	Instances []*FilteredTestModel
+}
+
+func (o *FilterORMIntegrator) FetchInstancesOnlyFields(interface{}, []string) (interface{}, error) {
+	return o.Instances, nil
+}
+
+func TestRegisterModel_ListFilters(t *testing.T) {
+	model := newTestModel(t, &FilteredTestModel{}, nil)
+
+	expected := []ListFilterKind{ListFilterBoolean, ListFilterDate, ListFilterValues}
+	if len(model.ListFilters) != len(expected) {
+		t.Fatalf("expected %d filters, got %d", len(expected), len(model.ListFilters))
+	}
+	for i, kind := range expected {
+		if model.ListFilters[i].Kind != kind {
+			t.Errorf("expected filter %d to be %s, got %s", i, kind, model.ListFilters[i].Kind)
+		}
+	}
+
+	t.Run("UnsupportedTagField", func(t *testing.T) {
+		panel, _ := NewMockAdminPanel()
+		testApp, _ := panel.RegisterApp("TestApp", "Test App", nil)
+		type UnsupportedFilterModel struct {
+			ID   uint
+			Name string `admin:"filter"`
+		}
+		if _, err := testApp.RegisterModel(&UnsupportedFilterModel{}, nil); err == nil {
+			t.Error("expected an error for a tag filter on a field without choices")
+		}
+	})
+}
+
+func TestListFilter_Expressions(t *testing.T) {
+	now := time.Date(2024, time.March, 15, 13, 30, 0, 0, time.UTC)
+	today := time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filter    ListFilter
+		value     string
+		fieldType reflect.Type
+		expected  []FilterExpression
+	}{
+		{"Empty", ListFilter{Field: "Active", Kind: ListFilterBoolean}, "", reflect.TypeOf(true), nil},
+		{"Boolean", ListFilter{Field: "Active", Kind: ListFilterBoolean}, "true", reflect.TypeOf(true),
+			[]FilterExpression{{Field: "Active", Operator: FilterEqual, Value: true}}},
+		{"Invalid Boolean", ListFilter{Field: "Active", Kind: ListFilterBoolean}, "maybe", reflect.TypeOf(true), nil},
+		{"Values", ListFilter{Field: "OrganizationID", Kind: ListFilterValues}, "2", reflect.TypeOf(uint(0)),
+			[]FilterExpression{{Field: "OrganizationID", Operator: FilterEqual, Value: uint(2)}}},
+		{"Today", ListFilter{Field: "Created", Kind: ListFilterDate}, "today", reflect.TypeOf(time.Time{}),
+			[]FilterExpression{
+				{Field: "Created", Operator: FilterGreaterThanOrEqual, Value: today},
+				{Field: "Created", Operator: FilterLessThan, Value: today.AddDate(0, 0, 1)},
+			}},
+		{"This Month", ListFilter{Field: "Created", Kind: ListFilterDate}, "this_month", reflect.TypeOf(time.Time{}),
+			[]FilterExpression{
+				{Field: "Created", Operator: FilterGreaterThanOrEqual, Value: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)},
+				{Field: "Created", Operator: FilterLessThan, Value: time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+			}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expressions := tt.filter.Expressions(tt.value, tt.fieldType, now)
+			if !reflect.DeepEqual(expressions, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, expressions)
+			}
+		})
+	}
+}
+
+func TestModel_FetchInstancesPage_Filters(t *testing.T) {
+	now := time.Now()
+	orm := &FilterORMIntegrator{Instances: []*FilteredTestModel{
+		{ID: 1, Active: true, Created: now, OrganizationID: 1},
+		{ID: 2, Active: false, Created: now.AddDate(-2, 0, 0), OrganizationID: 1},
+		{ID: 3, Active: true, Created: now.AddDate(-2, 0, 0), OrganizationID: 2},
+	}}
+	model := newTestModel(t, &FilteredTestModel{}, orm)
+
+	tests := []struct {
+		name     string
+		values   map[string]string
+		expected []uint
+	}{
+		{"No Filters", map[string]string{}, []uint{1, 2, 3}},
+		{"Active", map[string]string{"Active": "true"}, []uint{1, 3}},
+		{"This Year", map[string]string{"Created": "this_year"}, []uint{1}},
+		{"Combined", map[string]string{"Active": "true", "OrganizationID": "2"}, []uint{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := model.FetchInstancesPage(InstancesQuery{Filters: model.GetFilterExpressions(tt.values, now)})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ids := make([]uint, 0)
+			for _, instance := range page {
+				ids = append(ids, instance.(*FilteredTestModel).ID)
+			}
+			if !reflect.DeepEqual(ids, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+func TestModel_GetListFilterStates(t *testing.T) {
+	model := newTestModel(t, &FilteredTestModel{}, nil)
+
+	states := model.GetListFilterStates(map[string]string{"Active": "false"}, url.Values{"filter.Active": {"false"}, "search": {"x"}})
+	if len(states) != 3 {
+		t.Fatalf("expected 3 filter states, got %d", len(states))
+	}
+
+	active := states[0]
+	if len(active.Options) != 3 {
+		t.Fatalf("expected 3 options, got %d", len(active.Options))
+	}
+	if !active.Options[2].Selected || active.Options[0].Selected {
+		t.Errorf("expected the 'No' option to be selected")
+	}
+	if active.Options[0].Link != "/admin/a/TestApp/FilteredTestModel?search=x" {
+		t.Errorf("unexpected link for 'All': %s", active.Options[0].Link)
+	}
+	if active.Options[1].Link != "/admin/a/TestApp/FilteredTestModel?filter.Active=true&search=x" {
+		t.Errorf("unexpected link for 'Yes': %s", active.Options[1].Link)
+	}
+}
+
+func TestModel_GetViewHandler_Filters(t *testing.T) {
+	orm := &FilterORMIntegrator{Instances: []*FilteredTestModel{{ID: 1, Active: true}, {ID: 2}}}
+	model := newTestModel(t, &FilteredTestModel{}, orm)
+
+	code, html := model.GetViewHandler()(map[string]string{"filter.Active": "true", "ordering": "-ID"})
+	if code != 200 {
+		t.Fatalf("expected status 200, got %d: %s", code, html)
+	}
+}
@@ -0,0 +1,177 @@
+package adminpanel
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// FieldChange is the change of a single field recorded in an update log entry. Entries recorded before diffs were
+// logged only hold the new value, leaving HasOld false.
+type FieldChange struct {
+	Field       string
+	DisplayName string
+	Old         interface{}
+	New         interface{}
+	HasOld      bool
+}
+
+// HistoryEntry is a log entry about an instance along with the field changes it records.
+type HistoryEntry struct {
+	Log     *logging.LogEntry
+	Changes []FieldChange
+}
+
+// GetHistoryLink returns the relative URL to the history of the instance.
+func (i *Instance) GetHistoryLink() string {
+	return fmt.Sprintf("%s/%v/history", i.Model.GetLink(), i.InstanceID)
+}
+
+// GetFullHistoryLink returns the full URL to the history of the instance.
+func (i *Instance) GetFullHistoryLink() string {
+	return i.Model.App.Panel.Config.GetLink(i.GetHistoryLink())
+}
+
+// GetFieldChanges returns the field changes recorded in the message of an update log entry, in the order of the
+// model's fields. Changes to fields missing from fieldPermissions or without read permission are left out; a nil
+// fieldPermissions keeps every change. It returns nil for other entries and for messages that are not JSON objects.
+func (m *Model) GetFieldChanges(entry *logging.LogEntry, fieldPermissions map[string]Permissions) []FieldChange {
+	if entry.ActionFlag != logging.LogStoreLevelUpdate || entry.Message == "" {
+		return nil
+	}
+	diffs, hasOld := logging.ParseFieldDiffs(entry.Message)
+	if !hasOld {
+		var values map[string]interface{}
+		if err := json.Unmarshal([]byte(entry.Message), &values); err != nil {
+			return nil
+		}
+		diffs = make(map[string]logging.FieldDiff, len(values))
+		for field, value := range values {
+			diffs[field] = logging.FieldDiff{New: value}
+		}
+	}
+
+	changes := make([]FieldChange, 0, len(diffs))
+	for _, fieldConfig := range m.Fields {
+		diff, ok := diffs[fieldConfig.Name]
+		if !ok {
+			continue
+		}
+		delete(diffs, fieldConfig.Name)
+		if fieldPermissions != nil && !fieldPermissions[fieldConfig.Name].Read {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: fieldConfig.Name, DisplayName: fieldConfig.DisplayName, Old: diff.Old, New: diff.New, HasOld: hasOld})
+	}
+	if fieldPermissions == nil {
+		names := make([]string, 0, len(diffs))
+		for name := range diffs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			changes = append(changes, FieldChange{Field: name, DisplayName: name, Old: diffs[name].Old, New: diffs[name].New, HasOld: hasOld})
+		}
+	}
+	return changes
+}
+
+// GetInstanceHistoryHandler returns the HTTP handler function listing the log entries about an instance, newest first,
+// with the field changes recorded by its updates.
+func (m *Model) GetInstanceHistoryHandler() HandlerFunc {
+	return func(data interface{}) (uint, string) {
+		m := m.withRequestContext(data)
+
+		instanceIDStr := m.App.Panel.Web.GetPathParam(data, "id")
+		if instanceIDStr == "" {
+			return GetErrorHTML(http.StatusBadRequest, fmt.Errorf("instance id is required"))
+		}
+		instanceID, err := m.ParseInstanceID(instanceIDStr)
+		if err != nil {
+			return GetErrorHTML(http.StatusBadRequest, err)
+		}
+
+		allowed, err := m.App.Panel.PermissionChecker.HasInstanceReadPermission(m.App.Name, m.Name, instanceID, data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		if !allowed {
+			return GetErrorHTML(http.StatusForbidden, fmt.Errorf("you are not allowed to view this instance"))
+		}
+
+		// The history of deleted instances stays available, unless a scope hides the instance from the user.
+		scope, err := m.GetQueryScope(data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		if len(scope) > 0 {
+			instanceData, err := m.FetchInstanceInScope(instanceID, nil, scope)
+			if err != nil {
+				return GetErrorHTML(http.StatusInternalServerError, err)
+			}
+			if isNilInstance(instanceData) {
+				return GetErrorHTML(http.StatusNotFound, ErrOutOfScope)
+			}
+		}
+
+		query := logging.LogQuery{
+			ContentType: m.GetLogContentType(),
+			ObjectID:    instanceID,
+			Cursor:      m.App.Panel.Web.GetQueryParam(data, "cursor"),
+			Limit:       m.App.Panel.Config.DefaultInstancesPerPage,
+		}
+		if query.Cursor != "" {
+			if _, err = logging.ParseLogCursor(query.Cursor); err != nil {
+				return GetErrorHTML(http.StatusBadRequest, err)
+			}
+		}
+		page, err := m.App.Panel.QueryLogEntries(data, query)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+
+		fieldPermissions, err := m.GetFieldPermissions(instanceID, data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		history := make([]HistoryEntry, len(page.Entries))
+		for i, entry := range page.Entries {
+			history[i] = HistoryEntry{Log: entry, Changes: m.GetFieldChanges(entry, fieldPermissions)}
+		}
+
+		instance := &Instance{
+			InstanceID: instanceID,
+			Model:      m,
+		}
+		var nextPageLink string
+		if page.NextCursor != "" {
+			nextPageLink = instance.GetFullHistoryLink() + "?" + url.Values{"cursor": {page.NextCursor}}.Encode()
+		}
+
+		apps, err := GetAppsWithReadPermissions(m.App.Panel, data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+
+		html, err := m.App.Panel.Config.Renderer.RenderTemplate("history", map[string]interface{}{
+			"model":        m,
+			"apps":         apps,
+			"navBarItems":  m.App.Panel.Config.GetNavBarItems(data),
+			"instanceRef":  instance,
+			"history":      history,
+			"isFirstPage":  query.Cursor == "",
+			"nextPageLink": nextPageLink,
+		})
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		err = instance.CreateHistoryViewLog(data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		return http.StatusOK, html
+	}
+}
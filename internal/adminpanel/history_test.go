@@ -0,0 +1,211 @@
+package adminpanel
+
+import (
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// FieldsOnlyORMIntegrator fetches only the requested fields, leaving the others at their zero value.
+type FieldsOnlyORMIntegrator struct {
+	*ImportORMIntegrator
+}
+
+func (o *FieldsOnlyORMIntegrator) FetchInstanceOnlyFields(model interface{}, id interface{}, fields []string) (interface{}, error) {
+	instance, err := o.FetchInstance(model, id)
+	if err != nil || instance == nil {
+		return instance, err
+	}
+	fetched := &ImportTestModel{}
+	for _, field := range fields {
+		reflect.ValueOf(fetched).Elem().FieldByName(field).Set(reflect.ValueOf(instance).Elem().FieldByName(field))
+	}
+	return fetched, nil
+}
+
+// hiddenAgePermissionFunc allows everything except reading the Age field.
+func hiddenAgePermissionFunc(request PermissionRequest, _ interface{}) (bool, error) {
+	if request.FieldName != nil && *request.FieldName == "Age" && *request.Action == ReadAction {
+		return false, nil
+	}
+	return true, nil
+}
+
+func TestModel_GetFieldChanges(t *testing.T) {
+	_, model, _ := newHTTPTestPanel(t, MockPermissionFunc, scopeTestConfig, newScopeTestChild())
+	entry := &logging.LogEntry{ActionFlag: logging.LogStoreLevelUpdate, Message: `{"Extra":1,"Age":31,"Name":"Alicia"}`}
+
+	changes := model.GetFieldChanges(entry, nil)
+	if len(changes) != 3 || changes[0].Field != "Name" || changes[1].Field != "Age" || changes[2].Field != "Extra" {
+		t.Errorf("expected the changes in field order, got %+v", changes)
+	}
+
+	changes = model.GetFieldChanges(entry, map[string]Permissions{"Name": {Read: true}})
+	if len(changes) != 1 || changes[0].Field != "Name" || changes[0].New != "Alicia" || changes[0].HasOld {
+		t.Errorf("expected only the readable changes, got %+v", changes)
+	}
+
+	diffEntry := &logging.LogEntry{ActionFlag: logging.LogStoreLevelUpdate, Message: `{"Name":{"old":"Alice","new":"Alicia"}}`}
+	if changes = model.GetFieldChanges(diffEntry, nil); len(changes) != 1 || !changes[0].HasOld || changes[0].Old != "Alice" || changes[0].New != "Alicia" {
+		t.Errorf("expected the recorded diff, got %+v", changes)
+	}
+
+	if changes = model.GetFieldChanges(&logging.LogEntry{ActionFlag: logging.LogStoreLevelDelete, Message: "{}"}, nil); changes != nil {
+		t.Errorf("expected no changes for a delete, got %+v", changes)
+	}
+	if changes = model.GetFieldChanges(&logging.LogEntry{ActionFlag: logging.LogStoreLevelUpdate, Message: "renamed"}, nil); changes != nil {
+		t.Errorf("expected no changes for a plain message, got %+v", changes)
+	}
+}
+
+func TestModel_GetInstanceHistoryHandler(t *testing.T) {
+	web, model, _ := newHTTPTestPanel(t, MockPermissionFunc, scopeTestConfig, newScopeTestChild())
+	model.App.Panel.permissionFunc = hiddenAgePermissionFunc
+	store := model.App.Panel.Config.LogStore
+
+	now := time.Now()
+	entries := []*logging.LogEntry{
+		{ID: "1", ActionTime: now.Add(-2 * time.Hour), UserRepr: "ops", ContentType: model.GetLogContentType(), ObjectID: uint(1), ActionFlag: logging.LogStoreLevelCreate},
+		{ID: "2", ActionTime: now.Add(-time.Hour), UserRepr: "ops", ContentType: model.GetLogContentType(), ObjectID: uint(1), ActionFlag: logging.LogStoreLevelUpdate, Message: `{"Name":{"old":"Alice","new":"Alicia"},"Age":{"old":30,"new":31}}`},
+		{ID: "3", ActionTime: now, UserRepr: "ops", ContentType: model.GetLogContentType(), ObjectID: uint(3), ActionFlag: logging.LogStoreLevelDelete},
+		{ID: "4", ActionTime: now, UserRepr: "ops", ContentType: "Other | Model", ObjectID: uint(1), ActionFlag: logging.LogStoreLevelDelete},
+	}
+	for _, entry := range entries {
+		if err := store.InsertLogEntry(entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	instance := &Instance{InstanceID: uint(1), Model: model}
+	rec := httptest.NewRecorder()
+	web.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, instance.GetFullHistoryLink(), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if strings.Count(body, "<td>ops</td>") != 2 {
+		t.Errorf("expected the two entries about the instance, got %s", body)
+	}
+	if !strings.Contains(body, "Name: Alice &rarr; Alicia") || strings.Contains(body, "Age:") {
+		t.Errorf("expected only the readable field changes, got %s", body)
+	}
+	if page, _ := logging.QueryLogEntries(store, logging.LogQuery{ActionFlags: []logging.LogStoreLevel{logging.LogStoreLevelInstanceView}}); len(page.Entries) != 1 || page.Entries[0].Message != `{"page":"history"}` {
+		t.Errorf("expected the history view to be logged, got %v", page)
+	}
+
+	rec = httptest.NewRecorder()
+	web.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, (&Instance{InstanceID: uint(2), Model: model}).GetFullHistoryLink(), nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for an instance outside the scope, got %d", rec.Code)
+	}
+
+	model.App.Panel.permissionFunc = func(request PermissionRequest, _ interface{}) (bool, error) {
+		return request.Action == nil || *request.Action != ReadAction || request.InstanceID == nil, nil
+	}
+	rec = httptest.NewRecorder()
+	web.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, instance.GetFullHistoryLink(), nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected status 403 without read permission, got %d", rec.Code)
+	}
+}
+
+type SensitiveTestModel struct {
+	ID       uint
+	Name     string
+	Password string `admin:"sensitive"`
+}
+
+func TestInstance_CreateUpdateLog_RedactsSensitiveFields(t *testing.T) {
+	panel, err := NewMockAdminPanel()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	panel.Config.LogStore = logging.NewInMemoryLogStore(10)
+	panel.Config.LogStoreLevel = logging.LogStoreLevelUpdate
+	app, _ := panel.RegisterApp("TestApp", "Test App", nil)
+	model, err := app.RegisterModel(&SensitiveTestModel{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fieldConfig, _ := model.GetFieldConfig("Password"); !fieldConfig.Sensitive {
+		t.Fatal("expected the sensitive tag to mark the field")
+	}
+
+	before := &SensitiveTestModel{ID: 1, Name: "alice", Password: "hunter2"}
+	diff, err := model.DiffInstance(before, map[string]interface{}{"Name": "alicia", "Password": "correct horse"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	instance := &Instance{InstanceID: uint(1), Data: before, Model: model}
+	if err = instance.CreateUpdateLog(nil, diff); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = instance.CreateCreateLog(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries, _ := panel.Config.LogStore.GetLogEntries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 log entries, got %v", entries)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Message, "hunter2") || strings.Contains(entry.Message, "correct horse") || !strings.Contains(entry.Message, logging.RedactedValue) {
+			t.Errorf("expected the password to be redacted, got %s", entry.Message)
+		}
+		if entry.ActionFlag != logging.LogStoreLevelUpdate {
+			continue
+		}
+		if diffs, ok := logging.ParseFieldDiffs(entry.Message); !ok || diffs["Name"].Old != "alice" || diffs["Name"].New != "alicia" {
+			t.Errorf("expected the name diff, got %s", entry.Message)
+		}
+	}
+}
+
+func TestModel_GetEditHandler_LogsDiff(t *testing.T) {
+	web, model, _ := newHTTPTestPanel(t, MockPermissionFunc, scopeTestConfig, newScopeTestChild())
+	model.App.Panel.Config.LogStore = logging.NewInMemoryLogStore(10)
+	model.App.Panel.Config.LogStoreLevel = logging.LogStoreLevelUpdate
+
+	req := httptest.NewRequest(http.MethodPost, model.GetFullLink()+"/1/edit", strings.NewReader(url.Values{"Name": {"Alicia"}, "Age": {"30"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	web.ServeHTTP(rec, req)
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	entries, _ := model.App.Panel.Config.LogStore.GetLogEntries()
+	if len(entries) != 1 || entries[0].Message != `{"Name":{"old":"Alice","new":"Alicia"}}` {
+		t.Errorf("expected only the changed field in the diff, got %v", entries)
+	}
+}
+
+func TestModel_GetEditHandler_DiffsHiddenFields(t *testing.T) {
+	web, model, orm := newHTTPTestPanel(t, MockPermissionFunc, scopeTestConfig, newScopeTestChild())
+	model.ORM = &FieldsOnlyORMIntegrator{ImportORMIntegrator: orm}
+	model.App.Panel.Config.LogStore = logging.NewInMemoryLogStore(10)
+	model.App.Panel.Config.LogStoreLevel = logging.LogStoreLevelUpdate
+	for i := range model.Fields {
+		if model.Fields[i].Name == "Name" {
+			model.Fields[i].IncludeInInstanceView = false
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, model.GetFullLink()+"/1/edit", strings.NewReader(url.Values{"Name": {"Alicia"}, "Age": {"30"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	web.ServeHTTP(rec, req)
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	entries, _ := model.App.Panel.Config.LogStore.GetLogEntries()
+	if len(entries) != 1 || entries[0].Message != `{"Name":{"old":"Alice","new":"Alicia"}}` {
+		t.Errorf("expected the stored value of the hidden field in the diff, got %v", entries)
+	}
+}
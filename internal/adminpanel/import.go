@@ -0,0 +1,526 @@
+package adminpanel
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/form"
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"github.com/go-advanced-admin/admin/internal/utils"
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// ImportRowKind represents whether an import row creates or updates an instance.
+type ImportRowKind string
+
+const (
+	// ImportRowCreate marks a row creating a new instance.
+	ImportRowCreate ImportRowKind = "create"
+	// ImportRowUpdate marks a row updating the instance identified by its primary key column.
+	ImportRowUpdate ImportRowKind = "update"
+)
+
+// ImportChange represents the change of a single field by an import row.
+type ImportChange struct {
+	Field       string
+	DisplayName string
+	Old         interface{}
+	New         interface{}
+}
+
+// ImportRow represents a single row of an import along with its validation results.
+type ImportRow struct {
+	Number     int
+	Kind       ImportRowKind
+	InstanceID interface{}
+	Changes    []ImportChange
+	Errors     []string
+	values     map[string]form.HTMLType
+	form       form.Form
+}
+
+// HasErrors reports whether the row failed validation.
+func (r *ImportRow) HasErrors() bool {
+	return len(r.Errors) > 0
+}
+
+// ImportPreview represents the dry-run result of an import.
+type ImportPreview struct {
+	Rows   []*ImportRow
+	Errors []string
+}
+
+// HasErrors reports whether the import or any of its rows failed validation.
+func (p *ImportPreview) HasErrors() bool {
+	if len(p.Errors) > 0 {
+		return true
+	}
+	for _, row := range p.Rows {
+		if row.HasErrors() {
+			return true
+		}
+	}
+	return false
+}
+
+// CountKind returns the number of rows of the given kind.
+func (p *ImportPreview) CountKind(kind ImportRowKind) int {
+	count := 0
+	for _, row := range p.Rows {
+		if row.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// GetImportLink returns the relative URL path to import instances of the model.
+func (m *Model) GetImportLink() string {
+	return fmt.Sprintf("%s/import", m.GetLink())
+}
+
+// GetFullImportLink returns the full URL path to import instances of the model.
+func (m *Model) GetFullImportLink() string {
+	return m.App.Panel.Config.GetLink(m.GetImportLink())
+}
+
+// ParseImportRecords parses CSV, JSON or JSONL content into records keyed by field name. Columns may be named after
+// either the name or the display name of a field.
+func (m *Model) ParseImportRecords(format ExportFormat, content []byte) ([]map[string]form.HTMLType, error) {
+	var rawRecords []map[string]string
+	switch format {
+	case ExportFormatCSV:
+		reader := csv.NewReader(bytes.NewReader(content))
+		rows, err := reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			return nil, fmt.Errorf("the file is empty")
+		}
+		header := rows[0]
+		for _, row := range rows[1:] {
+			record := make(map[string]string)
+			for i, column := range header {
+				if i < len(row) {
+					record[column] = row[i]
+				}
+			}
+			rawRecords = append(rawRecords, record)
+		}
+	case ExportFormatJSON:
+		var objects []map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		if err := decoder.Decode(&objects); err != nil {
+			return nil, err
+		}
+		for _, object := range objects {
+			rawRecords = append(rawRecords, stringifyImportObject(object))
+		}
+	case ExportFormatJSONL:
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var object map[string]interface{}
+			decoder := json.NewDecoder(strings.NewReader(line))
+			decoder.UseNumber()
+			if err := decoder.Decode(&object); err != nil {
+				return nil, err
+			}
+			rawRecords = append(rawRecords, stringifyImportObject(object))
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported import format '%s'", format)
+	}
+
+	records := make([]map[string]form.HTMLType, 0, len(rawRecords))
+	for _, rawRecord := range rawRecords {
+		record := make(map[string]form.HTMLType)
+		for column, value := range rawRecord {
+			fieldConfig, ok := m.getImportFieldConfig(column)
+			if !ok {
+				return nil, fmt.Errorf("column '%s' does not match any field of %s", column, m.DisplayName)
+			}
+			record[fieldConfig.Name] = form.HTMLType(value)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (m *Model) getImportFieldConfig(column string) (*FieldConfig, bool) {
+	column = strings.TrimSpace(column)
+	if fieldConfig, ok := m.GetFieldConfig(column); ok {
+		return fieldConfig, true
+	}
+	for i := range m.Fields {
+		if strings.EqualFold(m.Fields[i].DisplayName, column) {
+			return &m.Fields[i], true
+		}
+	}
+	return nil, false
+}
+
+func stringifyImportObject(object map[string]interface{}) map[string]string {
+	record := make(map[string]string)
+	for key, value := range object {
+		switch v := value.(type) {
+		case nil:
+			record[key] = ""
+		case string:
+			record[key] = v
+		case []interface{}, map[string]interface{}:
+			encoded, _ := json.Marshal(v)
+			record[key] = string(encoded)
+		default:
+			record[key] = fmt.Sprint(v)
+		}
+	}
+	return record
+}
+
+// PrepareImport validates the records through the model's add and edit forms and returns a dry-run preview. Records
+// with a non-empty primary key column update the existing instance; all others create a new one. When the primary key
+// is set through the add form, as natural keys are, records naming a missing instance create it. Nothing is saved.
+func (m *Model) PrepareImport(records []map[string]form.HTMLType, data interface{}) (*ImportPreview, error) {
+	primaryKeyField, err := m.GetPrimaryKeyField()
+	if err != nil {
+		return nil, err
+	}
+	createMissing := false
+	if fieldConfig, ok := m.GetFieldConfig(primaryKeyField); ok && fieldConfig.AddFormField != nil {
+		createMissing = true
+	}
+
+	preview := &ImportPreview{}
+	for i, record := range records {
+		row := &ImportRow{Number: i + 1, Kind: ImportRowCreate, values: record}
+		preview.Rows = append(preview.Rows, row)
+
+		if idStr := strings.TrimSpace(string(record[primaryKeyField])); idStr != "" {
+			row.Kind = ImportRowUpdate
+			if err := m.prepareImportUpdate(row, idStr, createMissing, data); err != nil {
+				return nil, err
+			}
+		} else if err := m.prepareImportCreate(row, data); err != nil {
+			return nil, err
+		}
+	}
+	return preview, nil
+}
+
+func (m *Model) prepareImportCreate(row *ImportRow, data interface{}) error {
+	allowed, err := m.App.Panel.PermissionChecker.HasModelCreatePermission(m.App.Name, m.Name, data)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		row.Errors = append(row.Errors, "you are not allowed to create instances")
+		return nil
+	}
+
+	row.form, err = m.NewAddForm()
+	if err != nil {
+		return err
+	}
+	cleanValues := validateImportRow(row)
+	for _, fieldConfig := range m.Fields {
+		if fieldConfig.AddFormField == nil {
+			continue
+		}
+		if value, ok := cleanValues[fieldConfig.Name]; ok && value != nil {
+			row.Changes = append(row.Changes, ImportChange{Field: fieldConfig.Name, DisplayName: fieldConfig.DisplayName, New: value})
+		}
+	}
+	return nil
+}
+
+func (m *Model) prepareImportUpdate(row *ImportRow, idStr string, createMissing bool, data interface{}) error {
+	id, err := m.ParseInstanceID(idStr)
+	if err != nil {
+		row.Errors = append(row.Errors, err.Error())
+		return nil
+	}
+	row.InstanceID = id
+
+	allowed, err := m.App.Panel.PermissionChecker.HasInstanceUpdatePermission(m.App.Name, m.Name, id, data)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		row.Errors = append(row.Errors, fmt.Sprintf("you are not allowed to update instance %v", id))
+		return nil
+	}
+
+	scope, err := m.GetQueryScope(data)
+	if err != nil {
+		return err
+	}
+	existing, err := m.FetchInstanceInScope(id, nil, scope)
+	if err != nil {
+		return err
+	}
+	if isNilInstance(existing) {
+		if createMissing {
+			row.Kind, row.InstanceID = ImportRowCreate, nil
+			return m.prepareImportCreate(row, data)
+		}
+		row.Errors = append(row.Errors, fmt.Sprintf("instance %v does not exist", id))
+		return nil
+	}
+
+	fieldPermissions, err := m.GetFieldPermissions(id, data)
+	if err != nil {
+		return err
+	}
+	row.form, err = m.NewEditFormWithPermissions(id, fieldPermissions)
+	if err != nil {
+		return err
+	}
+	row.form.(*ModelEditForm).Scope = scope
+	readOnlyFields := row.form.(*ModelEditForm).ReadOnlyFields
+
+	oldValues := make(map[string]interface{})
+	for _, fieldConfig := range m.Fields {
+		if fieldConfig.EditFormField == nil {
+			continue
+		}
+		value, err := utils.GetFieldValue(existing, fieldConfig.Name)
+		if err != nil {
+			return err
+		}
+		value = dereferenceImportValue(value)
+		oldValues[fieldConfig.Name] = value
+		if readOnlyFields[fieldConfig.Name] {
+			if htmlValue, ok := row.values[fieldConfig.Name]; ok {
+				oldHTMLValue, err := fieldConfig.EditFormField.GoTypeToHTMLType(value)
+				if err != nil {
+					return err
+				}
+				newValue, err := fieldConfig.EditFormField.HTMLTypeToGoType(htmlValue)
+				if htmlValue != oldHTMLValue && (err != nil || utils.CompareValues(value, newValue) != 0) {
+					row.Errors = append(row.Errors, fmt.Sprintf("%s: %v", fieldConfig.DisplayName, ErrFieldNotWritable))
+				}
+				delete(row.values, fieldConfig.Name)
+			}
+			continue
+		}
+		if _, ok := row.values[fieldConfig.Name]; !ok {
+			htmlValue, err := fieldConfig.EditFormField.GoTypeToHTMLType(value)
+			if err != nil {
+				return err
+			}
+			row.values[fieldConfig.Name] = htmlValue
+		}
+	}
+
+	cleanValues := validateImportRow(row)
+	for _, fieldConfig := range m.Fields {
+		if fieldConfig.EditFormField == nil || readOnlyFields[fieldConfig.Name] {
+			continue
+		}
+		newValue := cleanValues[fieldConfig.Name]
+		if utils.CompareValues(oldValues[fieldConfig.Name], newValue) != 0 {
+			row.Changes = append(row.Changes, ImportChange{Field: fieldConfig.Name, DisplayName: fieldConfig.DisplayName, Old: oldValues[fieldConfig.Name], New: newValue})
+		}
+	}
+	return nil
+}
+
+func validateImportRow(row *ImportRow) map[string]interface{} {
+	cleanValues, formErrs, fieldErrs, err := validateFormValues(row.form, row.values)
+	if err != nil {
+		row.Errors = append(row.Errors, err.Error())
+		return cleanValues
+	}
+	for _, formErr := range formErrs {
+		row.Errors = append(row.Errors, formErr.Error())
+	}
+	for _, field := range row.form.GetFields() {
+		for _, fieldErr := range fieldErrs[field.GetName()] {
+			row.Errors = append(row.Errors, fmt.Sprintf("%s: %v", field.GetLabel(), fieldErr))
+		}
+	}
+	return cleanValues
+}
+
+func dereferenceImportValue(value interface{}) interface{} {
+	val := reflect.ValueOf(value)
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil
+		}
+		return val.Elem().Interface()
+	}
+	return value
+}
+
+// ApplyImport saves every row of a preview without errors through the model's add and edit forms and creates the
+// usual create and update log entries. It returns the number of created and updated instances. The rows are saved in
+// a single transaction when the ORM integrator implements TransactionalORMIntegrator, so a failing row leaves no
+// earlier row behind.
+func (m *Model) ApplyImport(preview *ImportPreview, data interface{}) (created int, updated int, err error) {
+	if preview.HasErrors() {
+		return 0, 0, fmt.Errorf("the import contains errors")
+	}
+
+	err = m.WithTransaction(data, func(tm *Model) error {
+		for _, row := range preview.Rows {
+			bindForm(row.form, tm)
+			instanceData, err := row.form.Save(row.values)
+			if err != nil {
+				return fmt.Errorf("row %d: %w", row.Number, err)
+			}
+
+			if row.Kind == ImportRowCreate {
+				instanceID, err := m.GetPrimaryKeyValue(instanceData)
+				if err != nil {
+					return err
+				}
+				instance := &Instance{InstanceID: instanceID, Data: instanceData, Model: m}
+				if err = instance.CreateCreateLog(data); err != nil {
+					return err
+				}
+				created++
+				continue
+			}
+
+			diff := make(map[string]logging.FieldDiff)
+			for _, change := range row.Changes {
+				diff[change.Field] = logging.FieldDiff{Old: change.Old, New: change.New}
+			}
+			instance := &Instance{InstanceID: row.InstanceID, Data: instanceData, Model: m}
+			if err = instance.CreateUpdateLog(data, diff); err != nil {
+				return err
+			}
+			updated++
+		}
+		return nil
+	})
+	return created, updated, err
+}
+
+func (m *Model) getImportContent(data interface{}, formData map[string][]string) (ExportFormat, []byte, error) {
+	var formatName string
+	if values := formData["format"]; len(values) > 0 {
+		formatName = values[0]
+	}
+
+	if web, ok := m.App.Panel.Web.(MultipartWebIntegrator); ok {
+		fileName, content, err := web.GetFormFile(data, "file")
+		if err != nil {
+			return "", nil, err
+		}
+		if fileName != "" {
+			if formatName == "" {
+				formatName = strings.TrimPrefix(path.Ext(fileName), ".")
+			}
+			format, err := ParseExportFormat(formatName)
+			return format, content, err
+		}
+	}
+
+	if values := formData["data"]; len(values) > 0 && strings.TrimSpace(values[0]) != "" {
+		format, err := ParseExportFormat(formatName)
+		return format, []byte(values[0]), err
+	}
+	return "", nil, fmt.Errorf("no import data provided")
+}
+
+// GetImportHandler returns the HTTP handler function for importing instances. Submitting a file renders a dry-run
+// preview of the creates and updates it would make; the import is applied once the preview is confirmed.
+func (m *Model) GetImportHandler() HandlerFunc {
+	return func(data interface{}) (uint, string) {
+		m := m.withRequestContext(data)
+
+		createAllowed, err := m.App.Panel.PermissionChecker.HasModelCreatePermission(m.App.Name, m.Name, data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		updateAllowed, err := m.App.Panel.PermissionChecker.HasModelUpdatePermission(m.App.Name, m.Name, data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		if !createAllowed && !updateAllowed {
+			return GetErrorHTML(http.StatusForbidden, fmt.Errorf("forbidden"))
+		}
+
+		apps, err := GetAppsWithReadPermissions(m.App.Panel, data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+
+		templateData := map[string]interface{}{
+			"apps":        apps,
+			"navBarItems": m.App.Panel.Config.GetNavBarItems(data),
+			"model":       m,
+			"formats":     ExportFormats,
+		}
+
+		method := m.App.Panel.Web.GetRequestMethod(data)
+		if method == "POST" {
+			formData := m.App.Panel.Web.GetFormData(data)
+			if formData == nil {
+				return GetErrorHTML(http.StatusBadRequest, fmt.Errorf("form data is required"))
+			}
+
+			format, content, err := m.getImportContent(data, formData)
+			if errors.Is(err, ErrRequestTooLarge) {
+				return GetErrorHTML(http.StatusRequestEntityTooLarge, err)
+			}
+			if err != nil {
+				return GetErrorHTML(http.StatusBadRequest, err)
+			}
+
+			preview := &ImportPreview{}
+			records, err := m.ParseImportRecords(format, content)
+			if err != nil {
+				preview.Errors = append(preview.Errors, err.Error())
+			} else {
+				preview, err = m.PrepareImport(records, data)
+				if err != nil {
+					return GetErrorHTML(http.StatusInternalServerError, err)
+				}
+			}
+
+			var confirmed bool
+			if values := formData["confirm"]; len(values) > 0 {
+				confirmed = values[0] == "yes"
+			}
+
+			if confirmed && !preview.HasErrors() {
+				_, _, err = m.ApplyImport(preview, data)
+				if err != nil {
+					return GetErrorHTML(http.StatusInternalServerError, err)
+				}
+				return http.StatusSeeOther, m.GetFullLink()
+			}
+
+			templateData["preview"] = preview
+			templateData["format"] = format
+			templateData["content"] = string(content)
+		} else if method != "GET" {
+			return GetErrorHTML(http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		}
+
+		html, err := m.App.Panel.Config.Renderer.RenderTemplate("import", templateData)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		return http.StatusOK, html
+	}
+}
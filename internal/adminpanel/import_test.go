@@ -0,0 +1,268 @@
+package adminpanel
+
+import (
+	"github.com/go-advanced-admin/admin/internal/form"
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type ImportTestModel struct {
+	ID   uint   `admin:"addForm:exclude;editForm:exclude"`
+	Name string `admin:"required;maxLength:10"`
+	Age  int    `admin:"min:0"`
+}
+
+type ImportORMIntegrator struct {
+	MockORMIntegrator
+	Existing map[uint]*ImportTestModel
+	Created  []*ImportTestModel
+	Updated  map[uint]*ImportTestModel
+}
+
+func (o *ImportORMIntegrator) GetPrimaryKeyValue(instance interface{}) (interface{}, error) {
+	return instance.(*ImportTestModel).ID, nil
+}
+
+func (o *ImportORMIntegrator) GetPrimaryKeyType(interface{}) (reflect.Type, error) {
+	return reflect.TypeOf(uint(0)), nil
+}
+
+func (o *ImportORMIntegrator) FetchInstance(_ interface{}, id interface{}) (interface{}, error) {
+	instance, ok := o.Existing[id.(uint)]
+	if !ok {
+		return nil, nil
+	}
+	return instance, nil
+}
+
+func (o *ImportORMIntegrator) CreateInstanceOnlyFields(instance interface{}, _ []string) error {
+	created := instance.(*ImportTestModel)
+	created.ID = uint(100 + len(o.Created))
+	o.Created = append(o.Created, created)
+	return nil
+}
+
+func (o *ImportORMIntegrator) UpdateInstanceOnlyFields(instance interface{}, fields []string, primaryKey interface{}) error {
+	updated := instance.(*ImportTestModel)
+	updated.ID = primaryKey.(uint)
+	if existing, ok := o.Existing[updated.ID]; ok {
+		merged := *existing
+		for _, field := range fields {
+			reflect.ValueOf(&merged).Elem().FieldByName(field).Set(reflect.ValueOf(updated).Elem().FieldByName(field))
+		}
+		*updated = merged
+	}
+	o.Updated[updated.ID] = updated
+	o.Existing[updated.ID] = updated
+	return nil
+}
+
+func (o *ImportORMIntegrator) FetchInstancesOnlyFields(interface{}, []string) (interface{}, error) {
+	instances := make([]*ImportTestModel, 0, len(o.Existing))
+	for _, instance := range o.Existing {
+		instances = append(instances, instance)
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].ID < instances[j].ID })
+	return instances, nil
+}
+
+func (o *ImportORMIntegrator) FetchInstancesOnlyFieldWithSearch(model interface{}, fields []string, query string, _ []string) (interface{}, error) {
+	instances, _ := o.FetchInstancesOnlyFields(model, fields)
+	matching := make([]*ImportTestModel, 0)
+	for _, instance := range instances.([]*ImportTestModel) {
+		if strings.Contains(instance.Name, query) {
+			matching = append(matching, instance)
+		}
+	}
+	return matching, nil
+}
+
+func (o *ImportORMIntegrator) DeleteInstance(_ interface{}, id interface{}) error {
+	delete(o.Existing, id.(uint))
+	return nil
+}
+
+// newImportTestORM returns an ORM integrator holding a single existing instance, Alice.
+func newImportTestORM() *ImportORMIntegrator {
+	return &ImportORMIntegrator{
+		Existing: map[uint]*ImportTestModel{1: {ID: 1, Name: "Alice", Age: 30}},
+		Updated:  make(map[uint]*ImportTestModel),
+	}
+}
+
+func TestModel_ParseImportRecords(t *testing.T) {
+	model := newTestModel(t, &ImportTestModel{}, newImportTestORM())
+
+	tests := []struct {
+		name     string
+		format   ExportFormat
+		content  string
+		expected []map[string]form.HTMLType
+	}{
+		{"CSV", ExportFormatCSV, "ID,Name\n1,Bob\n,Carol\n", []map[string]form.HTMLType{{"ID": "1", "Name": "Bob"}, {"ID": "", "Name": "Carol"}}},
+		{"JSON", ExportFormatJSON, `[{"ID": 1, "Name": "Bob", "Age": null}]`, []map[string]form.HTMLType{{"ID": "1", "Name": "Bob", "Age": ""}}},
+		{"JSONL", ExportFormatJSONL, "{\"Name\": \"Bob\"}\n\n{\"Age\": 4}\n", []map[string]form.HTMLType{{"Name": "Bob"}, {"Age": "4"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := model.ParseImportRecords(tt.format, []byte(tt.content))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(records, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, records)
+			}
+		})
+	}
+
+	if _, err := model.ParseImportRecords(ExportFormatCSV, []byte("Unknown\nx\n")); err == nil {
+		t.Error("expected an error for an unknown column")
+	}
+}
+
+func TestModel_PrepareAndApplyImport(t *testing.T) {
+	orm := newImportTestORM()
+	model := newTestModel(t, &ImportTestModel{}, orm)
+	store := logging.NewInMemoryLogStore(100)
+	model.App.Panel.Config.LogStore = store
+
+	records, err := model.ParseImportRecords(ExportFormatCSV, []byte("ID,Name,Age\n1,Alicia,\n,Bob,20\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	preview, err := model.PrepareImport(records, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if preview.HasErrors() {
+		t.Fatalf("expected no errors, got %v", preview.Rows[0].Errors)
+	}
+	if preview.CountKind(ImportRowUpdate) != 1 || preview.CountKind(ImportRowCreate) != 1 {
+		t.Fatalf("expected one update and one create")
+	}
+	update := preview.Rows[0]
+	if len(update.Changes) != 2 {
+		t.Fatalf("expected the name and age to change, got %v", update.Changes)
+	}
+	if len(orm.Created) != 0 || len(orm.Updated) != 0 {
+		t.Fatalf("expected the preview not to save anything")
+	}
+
+	created, updated, err := model.ApplyImport(preview, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created != 1 || updated != 1 {
+		t.Errorf("expected 1 created and 1 updated, got %d and %d", created, updated)
+	}
+	if orm.Updated[1].Name != "Alicia" || orm.Created[0].Name != "Bob" {
+		t.Errorf("unexpected saved instances: %v, %v", orm.Updated[1], orm.Created[0])
+	}
+
+	entries, _ := store.GetLogEntries()
+	if len(entries) != 2 {
+		t.Errorf("expected 2 log entries, got %d", len(entries))
+	}
+}
+
+type CodedImportORMIntegrator struct {
+	CodedORMIntegrator
+	Existing map[string]*CodedTestModel
+}
+
+func (o *CodedImportORMIntegrator) FetchInstance(_ interface{}, id interface{}) (interface{}, error) {
+	instance, ok := o.Existing[id.(string)]
+	if !ok {
+		return nil, nil
+	}
+	return instance, nil
+}
+
+func (o *CodedImportORMIntegrator) FetchInstanceOnlyFields(model interface{}, id interface{}, _ []string) (interface{}, error) {
+	return o.FetchInstance(model, id)
+}
+
+func TestModel_PrepareImport_PrimaryKeyColumn(t *testing.T) {
+	orm := &CodedImportORMIntegrator{Existing: map[string]*CodedTestModel{"a": {Name: "First", Code: "a", Label: "old"}}}
+	model := newTestModel(t, &CodedTestModel{}, orm)
+
+	records, err := model.ParseImportRecords(ExportFormatCSV, []byte("Code,Name,Label\na,First,new\nb,Second,label\n,Third,label\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	preview, err := model.PrepareImport(records, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if preview.HasErrors() {
+		t.Fatalf("expected no errors, got %+v", preview.Rows)
+	}
+	if preview.Rows[0].Kind != ImportRowUpdate || preview.Rows[0].InstanceID != "a" {
+		t.Errorf("expected the first row to update instance a, got %+v", preview.Rows[0])
+	}
+	if preview.Rows[1].Kind != ImportRowCreate || preview.Rows[2].Kind != ImportRowCreate {
+		t.Errorf("expected the missing and empty keys to create instances, got %s and %s", preview.Rows[1].Kind, preview.Rows[2].Kind)
+	}
+}
+
+func TestModel_PrepareImport_Errors(t *testing.T) {
+	model := newTestModel(t, &ImportTestModel{}, newImportTestORM())
+
+	records, err := model.ParseImportRecords(ExportFormatCSV, []byte("ID,Name,Age\n,,5\n,Bob,abc\n99,Ghost,1\n,Dave,-1\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	preview, err := model.PrepareImport(records, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, row := range preview.Rows {
+		if !row.HasErrors() {
+			t.Errorf("expected row %d to have errors", row.Number)
+		}
+	}
+	if _, _, err = model.ApplyImport(preview, nil); err == nil {
+		t.Error("expected an error when applying an import with errors")
+	}
+}
+
+func TestModel_GetImportHandler(t *testing.T) {
+	orm := newImportTestORM()
+	model := newTestModel(t, &ImportTestModel{}, orm)
+
+	code, html := model.GetImportHandler()(map[string]string{"method": "GET"})
+	if code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", code, html)
+	}
+
+	model.App.Panel.Web = &importWebIntegrator{method: "POST"}
+	code, html = model.GetImportHandler()(map[string][]string{"data": {"Name\nBob\n"}})
+	if code != http.StatusOK || !strings.Contains(html, "Apply import") {
+		t.Fatalf("expected the preview to render, got %d: %s", code, html)
+	}
+	if len(orm.Created) != 0 {
+		t.Fatalf("expected the preview not to save anything")
+	}
+
+	code, _ = model.GetImportHandler()(map[string][]string{"data": {"Name\nBob\n"}, "confirm": {"yes"}})
+	if code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d", code)
+	}
+	if len(orm.Created) != 1 {
+		t.Errorf("expected one created instance, got %d", len(orm.Created))
+	}
+}
+
+type importWebIntegrator struct {
+	MockWebIntegrator
+	method string
+}
+
+func (w *importWebIntegrator) GetRequestMethod(interface{}) string {
+	return w.method
+}
@@ -2,6 +2,7 @@ package adminpanel
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-advanced-admin/admin/internal/form"
 	"github.com/go-advanced-admin/admin/internal/form/forms"
@@ -35,30 +36,82 @@ func (i *Instance) GetRepr() string {
 
 // CreateViewLog creates a log entry when the instance is viewed.
 func (i *Instance) CreateViewLog(ctx interface{}) error {
-	return i.Model.App.Panel.Config.CreateLog(ctx, logging.LogStoreLevelInstanceView, fmt.Sprintf("%s | %s", i.Model.App.Name, i.Model.DisplayName), i.InstanceID, i.GetRepr(), "")
+	return i.Model.App.Panel.Config.CreateLog(ctx, logging.LogStoreLevelInstanceView, i.Model.GetLogContentType(), i.InstanceID, i.GetRepr(), "")
 }
 
-// CreateUpdateLog creates a log entry when the instance is updated.
-func (i *Instance) CreateUpdateLog(ctx interface{}, updates map[string]interface{}) error {
-	message, err := json.Marshal(updates)
+// CreateHistoryViewLog creates a log entry when the history of the instance is viewed. The history stays available
+// after the instance is deleted, so the entry is not given the instance representation.
+func (i *Instance) CreateHistoryViewLog(ctx interface{}) error {
+	message, err := json.Marshal(map[string]string{"page": "history"})
 	if err != nil {
 		return err
 	}
-	return i.Model.App.Panel.Config.CreateLog(ctx, logging.LogStoreLevelUpdate, fmt.Sprintf("%s | %s", i.Model.App.Name, i.Model.DisplayName), i.InstanceID, i.GetRepr(), string(message))
+	return i.Model.App.Panel.Config.CreateLog(ctx, logging.LogStoreLevelInstanceView, i.Model.GetLogContentType(), i.InstanceID, "", string(message))
 }
 
-// CreateCreateLog creates a log entry when the instance is created.
+// CreateUpdateLog creates a log entry when the instance is updated, recording the diff of the changed fields. The
+// values of sensitive fields are redacted.
+func (i *Instance) CreateUpdateLog(ctx interface{}, diff map[string]logging.FieldDiff) error {
+	redacted := make(map[string]logging.FieldDiff, len(diff))
+	for field, fieldDiff := range diff {
+		if fieldConfig, ok := i.Model.GetFieldConfig(field); ok && fieldConfig.Sensitive {
+			fieldDiff = logging.FieldDiff{Old: logging.RedactedValue, New: logging.RedactedValue}
+		}
+		redacted[field] = fieldDiff
+	}
+	message, err := json.Marshal(redacted)
+	if err != nil {
+		return err
+	}
+	return i.Model.App.Panel.CreateLog(ctx, logging.LogStoreLevelUpdate, i.Model.GetLogContentType(), i.InstanceID, i.GetRepr(), string(message))
+}
+
+// CreateCreateLog creates a log entry when the instance is created, recording the values of its fields. The values of
+// sensitive fields are redacted.
 func (i *Instance) CreateCreateLog(ctx interface{}) error {
-	message, err := json.Marshal(i.Data)
+	values := make(map[string]interface{}, len(i.Model.Fields))
+	for _, fieldConfig := range i.Model.Fields {
+		if fieldConfig.Sensitive {
+			values[fieldConfig.Name] = logging.RedactedValue
+			continue
+		}
+		value, err := utils.GetFieldValue(i.Data, fieldConfig.Name)
+		if err != nil {
+			return err
+		}
+		values[fieldConfig.Name] = dereferenceImportValue(value)
+	}
+	message, err := json.Marshal(values)
 	if err != nil {
 		return err
 	}
-	return i.Model.App.Panel.Config.CreateLog(ctx, logging.LogStoreLevelCreate, fmt.Sprintf("%s | %s", i.Model.App.Name, i.Model.DisplayName), i.InstanceID, i.GetRepr(), string(message))
+	return i.Model.App.Panel.CreateLog(ctx, logging.LogStoreLevelCreate, i.Model.GetLogContentType(), i.InstanceID, i.GetRepr(), string(message))
+}
+
+// DiffInstance compares the values of the given fields with those of the instance and returns the changed fields with
+// their old and new values.
+func (m *Model) DiffInstance(instance interface{}, values map[string]interface{}) (map[string]logging.FieldDiff, error) {
+	diff := make(map[string]logging.FieldDiff)
+	for _, fieldConfig := range m.Fields {
+		newValue, ok := values[fieldConfig.Name]
+		if !ok {
+			continue
+		}
+		oldValue, err := utils.GetFieldValue(instance, fieldConfig.Name)
+		if err != nil {
+			return nil, err
+		}
+		oldValue, newValue = dereferenceImportValue(oldValue), dereferenceImportValue(newValue)
+		if utils.CompareValues(oldValue, newValue) != 0 {
+			diff[fieldConfig.Name] = logging.FieldDiff{Old: oldValue, New: newValue}
+		}
+	}
+	return diff, nil
 }
 
 // CreateDeleteLog creates a log entry when the instance is deleted.
 func (i *Instance) CreateDeleteLog(ctx interface{}) error {
-	return i.Model.App.Panel.Config.CreateLog(ctx, logging.LogStoreLevelDelete, fmt.Sprintf("%s | %s", i.Model.App.Name, i.Model.DisplayName), i.InstanceID, i.GetRepr(), "")
+	return i.Model.App.Panel.CreateLog(ctx, logging.LogStoreLevelDelete, i.Model.GetLogContentType(), i.InstanceID, i.GetRepr(), "")
 }
 
 // GetLink returns the relative URL to view the instance.
@@ -83,6 +136,8 @@ func (i *Instance) GetFullEditLink() string {
 
 func (m *Model) GetInstanceDeleteHandler() HandlerFunc {
 	return func(data interface{}) (uint, string) {
+		m := m.withRequestContext(data)
+
 		instanceIDStr := m.App.Panel.Web.GetPathParam(data, "id")
 		if instanceIDStr == "" {
 			return GetErrorHTML(http.StatusBadRequest, fmt.Errorf("instance id is required"))
@@ -110,17 +165,24 @@ func (m *Model) GetInstanceDeleteHandler() HandlerFunc {
 			return GetErrorHTML(http.StatusForbidden, fmt.Errorf("you are not allowed to delete this instance"))
 		}
 
-		err = m.GetORM().DeleteInstance(m.PTR, instanceIDInterface)
+		scope, err := m.GetQueryScope(data)
 		if err != nil {
 			return GetErrorHTML(http.StatusInternalServerError, err)
 		}
+		err = m.WithTransaction(data, func(tm *Model) error {
+			if err := tm.DeleteInstanceInScope(instanceIDInterface, scope); err != nil {
+				return err
+			}
 
-		instance := &Instance{
-			InstanceID: instanceIDInterface,
-			Model:      m,
+			instance := &Instance{
+				InstanceID: instanceIDInterface,
+				Model:      m,
+			}
+			return instance.CreateDeleteLog(data)
+		})
+		if errors.Is(err, ErrOutOfScope) {
+			return GetErrorHTML(http.StatusNotFound, err)
 		}
-
-		err = instance.CreateDeleteLog(data)
 		if err != nil {
 			return GetErrorHTML(http.StatusInternalServerError, err)
 		}
@@ -131,6 +193,8 @@ func (m *Model) GetInstanceDeleteHandler() HandlerFunc {
 
 func (m *Model) GetInstanceViewHandler() HandlerFunc {
 	return func(data interface{}) (uint, string) {
+		m := m.withRequestContext(data)
+
 		instanceIDStr := m.App.Panel.Web.GetPathParam(data, "id")
 		if instanceIDStr == "" {
 			return GetErrorHTML(http.StatusBadRequest, fmt.Errorf("instance id is required"))
@@ -163,24 +227,26 @@ func (m *Model) GetInstanceViewHandler() HandlerFunc {
 			return GetErrorHTML(http.StatusInternalServerError, err)
 		}
 
-		var fieldsToFetch []string
-		for _, fieldConfig := range m.Fields {
-			if fieldConfig.IncludeInInstanceView {
-				fieldsToFetch = append(fieldsToFetch, fieldConfig.Name)
-			}
+		instanceFields, err := m.FilterReadableFields(m.getFieldsWhere(func(fieldConfig FieldConfig) bool {
+			return fieldConfig.IncludeInInstanceView
+		}), instanceIDInterface, data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
 		}
 
-		instanceData, err := m.GetORM().FetchInstanceOnlyFields(m.PTR, instanceIDInterface, fieldsToFetch)
+		scope, err := m.GetQueryScope(data)
 		if err != nil {
 			return GetErrorHTML(http.StatusInternalServerError, err)
 		}
+		instanceData, err := m.FetchInstanceInScope(instanceIDInterface, getFieldNames(instanceFields), scope)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		if isNilInstance(instanceData) {
+			return GetErrorHTML(http.StatusNotFound, ErrOutOfScope)
+		}
 
-		html, err := m.App.Panel.Config.Renderer.RenderTemplate("instance", map[string]interface{}{
-			"model":       m,
-			"apps":        apps,
-			"navBarItems": m.App.Panel.Config.GetNavBarItems(data),
-			"instance":    instanceData,
-		})
+		instanceActions, err := m.GetInstanceActionsWithPermission(instanceIDInterface, data)
 		if err != nil {
 			return GetErrorHTML(http.StatusInternalServerError, err)
 		}
@@ -189,6 +255,20 @@ func (m *Model) GetInstanceViewHandler() HandlerFunc {
 			InstanceID: instanceIDInterface,
 			Model:      m,
 		}
+
+		html, err := m.App.Panel.Config.Renderer.RenderTemplate("instance", map[string]interface{}{
+			"model":           m,
+			"apps":            apps,
+			"navBarItems":     m.App.Panel.Config.GetNavBarItems(data),
+			"instance":        instanceData,
+			"instanceRef":     instance,
+			"fields":          instanceFields,
+			"instanceActions": instanceActions,
+		})
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+
 		err = instance.CreateViewLog(data)
 		if err != nil {
 			return GetErrorHTML(http.StatusInternalServerError, err)
@@ -278,10 +358,22 @@ type ModelEditForm struct {
 	forms.BaseForm
 	Model      *Model
 	InstanceID interface{}
+	// ReadOnlyFields holds the names of the fields the user may not update. They are rendered disabled and keep their
+	// stored value when saving.
+	ReadOnlyFields map[string]bool
+	// Scope holds the query scope of the user. Saving fails with ErrOutOfScope when the instance lies outside of it.
+	Scope []FilterExpression
 }
 
-// Save processes the form data and updates the existing instance of the model.
+// Save processes the form data and updates the existing instance of the model. Values submitted for read-only fields
+// are rejected with ErrFieldNotWritable.
 func (f *ModelEditForm) Save(values map[string]form.HTMLType) (interface{}, error) {
+	for fieldName := range values {
+		if f.ReadOnlyFields[fieldName] {
+			return nil, fmt.Errorf("%w: %s", ErrFieldNotWritable, fieldName)
+		}
+	}
+
 	cleanValues, err := form.GetCleanData(f, values)
 	if err != nil {
 		return nil, err
@@ -292,6 +384,9 @@ func (f *ModelEditForm) Save(values map[string]form.HTMLType) (interface{}, erro
 	instanceVal := instancePtr.Elem()
 
 	for fieldName, value := range cleanValues {
+		if f.ReadOnlyFields[fieldName] {
+			continue
+		}
 		fieldVal := instanceVal.FieldByName(fieldName)
 
 		if !fieldVal.IsValid() {
@@ -336,12 +431,12 @@ func (f *ModelEditForm) Save(values map[string]form.HTMLType) (interface{}, erro
 
 	fieldsToInclude := make([]string, 0)
 	for _, field := range f.Model.Fields {
-		if field.EditFormField != nil {
+		if field.EditFormField != nil && !f.ReadOnlyFields[field.Name] {
 			fieldsToInclude = append(fieldsToInclude, field.Name)
 		}
 	}
 
-	err = f.Model.GetORM().UpdateInstanceOnlyFields(instancePtr.Interface(), fieldsToInclude, f.InstanceID)
+	err = f.Model.UpdateInstanceInScope(instancePtr.Interface(), fieldsToInclude, f.InstanceID, f.Scope)
 	if err != nil {
 		return nil, err
 	}
@@ -370,9 +465,17 @@ func (m *Model) NewAddForm() (form.Form, error) {
 
 // NewEditForm creates a new form for editing an existing instance of the model.
 func (m *Model) NewEditForm(instanceID interface{}) (form.Form, error) {
+	return m.NewEditFormWithPermissions(instanceID, nil)
+}
+
+// NewEditFormWithPermissions creates a new form for editing an existing instance of the model, applying the given
+// field permissions. Fields the user may not read are left out of the form and fields the user may not update are
+// read-only. Fields missing from the permissions map are editable.
+func (m *Model) NewEditFormWithPermissions(instanceID interface{}, fieldPermissions map[string]Permissions) (form.Form, error) {
 	f := &ModelEditForm{
-		Model:      m,
-		InstanceID: instanceID,
+		Model:          m,
+		InstanceID:     instanceID,
+		ReadOnlyFields: make(map[string]bool),
 	}
 
 	for _, fieldConfig := range m.Fields {
@@ -380,7 +483,16 @@ func (m *Model) NewEditForm(instanceID interface{}) (form.Form, error) {
 			continue
 		}
 
-		err := f.AddField(fieldConfig.Name, fieldConfig.EditFormField)
+		field := fieldConfig.EditFormField
+		if permissions, ok := fieldPermissions[fieldConfig.Name]; ok && !(permissions.Read && permissions.Update) {
+			f.ReadOnlyFields[fieldConfig.Name] = true
+			if !permissions.Read {
+				continue
+			}
+			field = form.NewReadOnlyField(field)
+		}
+
+		err := f.AddField(fieldConfig.Name, field)
 		if err != nil {
 			return nil, err
 		}
@@ -391,6 +503,8 @@ func (m *Model) NewEditForm(instanceID interface{}) (form.Form, error) {
 // GetAddHandler returns the HTTP handler function for adding a new instance.
 func (m *Model) GetAddHandler() HandlerFunc {
 	return func(data interface{}) (uint, string) {
+		m := m.withRequestContext(data)
+
 		allowed, err := m.App.Panel.PermissionChecker.HasModelCreatePermission(m.App.Name, m.Name, data)
 		if err != nil {
 			return GetErrorHTML(http.StatusInternalServerError, err)
@@ -473,33 +587,34 @@ func (m *Model) GetAddHandler() HandlerFunc {
 				return http.StatusOK, html
 			}
 
-			instanceInterface, err := formInstance.Save(convertedFormData)
-			if err != nil {
-				return GetErrorHTML(http.StatusInternalServerError, err)
-			}
-
-			instance := instanceInterface
-			instanceID, err := m.GetPrimaryKeyValue(instance)
-			if err != nil {
-				return GetErrorHTML(http.StatusInternalServerError, err)
-			}
-			if instanceID == nil {
-				return GetErrorHTML(http.StatusInternalServerError, fmt.Errorf("instance id is nil"))
-			}
-
-			instanceLink := fmt.Sprintf("%s/%v/view", m.GetFullLink(), instanceID)
+			var instanceID interface{}
+			err = m.WithTransaction(data, func(tm *Model) error {
+				bindForm(formInstance, tm)
+				instance, err := formInstance.Save(convertedFormData)
+				if err != nil {
+					return err
+				}
 
-			instanceInstance := &Instance{
-				InstanceID: instanceID,
-				Data:       instance,
-				Model:      m,
-			}
+				instanceID, err = m.GetPrimaryKeyValue(instance)
+				if err != nil {
+					return err
+				}
+				if instanceID == nil {
+					return fmt.Errorf("instance id is nil")
+				}
 
-			err = instanceInstance.CreateCreateLog(data)
+				instanceInstance := &Instance{
+					InstanceID: instanceID,
+					Data:       instance,
+					Model:      m,
+				}
+				return instanceInstance.CreateCreateLog(data)
+			})
 			if err != nil {
 				return GetErrorHTML(http.StatusInternalServerError, err)
 			}
 
+			instanceLink := fmt.Sprintf("%s/%v/view", m.GetFullLink(), instanceID)
 			return http.StatusSeeOther, instanceLink
 		} else {
 			return GetErrorHTML(http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
@@ -510,6 +625,8 @@ func (m *Model) GetAddHandler() HandlerFunc {
 // GetEditHandler returns the HTTP handler function for editing an existing instance.
 func (m *Model) GetEditHandler() HandlerFunc {
 	return func(data interface{}) (uint, string) {
+		m := m.withRequestContext(data)
+
 		instanceIDStr := m.App.Panel.Web.GetPathParam(data, "id")
 		if instanceIDStr == "" {
 			return GetErrorHTML(http.StatusBadRequest, fmt.Errorf("instance id is required"))
@@ -537,26 +654,41 @@ func (m *Model) GetEditHandler() HandlerFunc {
 			return GetErrorHTML(http.StatusForbidden, fmt.Errorf("you are not allowed to view this instance"))
 		}
 
+		// The edit form fields are fetched even when hidden from the instance view, so they get their initial values and
+		// the changes are diffed against their stored values.
 		var fieldsToFetch []string
 		for _, fieldConfig := range m.Fields {
-			if fieldConfig.IncludeInInstanceView {
+			if fieldConfig.IncludeInInstanceView || fieldConfig.EditFormField != nil {
 				fieldsToFetch = append(fieldsToFetch, fieldConfig.Name)
 			}
 		}
 
-		instanceData, err := m.App.Panel.ORM.FetchInstanceOnlyFields(m.PTR, instanceIDInterface, fieldsToFetch)
+		scope, err := m.GetQueryScope(data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		instanceData, err := m.FetchInstanceInScope(instanceIDInterface, fieldsToFetch, scope)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		if isNilInstance(instanceData) {
+			return GetErrorHTML(http.StatusNotFound, ErrOutOfScope)
+		}
+
+		fieldPermissions, err := m.GetFieldPermissions(instanceIDInterface, data)
 		if err != nil {
 			return GetErrorHTML(http.StatusInternalServerError, err)
 		}
 
-		formInstance, err := m.NewEditForm(instanceIDInterface)
+		formInstance, err := m.NewEditFormWithPermissions(instanceIDInterface, fieldPermissions)
 		if err != nil {
 			return GetErrorHTML(http.StatusInternalServerError, err)
 		}
+		formInstance.(*ModelEditForm).Scope = scope
 
 		initialValuesMap := make(map[string]interface{})
 		for _, field := range m.Fields {
-			if field.EditFormField == nil {
+			if field.EditFormField == nil || !fieldPermissions[field.Name].Read {
 				continue
 			}
 			fieldValue := reflect.ValueOf(instanceData).Elem().FieldByName(field.Name)
@@ -610,6 +742,9 @@ func (m *Model) GetEditHandler() HandlerFunc {
 			if err != nil {
 				return GetErrorHTML(http.StatusInternalServerError, err)
 			}
+			for fieldName := range formInstance.(*ModelEditForm).ReadOnlyFields {
+				delete(cleanFormData, fieldName)
+			}
 			formErrs, fieldErrs, err := form.ValuesAreValid(formInstance, cleanFormData)
 			if err != nil {
 				return GetErrorHTML(http.StatusInternalServerError, err)
@@ -646,33 +781,45 @@ func (m *Model) GetEditHandler() HandlerFunc {
 				return http.StatusOK, html
 			}
 
-			instanceInterface, err := formInstance.Save(convertedFormData)
+			diff, err := m.DiffInstance(instanceData, cleanFormData)
 			if err != nil {
 				return GetErrorHTML(http.StatusInternalServerError, err)
 			}
 
-			instance := instanceInterface
-			instanceID, err := m.GetPrimaryKeyValue(instance)
-			if err != nil {
-				return GetErrorHTML(http.StatusInternalServerError, err)
-			}
-			if instanceID == nil {
-				return GetErrorHTML(http.StatusInternalServerError, fmt.Errorf("instance id is nil"))
-			}
+			var instanceID interface{}
+			err = m.WithTransaction(data, func(tm *Model) error {
+				bindForm(formInstance, tm)
+				instance, err := formInstance.Save(convertedFormData)
+				if err != nil {
+					return err
+				}
 
-			instanceLink := fmt.Sprintf("%s/%v/view", m.GetFullLink(), instanceID)
+				instanceID, err = m.GetPrimaryKeyValue(instance)
+				if err != nil {
+					return err
+				}
+				if instanceID == nil {
+					return fmt.Errorf("instance id is nil")
+				}
 
-			instanceInstance := &Instance{
-				InstanceID: instanceID,
-				Data:       instance,
-				Model:      m,
+				instanceInstance := &Instance{
+					InstanceID: instanceID,
+					Data:       instance,
+					Model:      m,
+				}
+				return instanceInstance.CreateUpdateLog(data, diff)
+			})
+			if errors.Is(err, ErrFieldNotWritable) {
+				return GetErrorHTML(http.StatusForbidden, err)
+			}
+			if errors.Is(err, ErrOutOfScope) {
+				return GetErrorHTML(http.StatusNotFound, err)
 			}
-
-			err = instanceInstance.CreateUpdateLog(data, cleanFormData)
 			if err != nil {
 				return GetErrorHTML(http.StatusInternalServerError, err)
 			}
 
+			instanceLink := fmt.Sprintf("%s/%v/view", m.GetFullLink(), instanceID)
 			return http.StatusSeeOther, instanceLink
 		} else {
 			return GetErrorHTML(http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
@@ -0,0 +1,171 @@
+package adminpanel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"github.com/go-advanced-admin/admin/internal/utils"
+	"net/http"
+)
+
+// InstanceActionFunc performs an action on the instance with the given primary key and returns a message
+// describing the result. The model it receives is bound to the transaction the action runs in, so writes made through
+// its ORM integrator are rolled back with the action.
+type InstanceActionFunc = func(tm *Model, ctx interface{}, id interface{}) (string, error)
+
+// InstanceAction represents a one-click operation that can be run on a single instance from its view page.
+type InstanceAction struct {
+	Name        string
+	DisplayName string
+	Handler     InstanceActionFunc
+	Permission  Action
+}
+
+// InstanceActionPermission returns the permission action checked before running the instance action with the given name.
+func InstanceActionPermission(name string) Action {
+	return Action("instance:" + name)
+}
+
+// RegisterInstanceAction registers an action that can be run on a single instance of the model. Users need the
+// permission returned by InstanceActionPermission(name) on the instance to run it.
+func (m *Model) RegisterInstanceAction(name, displayName string, handler InstanceActionFunc) error {
+	if handler == nil {
+		return fmt.Errorf("instance action handler cannot be nil")
+	}
+	if name == "" || !utils.IsURLSafe(name) {
+		return fmt.Errorf("instance action name '%s' is not URL safe", name)
+	}
+	if _, exists := m.GetInstanceAction(name); exists {
+		return fmt.Errorf("instance action '%s' already exists in model '%s'. Instance actions cannot be registered more than once", name, m.Name)
+	}
+	m.InstanceActions = append(m.InstanceActions, &InstanceAction{
+		Name:        name,
+		DisplayName: displayName,
+		Handler:     handler,
+		Permission:  InstanceActionPermission(name),
+	})
+	return nil
+}
+
+// GetInstanceAction returns the instance action with the given name.
+func (m *Model) GetInstanceAction(name string) (*InstanceAction, bool) {
+	for _, action := range m.InstanceActions {
+		if action.Name == name {
+			return action, true
+		}
+	}
+	return nil, false
+}
+
+// GetInstanceActionsWithPermission returns the instance actions the user may run on the given instance.
+func (m *Model) GetInstanceActionsWithPermission(instanceID interface{}, data interface{}) ([]*InstanceAction, error) {
+	actions := make([]*InstanceAction, 0)
+	for _, action := range m.InstanceActions {
+		permission := action.Permission
+		allowed, err := m.App.Panel.PermissionChecker.HasPermission(PermissionRequest{AppName: &m.App.Name, ModelName: &m.Name, Action: &permission, InstanceID: instanceID}, data)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			actions = append(actions, action)
+		}
+	}
+	return actions, nil
+}
+
+// GetActionLink returns the relative URL to run the named action on the instance.
+func (i *Instance) GetActionLink(name string) string {
+	return fmt.Sprintf("%s/%v/action/%s", i.Model.GetLink(), i.InstanceID, name)
+}
+
+// GetFullActionLink returns the full URL to run the named action on the instance.
+func (i *Instance) GetFullActionLink(name string) string {
+	return i.Model.App.Panel.Config.GetLink(i.GetActionLink(name))
+}
+
+// CreateInstanceActionLog creates a log entry when an action is run on the instance.
+func (i *Instance) CreateInstanceActionLog(ctx interface{}, actionName string, result string) error {
+	message, err := json.Marshal(map[string]string{"action": actionName, "result": result})
+	if err != nil {
+		return err
+	}
+	return i.CreateActionLog(ctx, logging.LogStoreLevelAction, string(message))
+}
+
+// CreateInstanceActionFailureLog creates a log entry when an action run on the instance fails, recording its error.
+func (i *Instance) CreateInstanceActionFailureLog(ctx interface{}, actionName string, actionErr error) error {
+	message, err := json.Marshal(map[string]string{"action": actionName, "error": actionErr.Error()})
+	if err != nil {
+		return err
+	}
+	return i.CreateActionLog(ctx, logging.LogStoreLevelAction, string(message))
+}
+
+// GetInstanceActionHandler returns the HTTP handler function for running an action on a single instance.
+func (m *Model) GetInstanceActionHandler() HandlerFunc {
+	return func(data interface{}) (uint, string) {
+		m := m.withRequestContext(data)
+
+		instanceIDStr := m.App.Panel.Web.GetPathParam(data, "id")
+		if instanceIDStr == "" {
+			return GetErrorHTML(http.StatusBadRequest, fmt.Errorf("instance id is required"))
+		}
+
+		instanceID, err := m.ParseInstanceID(instanceIDStr)
+		if err != nil {
+			return GetErrorHTML(http.StatusBadRequest, err)
+		}
+
+		actionName := m.App.Panel.Web.GetPathParam(data, "name")
+		action, ok := m.GetInstanceAction(actionName)
+		if !ok {
+			return GetErrorHTML(http.StatusNotFound, fmt.Errorf("unknown instance action '%s'", actionName))
+		}
+
+		permission := action.Permission
+		allowed, err := m.App.Panel.PermissionChecker.HasPermission(PermissionRequest{AppName: &m.App.Name, ModelName: &m.Name, Action: &permission, InstanceID: instanceID}, data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		if !allowed {
+			return GetErrorHTML(http.StatusForbidden, fmt.Errorf("you are not allowed to run '%s' on this instance", action.DisplayName))
+		}
+
+		scope, err := m.GetQueryScope(data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		instanceData, err := m.FetchInstanceInScope(instanceID, nil, scope)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		if isNilInstance(instanceData) {
+			return GetErrorHTML(http.StatusNotFound, ErrOutOfScope)
+		}
+
+		instance := &Instance{
+			InstanceID: instanceID,
+			Data:       instanceData,
+			Model:      m,
+		}
+		var actionErr error
+		err = m.WithTransaction(data, func(tm *Model) error {
+			result, err := action.Handler(tm, data, instanceID)
+			if err != nil {
+				actionErr = err
+				return err
+			}
+			return instance.CreateInstanceActionLog(data, action.Name, result)
+		})
+		if actionErr != nil {
+			// The failed attempt is logged after the rollback so its entry is not rolled back with the action.
+			err = errors.Join(err, instance.CreateInstanceActionFailureLog(data, action.Name, actionErr))
+		}
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+
+		return http.StatusSeeOther, instance.GetFullLink()
+	}
+}
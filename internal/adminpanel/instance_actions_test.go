@@ -0,0 +1,120 @@
+package adminpanel
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"net/http"
+	"testing"
+)
+
+func TestModel_RegisterInstanceAction(t *testing.T) {
+	model := newTestModel(t, &TestModel{}, &ActionsORMIntegrator{})
+	handler := func(*Model, interface{}, interface{}) (string, error) { return "done", nil }
+
+	if err := model.RegisterInstanceAction("resend_email", "Resend welcome email", handler); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := model.RegisterInstanceAction("resend_email", "Resend welcome email", handler); err == nil {
+		t.Error("expected an error when registering the same instance action twice")
+	}
+	if err := model.RegisterInstanceAction("", "Empty", handler); err == nil {
+		t.Error("expected an error for an empty instance action name")
+	}
+	if err := model.RegisterInstanceAction("lock", "Lock", nil); err == nil {
+		t.Error("expected an error for a nil handler")
+	}
+
+	instance := &Instance{InstanceID: 5, Model: model}
+	if link := instance.GetFullActionLink("resend_email"); link != "/admin/a/TestApp/TestModel/5/action/resend_email" {
+		t.Errorf("unexpected action link: %s", link)
+	}
+}
+
+func TestModel_GetInstanceActionHandler(t *testing.T) {
+	model := newTestModel(t, &TestModel{}, &ActionsORMIntegrator{})
+	store := logging.NewInMemoryLogStore(100)
+	model.App.Panel.Config.LogStore = store
+
+	var receivedID interface{}
+	err := model.RegisterInstanceAction("lock", "Lock account", func(_ *Model, _ interface{}, id interface{}) (string, error) {
+		receivedID = id
+		return "account locked", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	code, _ := model.GetInstanceActionHandler()(map[string]string{"id": "3", "name": "lock"})
+	if code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d", code)
+	}
+	if receivedID != "3" {
+		t.Errorf("expected handler to receive id 3, got %v", receivedID)
+	}
+
+	entries, _ := store.GetLogEntries()
+	if len(entries) != 1 || entries[0].ActionFlag != logging.LogStoreLevelAction {
+		t.Fatalf("expected one action log entry, got %v", entries)
+	}
+	var message map[string]string
+	if err = json.Unmarshal([]byte(entries[0].Message), &message); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if message["action"] != "lock" || message["result"] != "account locked" {
+		t.Errorf("unexpected log message: %v", message)
+	}
+
+	code, _ = model.GetInstanceActionHandler()(map[string]string{"id": "3", "name": "unknown"})
+	if code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", code)
+	}
+
+	model.App.Panel.PermissionChecker = func(req PermissionRequest, _ interface{}) (bool, error) {
+		return *req.Action != InstanceActionPermission("lock"), nil
+	}
+	code, _ = model.GetInstanceActionHandler()(map[string]string{"id": "3", "name": "lock"})
+	if code != http.StatusForbidden {
+		t.Errorf("expected status 403, got %d", code)
+	}
+}
+
+func TestModel_GetInstanceActionHandler_Failure(t *testing.T) {
+	model := newTestModel(t, &TestModel{}, &ActionsORMIntegrator{})
+	store := logging.NewInMemoryLogStore(100)
+	model.App.Panel.Config.LogStore = store
+	_ = model.RegisterInstanceAction("lock", "Lock account", func(*Model, interface{}, interface{}) (string, error) {
+		return "", errors.New("account is already locked")
+	})
+
+	code, _ := model.GetInstanceActionHandler()(map[string]string{"id": "3", "name": "lock"})
+	if code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", code)
+	}
+
+	entries, _ := store.GetLogEntries()
+	if len(entries) != 1 || entries[0].ActionFlag != logging.LogStoreLevelAction {
+		t.Fatalf("expected the failed attempt to be logged, got %v", entries)
+	}
+	var message map[string]string
+	if err := json.Unmarshal([]byte(entries[0].Message), &message); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if message["action"] != "lock" || message["error"] != "account is already locked" {
+		t.Errorf("unexpected log message: %v", message)
+	}
+}
+
+func TestModel_GetInstanceActionHandler_MissingInstance(t *testing.T) {
+	model := newTestModel(t, &TestModel{}, &ActionsORMIntegrator{Missing: true})
+	called := false
+	_ = model.RegisterInstanceAction("lock", "Lock account", func(*Model, interface{}, interface{}) (string, error) {
+		called = true
+		return "", nil
+	})
+
+	code, _ := model.GetInstanceActionHandler()(map[string]string{"id": "3", "name": "lock"})
+	if code != http.StatusNotFound || called {
+		t.Errorf("expected status 404 without running the action, got %d", code)
+	}
+}
@@ -0,0 +1,311 @@
+package adminpanel
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"sync"
+	"time"
+)
+
+// LogEntryRecord is the model ORMLogStore persists log entries as. Identifiers are stored in their string form.
+// Lookups filter on UserID, ContentType, ObjectID, ActionFlag and ActionTime, so the table should be indexed on those
+// columns.
+type LogEntryRecord struct {
+	ID          string
+	ActionTime  time.Time
+	UserID      string
+	UserRepr    string
+	ContentType string
+	ObjectID    string
+	ObjectRepr  string
+	ActionFlag  string
+	Message     string
+	PrevHash    string
+	Hash        string
+}
+
+var logEntryRecordFields = []string{"ID", "ActionTime", "UserID", "UserRepr", "ContentType", "ObjectID", "ObjectRepr", "ActionFlag", "Message", "PrevHash", "Hash"}
+
+// NewLogEntryRecord converts a log entry to its persisted form.
+func NewLogEntryRecord(entry *logging.LogEntry) *LogEntryRecord {
+	return &LogEntryRecord{
+		ID:          logIdentifier(entry.ID),
+		ActionTime:  entry.ActionTime,
+		UserID:      logIdentifier(entry.UserID),
+		UserRepr:    entry.UserRepr,
+		ContentType: entry.ContentType,
+		ObjectID:    logIdentifier(entry.ObjectID),
+		ObjectRepr:  entry.ObjectRepr,
+		ActionFlag:  string(entry.ActionFlag),
+		Message:     entry.Message,
+		PrevHash:    entry.PrevHash,
+		Hash:        entry.Hash,
+	}
+}
+
+// LogEntry converts the record back to a log entry. Empty identifiers are returned as nil.
+func (r *LogEntryRecord) LogEntry() *logging.LogEntry {
+	return &logging.LogEntry{
+		ID:          logIdentifierValue(r.ID),
+		ActionTime:  r.ActionTime,
+		UserID:      logIdentifierValue(r.UserID),
+		UserRepr:    r.UserRepr,
+		ContentType: r.ContentType,
+		ObjectID:    logIdentifierValue(r.ObjectID),
+		ObjectRepr:  r.ObjectRepr,
+		ActionFlag:  logging.LogStoreLevel(r.ActionFlag),
+		Message:     r.Message,
+		PrevHash:    r.PrevHash,
+		Hash:        r.Hash,
+	}
+}
+
+func logIdentifier(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func logIdentifierValue(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// ORMLogStore is a log store persisting entries as LogEntryRecord instances through an ORM integrator. Lookups are
+// filtered and ordered in the database when the integrator implements PaginatedORMIntegrator.
+type ORMLogStore struct {
+	ORM       ORMIntegrator
+	Retention logging.RetentionPolicy
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// NewORMLogStore creates a log store persisting entries through the ORM integrator and pruning them according to the
+// retention policy.
+func NewORMLogStore(orm ORMIntegrator, retention logging.RetentionPolicy) *ORMLogStore {
+	return &ORMLogStore{ORM: orm, Retention: retention, lastPrune: time.Now()}
+}
+
+// InsertLogEntry persists the entry, then prunes old entries when the retention interval has elapsed.
+func (s *ORMLogStore) InsertLogEntry(entry *logging.LogEntry) error {
+	return s.InsertLogEntryContext(context.Background(), entry)
+}
+
+// InsertLogEntryContext is InsertLogEntry running its queries with ctx when the ORM integrator implements
+// ContextORMIntegrator.
+func (s *ORMLogStore) InsertLogEntryContext(ctx context.Context, entry *logging.LogEntry) error {
+	orm := bindORMContext(s.ORM, ctx)
+	existing, err := orm.FetchInstance(&LogEntryRecord{}, logIdentifier(entry.ID))
+	if err != nil {
+		return err
+	}
+	if !isNilInstance(existing) {
+		return fmt.Errorf("log entry with ID %v already exists", entry.ID)
+	}
+	if err = orm.CreateInstanceOnlyFields(NewLogEntryRecord(entry), logEntryRecordFields); err != nil {
+		return err
+	}
+	if !s.pruneDue(time.Now()) {
+		return nil
+	}
+	_, err = s.prune(orm, time.Now())
+	return err
+}
+
+func (s *ORMLogStore) pruneDue(now time.Time) bool {
+	if s.Retention.Interval <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastPrune) < s.Retention.Interval {
+		return false
+	}
+	s.lastPrune = now
+	return true
+}
+
+// GetLogEntry returns the entry with the given ID, or nil when it does not exist.
+func (s *ORMLogStore) GetLogEntry(id interface{}) (*logging.LogEntry, error) {
+	return s.GetLogEntryContext(context.Background(), id)
+}
+
+// GetLogEntryContext is GetLogEntry running its query with ctx.
+func (s *ORMLogStore) GetLogEntryContext(ctx context.Context, id interface{}) (*logging.LogEntry, error) {
+	instance, err := bindORMContext(s.ORM, ctx).FetchInstance(&LogEntryRecord{}, logIdentifier(id))
+	if err != nil || isNilInstance(instance) {
+		return nil, err
+	}
+	record, ok := instance.(*LogEntryRecord)
+	if !ok {
+		return nil, fmt.Errorf("unexpected log entry record type %T", instance)
+	}
+	return record.LogEntry(), nil
+}
+
+// GetLogEntries returns every entry, newest first.
+func (s *ORMLogStore) GetLogEntries() ([]*logging.LogEntry, error) {
+	return s.GetLogEntriesContext(context.Background())
+}
+
+// GetLogEntriesContext is GetLogEntries running its queries with ctx.
+func (s *ORMLogStore) GetLogEntriesContext(ctx context.Context) ([]*logging.LogEntry, error) {
+	page, err := s.QueryLogEntriesContext(ctx, logging.LogQuery{})
+	if err != nil {
+		return nil, err
+	}
+	return page.Entries, nil
+}
+
+// QueryLogEntries returns the page of entries the query selects. Each accepted action flag and each side of the
+// cursor is fetched with its own query, so the integrator only needs to support equality and range filters.
+func (s *ORMLogStore) QueryLogEntries(query logging.LogQuery) (*logging.LogPage, error) {
+	return s.QueryLogEntriesContext(context.Background(), query)
+}
+
+// QueryLogEntriesContext is QueryLogEntries running its queries with ctx.
+func (s *ORMLogStore) QueryLogEntriesContext(ctx context.Context, query logging.LogQuery) (*logging.LogPage, error) {
+	orm := bindORMContext(s.ORM, ctx)
+	var cursor *logging.LogCursor
+	if query.Cursor != "" {
+		parsed, err := logging.ParseLogCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &parsed
+	}
+
+	base := InstancesQuery{
+		Fields:   logEntryRecordFields,
+		Filters:  logQueryFilters(query),
+		Ordering: []OrderingField{{Field: "ActionTime", Descending: true}, {Field: "ID", Descending: true}},
+	}
+	if query.Limit > 0 {
+		base.Limit = query.Limit + 1
+	}
+	if query.Search != "" {
+		base.Search = query.Search
+		base.SearchFields = []string{"UserRepr", "ContentType", "ObjectRepr", "Message"}
+	}
+
+	queries := make([]InstancesQuery, 0)
+	for _, flagFilters := range logActionFlagFilters(query.ActionFlags) {
+		flagQuery := base
+		flagQuery.Filters = append(append([]FilterExpression(nil), base.Filters...), flagFilters...)
+		if cursor == nil {
+			queries = append(queries, flagQuery)
+			continue
+		}
+		tied := flagQuery
+		tied.Filters = append(append([]FilterExpression(nil), flagQuery.Filters...),
+			FilterExpression{Field: "ActionTime", Operator: FilterEqual, Value: cursor.ActionTime},
+			FilterExpression{Field: "ID", Operator: FilterLessThan, Value: cursor.ID},
+		)
+		older := flagQuery
+		older.Filters = append(append([]FilterExpression(nil), flagQuery.Filters...),
+			FilterExpression{Field: "ActionTime", Operator: FilterLessThan, Value: cursor.ActionTime},
+		)
+		queries = append(queries, tied, older)
+	}
+
+	entries := make([]*logging.LogEntry, 0)
+	for _, instancesQuery := range queries {
+		instances, err := fetchInstancesPage(orm, &LogEntryRecord{}, instancesQuery)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range instances {
+			record, ok := instance.(*LogEntryRecord)
+			if !ok {
+				return nil, fmt.Errorf("unexpected log entry record type %T", instance)
+			}
+			entries = append(entries, record.LogEntry())
+		}
+	}
+	return logging.PageLogEntries(entries, query)
+}
+
+// logActionFlagFilters returns the filters selecting each of the action flags, or a single empty set accepting
+// every action.
+func logActionFlagFilters(flags []logging.LogStoreLevel) [][]FilterExpression {
+	if len(flags) == 0 {
+		return [][]FilterExpression{nil}
+	}
+	filters := make([][]FilterExpression, len(flags))
+	for i, flag := range flags {
+		filters[i] = []FilterExpression{{Field: "ActionFlag", Operator: FilterEqual, Value: string(flag)}}
+	}
+	return filters
+}
+
+func logQueryFilters(query logging.LogQuery) []FilterExpression {
+	filters := make([]FilterExpression, 0)
+	if query.UserID != nil {
+		filters = append(filters, FilterExpression{Field: "UserID", Operator: FilterEqual, Value: logIdentifier(query.UserID)})
+	}
+	if query.ContentType != "" {
+		filters = append(filters, FilterExpression{Field: "ContentType", Operator: FilterEqual, Value: query.ContentType})
+	}
+	if query.ObjectID != nil {
+		filters = append(filters, FilterExpression{Field: "ObjectID", Operator: FilterEqual, Value: logIdentifier(query.ObjectID)})
+	}
+	if !query.Since.IsZero() {
+		filters = append(filters, FilterExpression{Field: "ActionTime", Operator: FilterGreaterThanOrEqual, Value: query.Since})
+	}
+	if !query.Until.IsZero() {
+		filters = append(filters, FilterExpression{Field: "ActionTime", Operator: FilterLessThan, Value: query.Until})
+	}
+	return filters
+}
+
+// Prune deletes the entries the retention policy no longer keeps at the given time and returns how many were
+// deleted.
+func (s *ORMLogStore) Prune(now time.Time) (uint, error) {
+	return s.prune(s.ORM, now)
+}
+
+func (s *ORMLogStore) prune(orm ORMIntegrator, now time.Time) (uint, error) {
+	expired := make([]interface{}, 0)
+	if cutoff := s.Retention.Cutoff(now); !cutoff.IsZero() {
+		instances, err := fetchInstancesPage(orm, &LogEntryRecord{}, InstancesQuery{
+			Fields:  []string{"ID", "ActionTime"},
+			Filters: []FilterExpression{{Field: "ActionTime", Operator: FilterLessThan, Value: cutoff}},
+		})
+		if err != nil {
+			return 0, err
+		}
+		expired = append(expired, instances...)
+	}
+	if s.Retention.MaxEntries > 0 {
+		instances, err := fetchInstancesPage(orm, &LogEntryRecord{}, InstancesQuery{
+			Fields:   []string{"ID", "ActionTime"},
+			Ordering: []OrderingField{{Field: "ActionTime", Descending: true}},
+			Offset:   s.Retention.MaxEntries,
+		})
+		if err != nil {
+			return 0, err
+		}
+		expired = append(expired, instances...)
+	}
+
+	deleted := make(map[string]bool)
+	for _, instance := range expired {
+		record, ok := instance.(*LogEntryRecord)
+		if !ok {
+			return uint(len(deleted)), fmt.Errorf("unexpected log entry record type %T", instance)
+		}
+		if deleted[record.ID] {
+			continue
+		}
+		if err := orm.DeleteInstance(&LogEntryRecord{}, record.ID); err != nil {
+			return uint(len(deleted)), err
+		}
+		deleted[record.ID] = true
+	}
+	return uint(len(deleted)), nil
+}
@@ -0,0 +1,202 @@
+package adminpanel
+
+import (
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"testing"
+	"time"
+)
+
+type LogRecordORMIntegrator struct {
+	MockORMIntegrator
+	Records map[string]*LogEntryRecord
+}
+
+func (o *LogRecordORMIntegrator) FetchInstance(_ interface{}, id interface{}) (interface{}, error) {
+	record, ok := o.Records[id.(string)]
+	if !ok {
+		return nil, nil
+	}
+	return record, nil
+}
+
+func (o *LogRecordORMIntegrator) FetchInstancesOnlyFields(interface{}, []string) (interface{}, error) {
+	records := make([]*LogEntryRecord, 0, len(o.Records))
+	for _, record := range o.Records {
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (o *LogRecordORMIntegrator) FetchInstancesOnlyFieldWithSearch(model interface{}, fields []string, _ string, _ []string) (interface{}, error) {
+	return o.FetchInstancesOnlyFields(model, fields)
+}
+
+func (o *LogRecordORMIntegrator) CreateInstanceOnlyFields(instance interface{}, _ []string) error {
+	record := instance.(*LogEntryRecord)
+	o.Records[record.ID] = record
+	return nil
+}
+
+func (o *LogRecordORMIntegrator) DeleteInstance(_ interface{}, id interface{}) error {
+	delete(o.Records, id.(string))
+	return nil
+}
+
+func newTestLogStore(t *testing.T, retention logging.RetentionPolicy, now time.Time) (*ORMLogStore, *LogRecordORMIntegrator) {
+	orm := &LogRecordORMIntegrator{Records: make(map[string]*LogEntryRecord)}
+	store := NewORMLogStore(orm, retention)
+	entries := []*logging.LogEntry{
+		{ID: "1", ActionTime: now.Add(-72 * time.Hour), UserID: 7, ContentType: "Shop | Orders", ObjectID: uint(1), ActionFlag: logging.LogStoreLevelCreate},
+		{ID: "2", ActionTime: now.Add(-48 * time.Hour), UserID: 7, ContentType: "Shop | Orders", ObjectID: uint(1), ActionFlag: logging.LogStoreLevelUpdate},
+		{ID: "3", ActionTime: now.Add(-24 * time.Hour), UserID: 8, ContentType: "Shop | Orders", ObjectID: uint(2), ActionFlag: logging.LogStoreLevelCreate},
+		{ID: "4", ActionTime: now, UserID: 8, ContentType: "Shop | Items", ObjectID: uint(1), ActionFlag: logging.LogStoreLevelDelete, Message: "Removed lamp"},
+	}
+	for _, entry := range entries {
+		if err := store.InsertLogEntry(entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return store, orm
+}
+
+func logEntryIDs(entries []*logging.LogEntry) []interface{} {
+	ids := make([]interface{}, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+	return ids
+}
+
+func TestORMLogStore_Entries(t *testing.T) {
+	now := time.Now()
+	store, _ := newTestLogStore(t, logging.RetentionPolicy{}, now)
+
+	if err := store.InsertLogEntry(&logging.LogEntry{ID: "1"}); err == nil {
+		t.Error("expected an error for a duplicate ID")
+	}
+
+	entry, err := store.GetLogEntry("2")
+	if err != nil || entry == nil {
+		t.Fatalf("expected entry 2, got %v, %v", entry, err)
+	}
+	if entry.UserID != "7" || entry.ObjectID != "1" || entry.ActionFlag != logging.LogStoreLevelUpdate {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if entry, err = store.GetLogEntry("missing"); err != nil || entry != nil {
+		t.Errorf("expected no entry, got %v, %v", entry, err)
+	}
+
+	entries, err := store.GetLogEntries()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := logEntryIDs(entries); len(ids) != 4 || ids[0] != "4" || ids[3] != "1" {
+		t.Errorf("expected every entry newest first, got %v", ids)
+	}
+}
+
+func TestORMLogStore_QueryLogEntries(t *testing.T) {
+	now := time.Now()
+	store, _ := newTestLogStore(t, logging.RetentionPolicy{}, now)
+
+	tests := []struct {
+		name     string
+		query    logging.LogQuery
+		expected []interface{}
+	}{
+		{"User", logging.LogQuery{UserID: 7}, []interface{}{"2", "1"}},
+		{"Object", logging.LogQuery{ContentType: "Shop | Orders", ObjectID: uint(1)}, []interface{}{"2", "1"}},
+		{"Action", logging.LogQuery{ActionFlags: []logging.LogStoreLevel{logging.LogStoreLevelCreate}}, []interface{}{"3", "1"}},
+		{"Actions", logging.LogQuery{ActionFlags: []logging.LogStoreLevel{logging.LogStoreLevelDelete, logging.LogStoreLevelUpdate}}, []interface{}{"4", "2"}},
+		{"Search", logging.LogQuery{Search: "lamp"}, []interface{}{"4"}},
+		{"TimeRange", logging.LogQuery{Since: now.Add(-48 * time.Hour), Until: now}, []interface{}{"3", "2"}},
+		{"Limit", logging.LogQuery{Limit: 1}, []interface{}{"4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := store.QueryLogEntries(tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ids := logEntryIDs(page.Entries)
+			if len(ids) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.expected[i] {
+					t.Errorf("expected %v, got %v", tt.expected, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestORMLogStore_QueryLogEntries_Cursor(t *testing.T) {
+	now := time.Now()
+	store, _ := newTestLogStore(t, logging.RetentionPolicy{}, now)
+	if err := store.InsertLogEntry(&logging.LogEntry{ID: "0", ActionTime: now.Add(-24 * time.Hour)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ids := make([]interface{}, 0)
+	query := logging.LogQuery{Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("expected the pages to end")
+		}
+		page, err := store.QueryLogEntries(query)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, logEntryIDs(page.Entries)...)
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	expected := []interface{}{"4", "3", "0", "2", "1"}
+	if len(ids) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, ids)
+	}
+	for i := range ids {
+		if ids[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, ids)
+		}
+	}
+
+	if _, err := store.QueryLogEntries(logging.LogQuery{Cursor: "not a cursor"}); err == nil {
+		t.Error("expected an error for an invalid cursor")
+	}
+}
+
+func TestORMLogStore_Prune(t *testing.T) {
+	now := time.Now()
+	store, orm := newTestLogStore(t, logging.RetentionPolicy{MaxAge: 60 * time.Hour, MaxEntries: 2}, now)
+
+	deleted, err := store.Prune(now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("expected 2 entries to be pruned, got %d", deleted)
+	}
+	if _, ok := orm.Records["3"]; !ok || len(orm.Records) != 2 {
+		t.Errorf("expected entries 3 and 4 to be kept, got %v", orm.Records)
+	}
+}
+
+func TestORMLogStore_AutomaticPrune(t *testing.T) {
+	store, orm := newTestLogStore(t, logging.RetentionPolicy{MaxEntries: 1, Interval: time.Hour}, time.Now())
+	if len(orm.Records) != 4 {
+		t.Fatalf("expected no prune before the interval, got %d records", len(orm.Records))
+	}
+
+	store.lastPrune = time.Now().Add(-2 * time.Hour)
+	if err := store.InsertLogEntry(&logging.LogEntry{ID: "5", ActionTime: time.Now()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := orm.Records["5"]; !ok || len(orm.Records) != 1 {
+		t.Errorf("expected only the newest entry to be kept, got %v", orm.Records)
+	}
+}
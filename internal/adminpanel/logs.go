@@ -2,9 +2,16 @@ package adminpanel
 
 import (
 	"fmt"
+	"github.com/go-advanced-admin/admin/internal/logging"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 )
 
+// LogDateLayout is the layout of the dates accepted by the log browser's since and until filters.
+const LogDateLayout = "2006-01-02"
+
 // GetLogBaseLink returns the base URL path for logs.
 func (ap *AdminPanel) GetLogBaseLink() string {
 	return "/i/log"
@@ -15,6 +22,22 @@ func (ap *AdminPanel) GetFullLogBaseLink() string {
 	return ap.Config.GetLink(ap.GetLogBaseLink())
 }
 
+// GetLogVerifyLink returns the URL path of the log chain verification page.
+func (ap *AdminPanel) GetLogVerifyLink() string {
+	return ap.GetLogBaseLink() + "/verify"
+}
+
+// GetFullLogVerifyLink returns the full URL path of the log chain verification page, including the admin prefix.
+func (ap *AdminPanel) GetFullLogVerifyLink() string {
+	return ap.Config.GetLink(ap.GetLogVerifyLink())
+}
+
+// IsLogStoreVerifiable reports whether the log store can verify that its entries were not altered.
+func (ap *AdminPanel) IsLogStoreVerifiable() bool {
+	_, ok := ap.Config.LogStore.(logging.VerifiableLogStore)
+	return ok
+}
+
 // GetLogHandler returns the HTTP handler function for viewing a log entry.
 func (ap *AdminPanel) GetLogHandler() HandlerFunc {
 	return func(data interface{}) (uint, string) {
@@ -23,7 +46,7 @@ func (ap *AdminPanel) GetLogHandler() HandlerFunc {
 			return GetErrorHTML(http.StatusBadRequest, fmt.Errorf("instance id is required"))
 		}
 
-		entry, err := ap.Config.LogStore.GetLogEntry(instanceIDStr)
+		entry, err := logging.AdaptLogStore(ap.Config.LogStore).GetLogEntryContext(ap.GetContext(data), instanceIDStr)
 		if err != nil {
 			return GetErrorHTML(http.StatusInternalServerError, err)
 		}
@@ -44,10 +67,16 @@ func (ap *AdminPanel) GetLogHandler() HandlerFunc {
 			return GetErrorHTML(http.StatusInternalServerError, err)
 		}
 
+		var changes map[string]logging.FieldDiff
+		if entry.ActionFlag == logging.LogStoreLevelUpdate {
+			changes, _ = logging.ParseFieldDiffs(entry.Message)
+		}
+
 		html, err := ap.Config.Renderer.RenderTemplate("log", map[string]interface{}{
 			"apps":        apps,
 			"navBarItems": ap.Config.GetNavBarItems(data),
 			"log":         entry,
+			"changes":     changes,
 		})
 		if err != nil {
 			return GetErrorHTML(http.StatusInternalServerError, err)
@@ -59,3 +88,192 @@ func (ap *AdminPanel) GetLogHandler() HandlerFunc {
 		return http.StatusOK, html
 	}
 }
+
+// QueryLogEntries returns up to query.Limit entries matching the query that the user may view. Further pages are read
+// from the log store when entries are filtered out, so the page is only short when no more entries are visible. The
+// returned cursor continues after the last entry of the page.
+func (ap *AdminPanel) QueryLogEntries(ctx interface{}, query logging.LogQuery) (*logging.LogPage, error) {
+	result := &logging.LogPage{Entries: make([]*logging.LogEntry, 0)}
+	if ap.Config.LogStore == nil {
+		return result, nil
+	}
+	store, requestCtx := logging.AdaptLogStore(ap.Config.LogStore), ap.GetContext(ctx)
+	for {
+		page, err := store.QueryLogEntriesContext(requestCtx, query)
+		if err != nil {
+			return nil, err
+		}
+		requests := make([]PermissionRequest, len(page.Entries))
+		for i, entry := range page.Entries {
+			action := LogViewAction
+			requests[i] = PermissionRequest{Action: &action, InstanceID: entry.ID}
+		}
+		if err = ap.PreloadPermissions(requests, ctx); err != nil {
+			return nil, err
+		}
+
+		for i, entry := range page.Entries {
+			allowed, err := ap.PermissionChecker.HasLogViewPermission(ctx, entry.ID)
+			if err != nil {
+				return nil, err
+			}
+			if !allowed {
+				continue
+			}
+			result.Entries = append(result.Entries, entry)
+			if query.Limit > 0 && uint(len(result.Entries)) >= query.Limit {
+				if i < len(page.Entries)-1 || page.NextCursor != "" {
+					result.NextCursor = logging.NewLogCursor(entry).String()
+				}
+				return result, nil
+			}
+		}
+		if page.NextCursor == "" {
+			return result, nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+// GetLogQuery builds the log query described by the log browser's query parameters: user, action (comma separated),
+// contentType, objectID, since, until, search and cursor.
+func (ap *AdminPanel) GetLogQuery(data interface{}) (logging.LogQuery, error) {
+	query := logging.LogQuery{
+		ContentType: ap.Web.GetQueryParam(data, "contentType"),
+		Search:      ap.Web.GetQueryParam(data, "search"),
+		Cursor:      ap.Web.GetQueryParam(data, "cursor"),
+		Limit:       ap.Config.DefaultInstancesPerPage,
+	}
+	if userID := ap.Web.GetQueryParam(data, "user"); userID != "" {
+		query.UserID = userID
+	}
+	if objectID := ap.Web.GetQueryParam(data, "objectID"); objectID != "" {
+		query.ObjectID = objectID
+	}
+	if actions := ap.Web.GetQueryParam(data, "action"); actions != "" {
+		for _, action := range strings.Split(actions, ",") {
+			query.ActionFlags = append(query.ActionFlags, logging.LogStoreLevel(strings.TrimSpace(action)))
+		}
+	}
+	if since := ap.Web.GetQueryParam(data, "since"); since != "" {
+		parsed, err := time.Parse(LogDateLayout, since)
+		if err != nil {
+			return query, fmt.Errorf("invalid since date: %v", err)
+		}
+		query.Since = parsed
+	}
+	if until := ap.Web.GetQueryParam(data, "until"); until != "" {
+		parsed, err := time.Parse(LogDateLayout, until)
+		if err != nil {
+			return query, fmt.Errorf("invalid until date: %v", err)
+		}
+		query.Until = parsed.AddDate(0, 0, 1)
+	}
+	if query.Cursor != "" {
+		if _, err := logging.ParseLogCursor(query.Cursor); err != nil {
+			return query, err
+		}
+	}
+	return query, nil
+}
+
+// GetLogsHandler returns the HTTP handler function for browsing the log entries with filters and paging.
+func (ap *AdminPanel) GetLogsHandler() HandlerFunc {
+	return func(data interface{}) (uint, string) {
+		allowed, err := ap.PermissionChecker.HasReadPermission(data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		if !allowed {
+			return GetErrorHTML(http.StatusForbidden, fmt.Errorf("forbidden"))
+		}
+
+		query, err := ap.GetLogQuery(data)
+		if err != nil {
+			return GetErrorHTML(http.StatusBadRequest, err)
+		}
+		page, err := ap.QueryLogEntries(data, query)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+
+		filterParams := url.Values{}
+		for _, name := range []string{"user", "action", "contentType", "objectID", "since", "until", "search"} {
+			if value := ap.Web.GetQueryParam(data, name); value != "" {
+				filterParams.Set(name, value)
+			}
+		}
+		firstPageLink := ap.GetFullLogBaseLink()
+		if len(filterParams) > 0 {
+			firstPageLink += "?" + filterParams.Encode()
+		}
+		var nextPageLink string
+		if page.NextCursor != "" {
+			filterParams.Set("cursor", page.NextCursor)
+			nextPageLink = ap.GetFullLogBaseLink() + "?" + filterParams.Encode()
+		}
+
+		apps, err := GetAppsWithReadPermissions(ap, data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+
+		html, err := ap.Config.Renderer.RenderTemplate("logs", map[string]interface{}{
+			"admin":         ap,
+			"apps":          apps,
+			"navBarItems":   ap.Config.GetNavBarItems(data),
+			"logs":          page.Entries,
+			"filters":       filterParams,
+			"isFirstPage":   query.Cursor == "",
+			"firstPageLink": firstPageLink,
+			"nextPageLink":  nextPageLink,
+		})
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		err = ap.CreateLogListViewLog(data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		return http.StatusOK, html
+	}
+}
+
+// GetLogVerifyHandler returns the HTTP handler function verifying the chain of a tamper-evident log store and reporting
+// its first broken link.
+func (ap *AdminPanel) GetLogVerifyHandler() HandlerFunc {
+	return func(data interface{}) (uint, string) {
+		allowed, err := ap.PermissionChecker.HasLogViewPermission(data, nil)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		if !allowed {
+			return GetErrorHTML(http.StatusForbidden, fmt.Errorf("you are not allowed to view the log"))
+		}
+
+		verifier, ok := ap.Config.LogStore.(logging.VerifiableLogStore)
+		if !ok {
+			return GetErrorHTML(http.StatusNotFound, fmt.Errorf("the log store does not support verification"))
+		}
+		report, err := verifier.Verify()
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+
+		apps, err := GetAppsWithReadPermissions(ap, data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+
+		html, err := ap.Config.Renderer.RenderTemplate("log_verify", map[string]interface{}{
+			"admin":       ap,
+			"apps":        apps,
+			"navBarItems": ap.Config.GetNavBarItems(data),
+			"report":      report,
+		})
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		return http.StatusOK, html
+	}
+}
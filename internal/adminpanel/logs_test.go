@@ -0,0 +1,221 @@
+package adminpanel
+
+import (
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// evenLogPermissionFunc allows viewing only the log entries with an even ID.
+func evenLogPermissionFunc(request PermissionRequest, _ interface{}) (bool, error) {
+	if request.Action == nil || *request.Action != LogViewAction || request.InstanceID == nil {
+		return true, nil
+	}
+	id, err := strconv.Atoi(fmt.Sprint(request.InstanceID))
+	return err == nil && id%2 == 0, nil
+}
+
+// newLogsTestPanel creates an admin panel whose log store holds count entries.
+func newLogsTestPanel(t *testing.T, count int) (*HTTPWebIntegrator, *AdminPanel) {
+	web, model, _ := newHTTPTestPanel(t, evenLogPermissionFunc, func(config *AdminConfig) {
+		config.LogStore = logging.NewInMemoryLogStore(1000)
+		config.LogStoreLevel = logging.LogStoreLevelCreate
+		config.DefaultInstancesPerPage = 5
+	})
+	panel := model.App.Panel
+
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < count; i++ {
+		action := logging.LogStoreLevelCreate
+		if i%4 == 0 {
+			action = logging.LogStoreLevelDelete
+		}
+		entry := &logging.LogEntry{
+			ID:          fmt.Sprintf("%03d", i),
+			ActionTime:  start.Add(time.Duration(i) * time.Hour),
+			UserID:      i % 3,
+			ContentType: "Shop | Orders",
+			ObjectID:    i,
+			ObjectRepr:  fmt.Sprintf("Order %d", i),
+			ActionFlag:  action,
+		}
+		if err := panel.Config.LogStore.InsertLogEntry(entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return web, panel
+}
+
+func TestAdminPanel_GetLogEntries_FiltersBeforeTruncating(t *testing.T) {
+	_, panel := newLogsTestPanel(t, 30)
+
+	entries := panel.GetLogEntries(nil, 10)
+	if len(entries) != 10 {
+		t.Fatalf("expected 10 visible entries, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if id, _ := strconv.Atoi(fmt.Sprint(entry.ID)); id%2 != 0 {
+			t.Errorf("entry %v should not be visible", entry.ID)
+		}
+	}
+	if entries[0].ID != "028" || entries[9].ID != "010" {
+		t.Errorf("expected the newest visible entries, got %v to %v", entries[0].ID, entries[9].ID)
+	}
+}
+
+func TestAdminPanel_QueryLogEntries_Paging(t *testing.T) {
+	_, panel := newLogsTestPanel(t, 12)
+
+	ids := make([]string, 0)
+	query := logging.LogQuery{Limit: 4}
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("expected the pages to end")
+		}
+		page, err := panel.QueryLogEntries(nil, query)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, entry := range page.Entries {
+			ids = append(ids, fmt.Sprint(entry.ID))
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	if strings.Join(ids, ",") != "010,008,006,004,002,000" {
+		t.Errorf("unexpected entries %v", ids)
+	}
+}
+
+// plainLogStore implements only LogStore, leaving queries to the in-memory fallback.
+type plainLogStore struct {
+	store *logging.InMemoryLogStore
+}
+
+func (s plainLogStore) InsertLogEntry(entry *logging.LogEntry) error {
+	return s.store.InsertLogEntry(entry)
+}
+
+func (s plainLogStore) GetLogEntry(id interface{}) (*logging.LogEntry, error) {
+	return s.store.GetLogEntry(id)
+}
+
+func (s plainLogStore) GetLogEntries() ([]*logging.LogEntry, error) {
+	return s.store.GetLogEntries()
+}
+
+func TestAdminPanel_QueryLogEntries_Fallback(t *testing.T) {
+	_, panel := newLogsTestPanel(t, 12)
+	panel.Config.LogStore = plainLogStore{store: panel.Config.LogStore.(*logging.InMemoryLogStore)}
+
+	page, err := panel.QueryLogEntries(nil, logging.LogQuery{ActionFlags: []logging.LogStoreLevel{logging.LogStoreLevelDelete}, Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Entries) != 2 || page.Entries[0].ID != "008" || page.Entries[1].ID != "004" || page.NextCursor == "" {
+		t.Errorf("expected the newest visible deletions, got %v", page.Entries)
+	}
+}
+
+func TestAdminPanel_QueryLogEntries_PermissionError(t *testing.T) {
+	_, panel := newLogsTestPanel(t, 4)
+	panel.permissionFunc = func(PermissionRequest, interface{}) (bool, error) {
+		return false, fmt.Errorf("permission backend unavailable")
+	}
+
+	if _, err := panel.QueryLogEntries(nil, logging.LogQuery{}); err == nil {
+		t.Error("expected the permission error to be returned")
+	}
+}
+
+func TestAdminPanel_GetLogsHandler(t *testing.T) {
+	web, panel := newLogsTestPanel(t, 20)
+	link := panel.GetFullLogBaseLink()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		web.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get(link + "?action=delete&since=2024-05-01&until=2024-05-01")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	for _, expected := range []string{"Order 0<", "Order 4<", "Order 8<", "Order 12<", "Order 16<"} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("expected %q in the page, got %s", expected, body)
+		}
+	}
+	if strings.Contains(body, "Order 2<") || strings.Contains(body, "Next page") {
+		t.Errorf("expected only the visible delete entries on a single page, got %s", body)
+	}
+
+	rec = get(link + "?search=order")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	next := regexp.MustCompile(`href="([^"]+)">Next page`).FindStringSubmatch(rec.Body.String())
+	if next == nil {
+		t.Fatalf("expected a next page link, got %s", rec.Body.String())
+	}
+	rec = get(strings.ReplaceAll(next[1], "&amp;", "&"))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Order 8<") || !strings.Contains(rec.Body.String(), "First page") {
+		t.Errorf("expected the second page, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	for _, query := range []string{"?since=yesterday", "?cursor=invalid!"} {
+		if rec = get(link + query); rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400 for %s, got %d", query, rec.Code)
+		}
+	}
+}
+
+func TestAdminPanel_GetLogVerifyHandler(t *testing.T) {
+	web, panel := newLogsTestPanel(t, 0)
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		web.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, panel.GetFullLogVerifyLink(), nil))
+		return rec
+	}
+
+	if rec := get(); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for a store without verification, got %d", rec.Code)
+	}
+
+	orm := &LogRecordORMIntegrator{Records: make(map[string]*LogEntryRecord)}
+	store, err := logging.NewChainedLogStore(NewORMLogStore(orm, logging.RetentionPolicy{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	panel.Config.LogStore = store
+	for i := 0; i < 3; i++ {
+		if err = panel.Config.CreateLog(nil, logging.LogStoreLevelDelete, "Shop | Orders", uint(i), "", ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	rec := get()
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "intact: 3 entries verified") {
+		t.Fatalf("expected an intact chain, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	for _, record := range orm.Records {
+		if record.ObjectID == "1" {
+			record.ObjectID = "7"
+		}
+	}
+	rec = get()
+	if !strings.Contains(rec.Body.String(), "broken after 1 verified entries") || !strings.Contains(rec.Body.String(), "does not match its hash") {
+		t.Errorf("expected the altered entry to be reported, got %s", rec.Body.String())
+	}
+}
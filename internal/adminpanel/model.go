@@ -1,36 +1,67 @@
 package adminpanel
 
 import (
+	"context"
 	"fmt"
 	"github.com/go-advanced-admin/admin/internal/logging"
+	"github.com/go-advanced-admin/admin/internal/utils"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strconv"
 )
 
 // Model represents a registered model within an app in the admin panel.
 type Model struct {
-	Name        string
-	DisplayName string
-	PTR         interface{}
-	App         *App
-	Fields      []FieldConfig
-	ORM         ORMIntegrator
+	Name            string
+	DisplayName     string
+	PTR             interface{}
+	App             *App
+	Fields          []FieldConfig
+	ORM             ORMIntegrator
+	DefaultOrdering []OrderingField
+	ListFilters     []ListFilter
+	Actions         []*ModelAction
+	InstanceActions []*InstanceAction
+
+	ctx context.Context
 }
 
 // CreateViewLog creates a log entry when the model's list view is accessed.
 func (m *Model) CreateViewLog(ctx interface{}) error {
-	return m.App.Panel.Config.CreateLog(ctx, logging.LogStoreLevelListView, fmt.Sprintf("%s | %s", m.App.Name, m.DisplayName), nil, "", "")
+	return m.App.Panel.Config.CreateLog(ctx, logging.LogStoreLevelListView, m.GetLogContentType(), nil, "", "")
 }
 
-// GetORM returns the ORM integrator for the model.
+// GetLogContentType returns the content type recorded in the log entries about the model and its instances.
+func (m *Model) GetLogContentType() string {
+	return fmt.Sprintf("%s | %s", m.App.Name, m.DisplayName)
+}
+
+// GetORM returns the ORM integrator for the model, bound to the context of the model when the integrator implements
+// ContextORMIntegrator.
 func (m *Model) GetORM() ORMIntegrator {
+	return bindORMContext(m.getORM(), m.ctx)
+}
+
+func (m *Model) getORM() ORMIntegrator {
 	if m.ORM != nil {
 		return m.ORM
 	}
 	return m.App.GetORM()
 }
 
+// WithContext returns a copy of the model running its ORM queries with ctx.
+func (m *Model) WithContext(ctx context.Context) *Model {
+	bound := *m
+	bound.ctx = ctx
+	return &bound
+}
+
+// withRequestContext returns a copy of the model running its ORM queries with the context of the request.
+func (m *Model) withRequestContext(data interface{}) *Model {
+	return m.WithContext(m.App.Panel.GetContext(data))
+}
+
 type AdminModelNameInterface interface {
 	AdminName() string
 }
@@ -66,17 +97,19 @@ func (m *Model) GetFullAddLink() string {
 // GetViewHandler returns the HTTP handler function for the model's list view.
 func (m *Model) GetViewHandler() HandlerFunc {
 	return func(data interface{}) (uint, string) {
+		m := m.withRequestContext(data)
+
 		var page, perPage uint
 		pageQuery := m.App.Panel.Web.GetQueryParam(data, "page")
 		perPageQuery := m.App.Panel.Web.GetQueryParam(data, "perPage")
 
-		if p, err := strconv.Atoi(pageQuery); err == nil {
+		if p, err := strconv.Atoi(pageQuery); err == nil && p > 0 {
 			page = uint(p)
 		} else {
 			page = 1
 		}
 
-		if pp, err := strconv.Atoi(perPageQuery); err == nil {
+		if pp, err := strconv.Atoi(perPageQuery); err == nil && pp > 0 {
 			perPage = uint(pp)
 		} else {
 			perPage = m.App.Panel.Config.DefaultInstancesPerPage
@@ -99,50 +132,46 @@ func (m *Model) GetViewHandler() HandlerFunc {
 			return GetErrorHTML(http.StatusInternalServerError, err)
 		}
 
-		var fieldsToFetch []string
-		for _, fieldConfig := range m.Fields {
-			if fieldConfig.IncludeInListFetch {
-				fieldsToFetch = append(fieldsToFetch, fieldConfig.Name)
-			}
+		list, err := m.fetchListPage(data, page, perPage)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
 		}
+		query, filterValues, pagedInstances := list.Query, list.FilterValues, list.Instances
+		totalCount := list.TotalCount
+		totalPages := (totalCount + perPage - 1) / perPage
 
-		searchQuery := m.App.Panel.Web.GetQueryParam(data, "search")
-		var instances interface{}
-		if searchQuery == "" {
-			instances, err = m.GetORM().FetchInstancesOnlyFields(m.PTR, fieldsToFetch)
-		} else {
-			var fieldsToSearch []string
-			for _, fieldConfig := range m.Fields {
-				if fieldConfig.IncludeInSearch {
-					fieldsToSearch = append(fieldsToSearch, fieldConfig.Name)
-				}
+		listParams := url.Values{}
+		if query.Search != "" {
+			listParams.Set("search", query.Search)
+		}
+		if perPageQuery != "" {
+			listParams.Set("perPage", perPageQuery)
+		}
+		for _, filter := range m.ListFilters {
+			if value, ok := filterValues[filter.Field]; ok {
+				listParams.Set(filter.GetParam(), value)
 			}
-			instances, err = m.GetORM().FetchInstancesOnlyFieldWithSearch(m.PTR, fieldsToFetch, searchQuery, fieldsToSearch)
 		}
-		if err != nil {
-			return GetErrorHTML(http.StatusInternalServerError, err)
+		filterParams := url.Values{}
+		for key, values := range listParams {
+			filterParams[key] = values
 		}
-
-		filteredInstances, err := filterInstancesByPermission(instances, m, data)
-		if err != nil {
-			return GetErrorHTML(http.StatusInternalServerError, err)
+		if orderingQuery := m.App.Panel.Web.GetQueryParam(data, "ordering"); orderingQuery != "" {
+			filterParams.Set("ordering", orderingQuery)
 		}
 
-		totalCount := uint(len(filteredInstances))
-		totalPages := (totalCount + perPage - 1) / perPage
-
-		startIndex := (page - 1) * perPage
-		endIndex := startIndex + perPage
-
-		if startIndex > totalCount {
-			startIndex = totalCount
+		requests := make([]PermissionRequest, 0, len(pagedInstances)*2)
+		for _, instance := range pagedInstances {
+			id, err := m.GetPrimaryKeyValue(instance)
+			if err != nil {
+				return GetErrorHTML(http.StatusInternalServerError, err)
+			}
+			requests = append(requests, newModelPermissionRequests(m.App.Name, m.Name, id, UpdateAction, DeleteAction)...)
 		}
-		if endIndex > totalCount {
-			endIndex = totalCount
+		if err = m.App.Panel.PreloadPermissions(requests, data); err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
 		}
 
-		pagedInstances := filteredInstances[startIndex:endIndex]
-
 		cleanInstances := make([]Instance, len(pagedInstances))
 		for i, instance := range pagedInstances {
 			id, err := m.GetPrimaryKeyValue(instance)
@@ -166,6 +195,23 @@ func (m *Model) GetViewHandler() HandlerFunc {
 			cleanInstances[i] = cleanInstance
 		}
 
+		actions, err := m.GetActionsWithPermission(data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+
+		readableFields, err := m.FilterReadableFields(m.Fields, nil, data)
+		if err != nil {
+			return GetErrorHTML(http.StatusInternalServerError, err)
+		}
+		readableFieldNames := getFieldNames(readableFields)
+		columns := make([]ListColumn, 0)
+		for _, column := range m.GetListColumns(query.Ordering, listParams) {
+			if utils.ContainsString(readableFieldNames, column.Field.Name) {
+				columns = append(columns, column)
+			}
+		}
+
 		html, err := m.App.Panel.Config.Renderer.RenderTemplate("model", map[string]interface{}{
 			"apps":        apps,
 			"model":       m,
@@ -174,6 +220,11 @@ func (m *Model) GetViewHandler() HandlerFunc {
 			"totalPages":  totalPages,
 			"currentPage": page,
 			"perPage":     perPage,
+			"ordering":    query.Ordering,
+			"columns":     columns,
+			"filters":     m.GetListFilterStates(filterValues, filterParams),
+			"actions":     actions,
+			"exportLinks": m.GetExportLinks(filterParams),
 			"navBarItems": m.App.Panel.Config.GetNavBarItems(data),
 		})
 		if err != nil {
@@ -197,30 +248,115 @@ func (m *Model) GetPrimaryKeyType() (reflect.Type, error) {
 	return m.GetORM().GetPrimaryKeyType(m.PTR)
 }
 
-func filterInstancesByPermission(instances interface{}, model *Model, data interface{}) ([]interface{}, error) {
-	val := reflect.ValueOf(instances)
+// GetPrimaryKeyField returns the name of the struct field holding the model's primary key. Unless the ORM integrator
+// implements PrimaryKeyFieldORMIntegrator, the field is found by giving every field of the primary key type a distinct
+// value and matching them against GetPrimaryKeyValue, falling back to "ID".
+func (m *Model) GetPrimaryKeyField() (string, error) {
+	orm := m.GetORM()
+	if named, ok := orm.(PrimaryKeyFieldORMIntegrator); ok {
+		return named.GetPrimaryKeyField(m.PTR)
+	}
+
+	modelType := reflect.TypeOf(m.PTR)
+	if modelType == nil || modelType.Kind() != reflect.Ptr || modelType.Elem().Kind() != reflect.Struct {
+		return "", fmt.Errorf("model must be a pointer to a struct")
+	}
+	primaryKeyType, err := orm.GetPrimaryKeyType(m.PTR)
+	if err != nil {
+		return "", err
+	}
+	if primaryKeyType != nil {
+		probe := reflect.New(modelType.Elem())
+		values := make(map[string]string)
+		for _, field := range reflect.VisibleFields(modelType.Elem()) {
+			if !field.IsExported() || field.Anonymous || field.Type != primaryKeyType {
+				continue
+			}
+			value := probe.Elem().FieldByIndex(field.Index)
+			key := strconv.Itoa(len(values) + 1)
+			if err := utils.SetStringsAsType(value, key); err != nil {
+				key = fmt.Sprintf("00000000-0000-0000-0000-%012d", len(values)+1)
+				if err := utils.SetStringsAsType(value, key); err != nil {
+					continue
+				}
+			}
+			values[fmt.Sprint(value.Interface())] = field.Name
+		}
+		if id, err := orm.GetPrimaryKeyValue(probe.Interface()); err == nil && id != nil {
+			if name, ok := values[fmt.Sprint(id)]; ok {
+				return name, nil
+			}
+		}
+	}
+	if _, ok := modelType.Elem().FieldByName("ID"); ok {
+		return "ID", nil
+	}
+	return "", fmt.Errorf("could not determine the primary key field of model '%s'", m.Name)
+}
+
+// listPage is a page of the model list as shown to the user.
+type listPage struct {
+	Query        InstancesQuery
+	FilterValues map[string]string
+	Instances    []interface{}
+	TotalCount   uint
+}
+
+// fetchListPage builds the scoped list query described by the request and retrieves the given page of the instances
+// matching it that the user may read. The total count only includes readable instances.
+func (m *Model) fetchListPage(data interface{}, page, perPage uint) (listPage, error) {
+	var fieldsToFetch []string
+	for _, fieldConfig := range m.Fields {
+		if fieldConfig.IncludeInListFetch {
+			fieldsToFetch = append(fieldsToFetch, fieldConfig.Name)
+		}
+	}
 
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
+	query, filterValues, err := m.GetScopedListQuery(data, fieldsToFetch)
+	if err != nil {
+		return listPage{}, err
 	}
+	query.Offset = (page - 1) * perPage
+	query.Limit = perPage
 
-	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
-		return nil, fmt.Errorf("instances must be a slice or array")
+	instances, totalCount, err := m.FetchReadableInstancesPage(query, data)
+	if err != nil {
+		return listPage{}, err
 	}
+	return listPage{Query: query, FilterValues: filterValues, Instances: instances, TotalCount: totalCount}, nil
+}
 
-	filtered := make([]interface{}, 0, val.Len())
+// filterInstancesByPermission drops the instances the user may not read. It only checks the given instances.
+func filterInstancesByPermission(instances []interface{}, model *Model, data interface{}) ([]interface{}, error) {
+	filtered := make([]interface{}, 0, len(instances))
 
-	for i := 0; i < val.Len(); i++ {
-		instance := val.Index(i).Interface()
+	ids := make([]interface{}, len(instances))
+	requests := make([]PermissionRequest, 0, len(instances))
+	for i, instance := range instances {
+		if instance == nil {
+			continue
+		}
 		id, err := model.GetPrimaryKeyValue(instance)
 		if err != nil {
 			return nil, err
 		}
+		ids[i] = id
+		requests = append(requests, newModelPermissionRequests(model.App.Name, model.Name, id, ReadAction)...)
+	}
+	if err := model.App.Panel.PreloadPermissions(requests, data); err != nil {
+		return nil, err
+	}
+
+	for i, instance := range instances {
+		if instance == nil {
+			continue
+		}
+		id := ids[i]
 		allowed, err := model.App.Panel.PermissionChecker.HasInstanceReadPermission(model.App.Name, model.Name, id, data)
 		if err != nil {
 			return nil, err
 		}
-		if allowed && instance != nil {
+		if allowed {
 			filtered = append(filtered, instance)
 		}
 	}
@@ -2,6 +2,7 @@ package adminpanel
 
 import (
 	"net/http"
+	"reflect"
 	"testing"
 )
 
@@ -10,6 +11,24 @@ type TestModel struct {
 	Name string
 }
 
+type CodedTestModel struct {
+	Name  string
+	Code  string
+	Label string
+}
+
+type CodedORMIntegrator struct {
+	MockORMIntegrator
+}
+
+func (o *CodedORMIntegrator) GetPrimaryKeyValue(instance interface{}) (interface{}, error) {
+	return instance.(*CodedTestModel).Code, nil
+}
+
+func (o *CodedORMIntegrator) GetPrimaryKeyType(interface{}) (reflect.Type, error) {
+	return reflect.TypeOf(""), nil
+}
+
 func TestModel_GetLink(t *testing.T) {
 	app := &App{Name: "App", Panel: &AdminPanel{Config: AdminConfig{Prefix: "admin"}}}
 	model := Model{Name: "TestModel", App: app}
@@ -62,3 +81,30 @@ func TestModel_GetViewHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestModel_GetPrimaryKeyField(t *testing.T) {
+	panel, err := NewMockAdminPanel()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testApp, err := panel.RegisterApp("TestApp", "Test App", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	coded, err := testApp.RegisterModel(&CodedTestModel{}, &CodedORMIntegrator{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if field, err := coded.GetPrimaryKeyField(); err != nil || field != "Code" {
+		t.Errorf("expected the Code field, got %q, %v", field, err)
+	}
+
+	model, err := testApp.RegisterModel(&TestModel{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if field, err := model.GetPrimaryKeyField(); err != nil || field != "ID" {
+		t.Errorf("expected the ID field by default, got %q, %v", field, err)
+	}
+}
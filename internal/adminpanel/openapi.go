@@ -0,0 +1,450 @@
+package adminpanel
+
+import (
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/form"
+	"github.com/go-advanced-admin/admin/internal/form/fields"
+	"github.com/go-advanced-admin/admin/internal/utils"
+	"github.com/google/uuid"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification generated documents follow.
+const OpenAPIVersion = "3.0.3"
+
+// OpenAPIDocument represents an OpenAPI 3 document describing the JSON API.
+type OpenAPIDocument struct {
+	OpenAPI    string                      `json:"openapi"`
+	Info       OpenAPIInfo                 `json:"info"`
+	Tags       []OpenAPITag                `json:"tags,omitempty"`
+	Paths      map[string]*OpenAPIPathItem `json:"paths"`
+	Components OpenAPIComponents           `json:"components"`
+}
+
+// OpenAPIInfo holds the metadata of an OpenAPI document.
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenAPITag groups the operations of an app.
+type OpenAPITag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIPathItem describes the operations available on a path.
+type OpenAPIPathItem struct {
+	Parameters []OpenAPIParameter `json:"parameters,omitempty"`
+	Get        *OpenAPIOperation  `json:"get,omitempty"`
+	Post       *OpenAPIOperation  `json:"post,omitempty"`
+	Patch      *OpenAPIOperation  `json:"patch,omitempty"`
+	Delete     *OpenAPIOperation  `json:"delete,omitempty"`
+}
+
+// OpenAPIOperation describes a single API operation on a path.
+type OpenAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPIParameter describes a path or query parameter.
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIRequestBody describes the body of a request.
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required,omitempty"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse describes a single response of an operation.
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType describes the schema of a request or response body.
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIComponents holds the reusable schemas of an OpenAPI document.
+type OpenAPIComponents struct {
+	Schemas map[string]*OpenAPISchema `json:"schemas"`
+}
+
+// OpenAPISchema describes the type of a value.
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	ReadOnly             bool                      `json:"readOnly,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Pattern              string                    `json:"pattern,omitempty"`
+	MinLength            *uint                     `json:"minLength,omitempty"`
+	MaxLength            *uint                     `json:"maxLength,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty"`
+	Maximum              *float64                  `json:"maximum,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+const openAPIErrorSchema = "Error"
+
+func openAPIRef(name string) *OpenAPISchema {
+	return &OpenAPISchema{Ref: "#/components/schemas/" + name}
+}
+
+func openAPIJSONContent(schema *OpenAPISchema) map[string]OpenAPIMediaType {
+	return map[string]OpenAPIMediaType{"application/json": {Schema: schema}}
+}
+
+// GetOpenAPISchemaName returns the name of the model's schema in the OpenAPI document.
+func (m *Model) GetOpenAPISchemaName() string {
+	return m.App.Name + "_" + m.Name
+}
+
+// GetOpenAPIDocument generates an OpenAPI document describing the JSON API of the models the user may read.
+func (ap *AdminPanel) GetOpenAPIDocument(data interface{}) (*OpenAPIDocument, error) {
+	doc := &OpenAPIDocument{
+		OpenAPI: OpenAPIVersion,
+		Info:    OpenAPIInfo{Title: ap.Config.Name, Version: ap.Config.GetAPIVersion()},
+		Paths:   make(map[string]*OpenAPIPathItem),
+		Components: OpenAPIComponents{Schemas: map[string]*OpenAPISchema{
+			openAPIErrorSchema: {
+				Type: "object",
+				Properties: map[string]*OpenAPISchema{
+					"error":       {Type: "string"},
+					"formErrors":  {Type: "array", Items: &OpenAPISchema{Type: "string"}},
+					"fieldErrors": {Type: "object", AdditionalProperties: &OpenAPISchema{Type: "array", Items: &OpenAPISchema{Type: "string"}}},
+				},
+				Required: []string{"error"},
+			},
+		}},
+	}
+
+	for _, app := range ap.AppsSlice {
+		allowed, err := ap.PermissionChecker.HasAppReadPermission(app.Name, data)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			continue
+		}
+		tagAdded := false
+		for _, model := range app.ModelsSlice {
+			allowed, err = ap.PermissionChecker.HasModelReadPermission(app.Name, model.Name, data)
+			if err != nil {
+				return nil, err
+			}
+			if !allowed {
+				continue
+			}
+			if !tagAdded {
+				doc.Tags = append(doc.Tags, OpenAPITag{Name: app.Name, Description: app.DisplayName})
+				tagAdded = true
+			}
+			if err = model.addToOpenAPIDocument(doc); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return doc, nil
+}
+
+func (m *Model) addToOpenAPIDocument(doc *OpenAPIDocument) error {
+	schemaName := m.GetOpenAPISchemaName()
+	primaryKeyType, err := m.GetPrimaryKeyType()
+	if err != nil {
+		return err
+	}
+	idSchema := openAPISchemaForType(primaryKeyType)
+
+	instanceFields := m.getFieldsWhere(func(fieldConfig FieldConfig) bool { return fieldConfig.IncludeInInstanceView })
+	listFields := m.getFieldsWhere(func(fieldConfig FieldConfig) bool { return fieldConfig.IncludeInListDisplay })
+	doc.Components.Schemas[schemaName] = openAPIInstanceSchema(m.DisplayName, idSchema, instanceFields)
+	doc.Components.Schemas[schemaName+"_ListItem"] = openAPIInstanceSchema(m.DisplayName, idSchema, listFields)
+	doc.Components.Schemas[schemaName+"_List"] = &OpenAPISchema{
+		Type: "object",
+		Properties: map[string]*OpenAPISchema{
+			"count":      {Type: "integer", Minimum: openAPIFloat(0)},
+			"page":       {Type: "integer", Minimum: openAPIFloat(1)},
+			"perPage":    {Type: "integer", Minimum: openAPIFloat(1)},
+			"totalPages": {Type: "integer", Minimum: openAPIFloat(0)},
+			"results":    {Type: "array", Items: openAPIRef(schemaName + "_ListItem")},
+		},
+		Required: []string{"count", "page", "perPage", "totalPages", "results"},
+	}
+
+	addForm, err := m.NewAddForm()
+	if err != nil {
+		return err
+	}
+	doc.Components.Schemas[schemaName+"_Create"] = m.openAPIFormSchema(addForm, true)
+	editForm, err := m.NewEditForm(nil)
+	if err != nil {
+		return err
+	}
+	doc.Components.Schemas[schemaName+"_Update"] = m.openAPIFormSchema(editForm, false)
+
+	errorResponse := func(description string) OpenAPIResponse {
+		return OpenAPIResponse{Description: description, Content: openAPIJSONContent(openAPIRef(openAPIErrorSchema))}
+	}
+	tags := []string{m.App.Name}
+
+	listParameters := []OpenAPIParameter{
+		{Name: "page", In: "query", Description: "Page number, starting at 1.", Schema: &OpenAPISchema{Type: "integer", Minimum: openAPIFloat(1)}},
+		{Name: "perPage", In: "query", Description: "Number of instances per page.", Schema: &OpenAPISchema{Type: "integer", Minimum: openAPIFloat(1), Maximum: openAPIFloat(float64(APIMaxPerPage))}},
+		{Name: "search", In: "query", Description: "Search term matched against the searchable fields.", Schema: &OpenAPISchema{Type: "string"}},
+		{Name: "ordering", In: "query", Description: "Comma separated fields to order by, prefixed with '-' for descending order.", Schema: &OpenAPISchema{Type: "string"}},
+	}
+	for _, filter := range m.ListFilters {
+		parameter := OpenAPIParameter{Name: filter.GetParam(), In: "query", Schema: &OpenAPISchema{Type: "string"}}
+		if fieldConfig, ok := m.GetFieldConfig(filter.Field); ok {
+			parameter.Description = fmt.Sprintf("Filter by %s.", fieldConfig.DisplayName)
+			for _, choice := range filter.GetChoices() {
+				parameter.Schema.Enum = append(parameter.Schema.Enum, choice.Value)
+			}
+		}
+		listParameters = append(listParameters, parameter)
+	}
+
+	doc.Paths[m.GetFullAPILink()] = &OpenAPIPathItem{
+		Get: &OpenAPIOperation{
+			OperationID: "list" + schemaName,
+			Summary:     fmt.Sprintf("List %s instances", m.DisplayName),
+			Tags:        tags,
+			Parameters:  listParameters,
+			Responses: map[string]OpenAPIResponse{
+				"200": {Description: "A page of instances.", Content: openAPIJSONContent(openAPIRef(schemaName + "_List"))},
+				"403": errorResponse("Forbidden."),
+			},
+		},
+		Post: &OpenAPIOperation{
+			OperationID: "create" + schemaName,
+			Summary:     fmt.Sprintf("Create a %s instance", m.DisplayName),
+			Tags:        tags,
+			RequestBody: &OpenAPIRequestBody{Required: true, Content: openAPIJSONContent(openAPIRef(schemaName + "_Create"))},
+			Responses: map[string]OpenAPIResponse{
+				"201": {Description: "The created instance.", Content: openAPIJSONContent(openAPIRef(schemaName))},
+				"400": errorResponse("Validation failed."),
+				"403": errorResponse("Forbidden."),
+			},
+		},
+	}
+
+	doc.Paths[m.GetFullAPILink()+"/{id}"] = &OpenAPIPathItem{
+		Parameters: []OpenAPIParameter{{Name: "id", In: "path", Required: true, Schema: idSchema}},
+		Get: &OpenAPIOperation{
+			OperationID: "retrieve" + schemaName,
+			Summary:     fmt.Sprintf("Retrieve a %s instance", m.DisplayName),
+			Tags:        tags,
+			Responses: map[string]OpenAPIResponse{
+				"200": {Description: "The instance.", Content: openAPIJSONContent(openAPIRef(schemaName))},
+				"403": errorResponse("Forbidden."),
+				"404": errorResponse("Instance not found."),
+			},
+		},
+		Patch: &OpenAPIOperation{
+			OperationID: "update" + schemaName,
+			Summary:     fmt.Sprintf("Partially update a %s instance", m.DisplayName),
+			Tags:        tags,
+			RequestBody: &OpenAPIRequestBody{Required: true, Content: openAPIJSONContent(openAPIRef(schemaName + "_Update"))},
+			Responses: map[string]OpenAPIResponse{
+				"200": {Description: "The updated instance.", Content: openAPIJSONContent(openAPIRef(schemaName))},
+				"400": errorResponse("Validation failed."),
+				"403": errorResponse("Forbidden."),
+				"404": errorResponse("Instance not found."),
+			},
+		},
+		Delete: &OpenAPIOperation{
+			OperationID: "delete" + schemaName,
+			Summary:     fmt.Sprintf("Delete a %s instance", m.DisplayName),
+			Tags:        tags,
+			Responses: map[string]OpenAPIResponse{
+				"204": {Description: "The instance was deleted."},
+				"403": errorResponse("Forbidden."),
+			},
+		},
+	}
+	return nil
+}
+
+func openAPIInstanceSchema(description string, idSchema *OpenAPISchema, fieldConfigs []FieldConfig) *OpenAPISchema {
+	fieldsSchema := &OpenAPISchema{Type: "object", Properties: make(map[string]*OpenAPISchema)}
+	for _, fieldConfig := range fieldConfigs {
+		schema := openAPISchemaForType(fieldConfig.FieldType)
+		schema.Description = fieldConfig.DisplayName
+		schema.Nullable = fieldConfig.IsPointer
+		fieldsSchema.Properties[fieldConfig.Name] = schema
+	}
+	return &OpenAPISchema{
+		Type:        "object",
+		Description: description,
+		Properties: map[string]*OpenAPISchema{
+			"id":     idSchema,
+			"repr":   {Type: "string", ReadOnly: true},
+			"fields": fieldsSchema,
+		},
+		Required: []string{"id", "repr", "fields"},
+	}
+}
+
+// openAPIFormSchema describes the request body accepted by the form. Required fields are only listed for create
+// requests, since updates are partial.
+func (m *Model) openAPIFormSchema(formInstance form.Form, withRequired bool) *OpenAPISchema {
+	schema := &OpenAPISchema{Type: "object", Properties: make(map[string]*OpenAPISchema)}
+	for _, field := range formInstance.GetFields() {
+		var fieldType reflect.Type
+		if fieldConfig, ok := m.GetFieldConfig(field.GetName()); ok {
+			fieldType = fieldConfig.FieldType
+		}
+		fieldSchema, required := openAPISchemaForFormField(field, fieldType)
+		fieldSchema.Description = field.GetLabel()
+		schema.Properties[field.GetName()] = fieldSchema
+		if required && withRequired {
+			schema.Required = append(schema.Required, field.GetName())
+		}
+	}
+	return schema
+}
+
+func openAPISchemaForFormField(field form.Field, fieldType reflect.Type) (*OpenAPISchema, bool) {
+	switch f := field.(type) {
+	case *fields.TextField:
+		schema := &OpenAPISchema{Type: "string", MinLength: f.MinLength, MaxLength: f.MaxLength}
+		if f.Regex != nil {
+			schema.Pattern = *f.Regex
+		}
+		return schema, f.Required
+	case *fields.IntegerField:
+		schema := &OpenAPISchema{Type: "integer"}
+		if f.MinValue != nil {
+			schema.Minimum = openAPIFloat(float64(*f.MinValue))
+		}
+		if f.MaxValue != nil {
+			schema.Maximum = openAPIFloat(float64(*f.MaxValue))
+		}
+		return schema, f.Required
+	case *fields.FloatField:
+		return &OpenAPISchema{Type: "number", Minimum: f.MinValue, Maximum: f.MaxValue}, f.Required
+	case *fields.BooleanField:
+		return &OpenAPISchema{Type: "boolean"}, f.Required
+	case *fields.ChoiceField:
+		schema := &OpenAPISchema{Type: "string"}
+		for _, choice := range f.Choices {
+			schema.Enum = append(schema.Enum, choice.Value)
+		}
+		return schema, f.Required
+	case *fields.MultipleChoiceField:
+		items := &OpenAPISchema{Type: "string"}
+		for _, choice := range f.Choices {
+			items.Enum = append(items.Enum, choice.Value)
+		}
+		return &OpenAPISchema{Type: "array", Items: items}, f.Required
+	case *fields.DateField:
+		return &OpenAPISchema{Type: "string", Format: "date"}, f.Required
+	case *fields.EmailField:
+		return &OpenAPISchema{Type: "string", Format: "email"}, f.Required
+	case *fields.URLField:
+		return &OpenAPISchema{Type: "string", Format: "uri"}, f.Required
+	case *fields.UUIDField:
+		return &OpenAPISchema{Type: "string", Format: "uuid"}, f.Required
+	}
+	return openAPISchemaForType(fieldType), false
+}
+
+func openAPISchemaForType(t reflect.Type) *OpenAPISchema {
+	if t == nil {
+		return &OpenAPISchema{}
+	}
+	if t.Kind() == reflect.Ptr {
+		schema := openAPISchemaForType(t.Elem())
+		schema.Nullable = true
+		return schema
+	}
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	case reflect.TypeOf(uuid.UUID{}):
+		return &OpenAPISchema{Type: "string", Format: "uuid"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		format := "int64"
+		if t.Kind() != reflect.Uint && t.Kind() != reflect.Uint64 {
+			format = "int32"
+		}
+		return &OpenAPISchema{Type: "integer", Format: format, Minimum: openAPIFloat(0)}
+	case reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		return &OpenAPISchema{Type: "array", Items: openAPISchemaForType(t.Elem())}
+	case reflect.Map, reflect.Struct:
+		return &OpenAPISchema{Type: "object"}
+	}
+	return &OpenAPISchema{}
+}
+
+func openAPIFloat(value float64) *float64 {
+	return &value
+}
+
+// GetOpenAPIHandler returns the HTTP handler function serving the OpenAPI document as JSON, or as YAML when the
+// format query parameter is "yaml".
+func (ap *AdminPanel) GetOpenAPIHandler() ResponseHandlerFunc {
+	return func(data interface{}) *Response {
+		allowed, err := ap.PermissionChecker.HasReadPermission(data)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+		if !allowed {
+			return NewAPIErrorResponse(http.StatusForbidden, fmt.Errorf("forbidden"))
+		}
+
+		doc, err := ap.GetOpenAPIDocument(data)
+		if err != nil {
+			return NewAPIErrorResponse(http.StatusInternalServerError, err)
+		}
+
+		switch format := ap.Web.GetQueryParam(data, "format"); format {
+		case "", "json":
+			return NewJSONResponse(http.StatusOK, doc)
+		case "yaml":
+			body, err := utils.MarshalYAML(doc)
+			if err != nil {
+				return NewAPIErrorResponse(http.StatusInternalServerError, err)
+			}
+			return &Response{StatusCode: http.StatusOK, ContentType: "application/yaml", Body: body}
+		default:
+			return NewAPIErrorResponse(http.StatusBadRequest, fmt.Errorf("unsupported format '%s'", format))
+		}
+	}
+}
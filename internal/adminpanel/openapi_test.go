@@ -0,0 +1,104 @@
+package adminpanel
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAdminPanel_GetOpenAPIHandler(t *testing.T) {
+	web, model, _ := newHTTPTestPanel(t, MockPermissionFunc, apiTestConfig, newAPITestBob())
+
+	rec := serveAPIRequest(web, http.MethodGet, "/admin/openapi", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var doc OpenAPIDocument
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc.OpenAPI != OpenAPIVersion || doc.Info.Version != "1.0.0" {
+		t.Errorf("unexpected document header: %+v %+v", doc.OpenAPI, doc.Info)
+	}
+
+	listPath, ok := doc.Paths[model.GetFullAPILink()]
+	if !ok || listPath.Get == nil || listPath.Post == nil {
+		t.Fatalf("expected list and create operations, got %v", doc.Paths)
+	}
+	instancePath, ok := doc.Paths[model.GetFullAPILink()+"/{id}"]
+	if !ok || instancePath.Get == nil || instancePath.Patch == nil || instancePath.Delete == nil {
+		t.Fatalf("expected retrieve, update and delete operations, got %v", doc.Paths)
+	}
+	if len(instancePath.Parameters) != 1 || instancePath.Parameters[0].Schema.Type != "integer" {
+		t.Errorf("expected an integer id parameter, got %+v", instancePath.Parameters)
+	}
+
+	schemaName := model.GetOpenAPISchemaName()
+	createSchema := doc.Components.Schemas[schemaName+"_Create"]
+	if createSchema == nil {
+		t.Fatalf("expected a create schema, got %v", doc.Components.Schemas)
+	}
+	if _, ok := createSchema.Properties["ID"]; ok {
+		t.Error("expected the ID field to be excluded from the create schema")
+	}
+	if !reflect.DeepEqual(createSchema.Required, []string{"Name"}) {
+		t.Errorf("expected Name to be required, got %v", createSchema.Required)
+	}
+	if name := createSchema.Properties["Name"]; name.Type != "string" || name.MaxLength == nil || *name.MaxLength != 10 {
+		t.Errorf("unexpected Name schema: %+v", name)
+	}
+	if age := createSchema.Properties["Age"]; age.Type != "integer" || age.Minimum == nil || *age.Minimum != 0 {
+		t.Errorf("unexpected Age schema: %+v", age)
+	}
+	if updateSchema := doc.Components.Schemas[schemaName+"_Update"]; updateSchema == nil || len(updateSchema.Required) != 0 {
+		t.Errorf("expected an update schema without required fields, got %+v", updateSchema)
+	}
+	fields := doc.Components.Schemas[schemaName].Properties["fields"]
+	if fields == nil || fields.Properties["ID"].Format != "int64" {
+		t.Errorf("expected instance fields to be described, got %+v", fields)
+	}
+}
+
+func TestAdminPanel_GetOpenAPIHandler_YAML(t *testing.T) {
+	web, _, _ := newHTTPTestPanel(t, MockPermissionFunc, apiTestConfig, newAPITestBob())
+
+	rec := serveAPIRequest(web, http.MethodGet, "/admin/openapi?format=yaml", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/yaml" {
+		t.Errorf("expected YAML content type, got %q", got)
+	}
+	if !strings.Contains(rec.Body.String(), "openapi: \"3.0.3\"\n") {
+		t.Errorf("expected a YAML document, got %s", rec.Body.String())
+	}
+
+	if rec = serveAPIRequest(web, http.MethodGet, "/admin/openapi?format=xml", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unsupported format, got %d", rec.Code)
+	}
+}
+
+func TestAdminPanel_GetOpenAPIDocument_Permissions(t *testing.T) {
+	denyModels := func(request PermissionRequest, _ interface{}) (bool, error) {
+		return request.ModelName == nil, nil
+	}
+	_, model, _ := newHTTPTestPanel(t, denyModels, apiTestConfig, newAPITestBob())
+
+	doc, err := model.App.Panel.GetOpenAPIDocument(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(doc.Paths) != 0 {
+		t.Errorf("expected models without read permission to be left out, got %v", doc.Paths)
+	}
+}
+
+func TestNewAdminPanel_OpenAPIRequiresAPI(t *testing.T) {
+	config := NewDefaultAdminConfig()
+	config.OpenAPIRoute = "openapi"
+	if _, err := NewAdminPanel(&MockORMIntegrator{}, &MockWebIntegrator{}, MockPermissionFunc, config); err == nil {
+		t.Error("expected an error when the OpenAPI route is set without the API prefix")
+	}
+}
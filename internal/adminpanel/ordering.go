@@ -0,0 +1,173 @@
+package adminpanel
+
+import (
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/utils"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// OrderingField describes a field a list query is ordered by.
+type OrderingField struct {
+	Field      string
+	Descending bool
+}
+
+// String returns the ordering in query parameter form, prefixed with "-" when descending.
+func (o OrderingField) String() string {
+	if o.Descending {
+		return "-" + o.Field
+	}
+	return o.Field
+}
+
+// AdminModelDefaultOrderingInterface allows a model to define the ordering of its list view when none is requested.
+type AdminModelDefaultOrderingInterface interface {
+	// AdminDefaultOrdering returns the field names to order by, prefixed with "-" for descending order.
+	AdminDefaultOrdering() []string
+}
+
+// ListColumn describes a column of the model list view.
+type ListColumn struct {
+	Field      FieldConfig
+	Sortable   bool
+	Ordered    bool
+	Descending bool
+	SortLink   string
+}
+
+// GetFieldConfig returns the configuration of the field with the given name.
+func (m *Model) GetFieldConfig(name string) (*FieldConfig, bool) {
+	for i := range m.Fields {
+		if m.Fields[i].Name == name {
+			return &m.Fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// ParseOrdering parses a comma separated ordering such as "Name,-ID". Unknown and non-sortable fields are ignored.
+func (m *Model) ParseOrdering(value string) []OrderingField {
+	ordering := make([]OrderingField, 0)
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		orderingField := parseOrderingField(strings.TrimSpace(part))
+		if orderingField.Field == "" || seen[orderingField.Field] {
+			continue
+		}
+		fieldConfig, ok := m.GetFieldConfig(orderingField.Field)
+		if !ok || !fieldConfig.Sortable {
+			continue
+		}
+		seen[orderingField.Field] = true
+		ordering = append(ordering, orderingField)
+	}
+	return ordering
+}
+
+// GetOrdering returns the ordering requested by the query parameter value, ignoring the fields missing from the given
+// readable fields, and falls back to the model's default ordering when the value holds no sortable readable field.
+func (m *Model) GetOrdering(value string, readableFields []string) []OrderingField {
+	ordering := make([]OrderingField, 0)
+	for _, orderingField := range m.ParseOrdering(value) {
+		if utils.ContainsString(readableFields, orderingField.Field) {
+			ordering = append(ordering, orderingField)
+		}
+	}
+	if len(ordering) == 0 {
+		return m.DefaultOrdering
+	}
+	return ordering
+}
+
+// GetListColumns returns the columns of the list view. Each sortable column links to the list ordered by it,
+// toggling the direction when it already is the primary ordering. The given query parameters are preserved.
+func (m *Model) GetListColumns(ordering []OrderingField, params url.Values) []ListColumn {
+	columns := make([]ListColumn, 0)
+	for _, fieldConfig := range m.Fields {
+		if !fieldConfig.IncludeInListDisplay {
+			continue
+		}
+		column := ListColumn{Field: fieldConfig, Sortable: fieldConfig.Sortable}
+		if column.Sortable {
+			toggled := OrderingField{Field: fieldConfig.Name}
+			for i, orderingField := range ordering {
+				if orderingField.Field == fieldConfig.Name {
+					column.Ordered = true
+					column.Descending = orderingField.Descending
+					if i == 0 {
+						toggled.Descending = !orderingField.Descending
+					}
+				}
+			}
+			parts := []string{toggled.String()}
+			for _, orderingField := range ordering {
+				if orderingField.Field != fieldConfig.Name {
+					parts = append(parts, orderingField.String())
+				}
+			}
+
+			linkParams := url.Values{}
+			for key, values := range params {
+				linkParams[key] = values
+			}
+			linkParams.Set("ordering", strings.Join(parts, ","))
+			linkParams.Del("page")
+			column.SortLink = fmt.Sprintf("%s?%s", m.GetFullLink(), linkParams.Encode())
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+func parseOrderingField(value string) OrderingField {
+	if strings.HasPrefix(value, "-") {
+		return OrderingField{Field: strings.TrimPrefix(value, "-"), Descending: true}
+	}
+	return OrderingField{Field: value}
+}
+
+func parseDefaultOrdering(m *Model, values []string) ([]OrderingField, error) {
+	ordering := make([]OrderingField, 0, len(values))
+	for _, value := range values {
+		orderingField := parseOrderingField(value)
+		if _, ok := m.GetFieldConfig(orderingField.Field); !ok {
+			return nil, fmt.Errorf("default ordering field '%s' not found in model '%s'", orderingField.Field, m.Name)
+		}
+		ordering = append(ordering, orderingField)
+	}
+	return ordering, nil
+}
+
+func sortInstances(instances []interface{}, ordering []OrderingField) error {
+	if len(ordering) == 0 {
+		return nil
+	}
+
+	var sortErr error
+	sort.SliceStable(instances, func(i, j int) bool {
+		for _, orderingField := range ordering {
+			a, err := utils.GetFieldValue(instances[i], orderingField.Field)
+			if err != nil {
+				sortErr = err
+				return false
+			}
+			b, err := utils.GetFieldValue(instances[j], orderingField.Field)
+			if err != nil {
+				sortErr = err
+				return false
+			}
+			result := utils.CompareValues(a, b)
+			if result == 0 {
+				continue
+			}
+			if orderingField.Descending {
+				return result > 0
+			}
+			return result < 0
+		}
+		return false
+	})
+	return sortErr
+}
@@ -0,0 +1,110 @@
+package adminpanel
+
+import (
+	"net/url"
+	"testing"
+)
+
+type OrderedTestModel struct {
+	ID     uint
+	Name   string
+	Secret string `admin:"sortable:exclude"`
+}
+
+func (m *OrderedTestModel) AdminDefaultOrdering() []string {
+	return []string{"-ID"}
+}
+
+func TestModel_GetOrdering(t *testing.T) {
+	model := newTestModel(t, &OrderedTestModel{}, nil)
+
+	allFields := getFieldNames(model.Fields)
+
+	tests := []struct {
+		name     string
+		value    string
+		readable []string
+		expected []OrderingField
+	}{
+		{"Default", "", allFields, []OrderingField{{Field: "ID", Descending: true}}},
+		{"Single", "Name", allFields, []OrderingField{{Field: "Name"}}},
+		{"Multiple", "Name,-ID", allFields, []OrderingField{{Field: "Name"}, {Field: "ID", Descending: true}}},
+		{"Unknown Field Ignored", "Unknown,Name", allFields, []OrderingField{{Field: "Name"}}},
+		{"Not Sortable Ignored", "Secret", allFields, []OrderingField{{Field: "ID", Descending: true}}},
+		{"Duplicates Ignored", "Name,-Name", allFields, []OrderingField{{Field: "Name"}}},
+		{"Unreadable Field Ignored", "Name,-ID", []string{"ID"}, []OrderingField{{Field: "ID", Descending: true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordering := model.GetOrdering(tt.value, tt.readable)
+			if len(ordering) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, ordering)
+			}
+			for i := range ordering {
+				if ordering[i] != tt.expected[i] {
+					t.Errorf("expected %v, got %v", tt.expected, ordering)
+				}
+			}
+		})
+	}
+}
+
+func TestModel_GetListColumns(t *testing.T) {
+	model := newTestModel(t, &OrderedTestModel{}, nil)
+
+	columns := model.GetListColumns([]OrderingField{{Field: "Name"}, {Field: "ID", Descending: true}}, url.Values{"search": {"abc"}})
+	if len(columns) != 3 {
+		t.Fatalf("expected 3 columns, got %d", len(columns))
+	}
+
+	expectedLinks := map[string]string{
+		"ID":   "/admin/a/TestApp/OrderedTestModel?ordering=ID%2CName&search=abc",
+		"Name": "/admin/a/TestApp/OrderedTestModel?ordering=-Name%2C-ID&search=abc",
+	}
+	for _, column := range columns {
+		if column.Field.Name == "Secret" {
+			if column.Sortable || column.SortLink != "" {
+				t.Errorf("expected column 'Secret' not to be sortable")
+			}
+			continue
+		}
+		if !column.Ordered {
+			t.Errorf("expected column '%s' to be ordered", column.Field.Name)
+		}
+		if column.SortLink != expectedLinks[column.Field.Name] {
+			t.Errorf("expected link %s, got %s", expectedLinks[column.Field.Name], column.SortLink)
+		}
+	}
+}
+
+func TestModel_FetchInstancesPage_Ordering(t *testing.T) {
+	orm := &SliceORMIntegrator{Instances: newTestModelInstances(5)}
+	model := newTestModel(t, &OrderedTestModel{}, orm)
+
+	page, err := model.FetchInstancesPage(InstancesQuery{Ordering: []OrderingField{{Field: "ID", Descending: true}}, Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page) != 2 || page[0].(*TestModel).ID != 5 || page[1].(*TestModel).ID != 4 {
+		t.Errorf("expected instances 5 and 4, got %v", page)
+	}
+}
+
+func TestRegisterModel_InvalidSortableTag(t *testing.T) {
+	panel, err := NewMockAdminPanel()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testApp, err := panel.RegisterApp("TestApp", "Test App", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	type InvalidSortableModel struct {
+		ID   uint
+		Name string `admin:"sortable:invalid"`
+	}
+	if _, err = testApp.RegisterModel(&InvalidSortableModel{}, nil); err == nil {
+		t.Error("expected an error due to invalid sortable tag value")
+	}
+}
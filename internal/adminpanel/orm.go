@@ -1,6 +1,9 @@
 package adminpanel
 
-import "reflect"
+import (
+	"context"
+	"reflect"
+)
 
 // ORMIntegrator defines the interface for integrating ORMs with the admin panel.
 type ORMIntegrator interface {
@@ -40,3 +43,54 @@ type ORMIntegrator interface {
 	// UpdateInstanceOnlyFields updates an existing instance with only the specified fields.
 	UpdateInstanceOnlyFields(instance interface{}, fields []string, primaryKey interface{}) error
 }
+
+// InstancesQuery describes a list query against a model.
+type InstancesQuery struct {
+	// Fields lists the fields to fetch for each instance.
+	Fields []string
+	// Search is the free-text search query. An empty string disables searching.
+	Search string
+	// SearchFields lists the fields that Search is matched against.
+	SearchFields []string
+	// Filters lists the constraints every returned instance must satisfy.
+	Filters []FilterExpression
+	// Ordering lists the fields to order the results by, in order of precedence.
+	Ordering []OrderingField
+	// Offset is the number of matching instances to skip.
+	Offset uint
+	// Limit is the maximum number of instances to return. Zero means no limit.
+	Limit uint
+}
+
+// PaginatedORMIntegrator is an optional interface ORM integrators can implement to paginate list queries in the
+// database instead of in memory.
+type PaginatedORMIntegrator interface {
+	// FetchInstancesPage retrieves the instances matching the query, honoring its filters, ordering, offset and limit.
+	FetchInstancesPage(model interface{}, query InstancesQuery) (interface{}, error)
+
+	// CountInstances returns the number of instances matching the query, ignoring its offset and limit.
+	CountInstances(model interface{}, query InstancesQuery) (uint, error)
+}
+
+// PrimaryKeyFieldORMIntegrator is an optional interface ORM integrators can implement to name the primary key field
+// of a model. Integrators that do not implement it have the field found from GetPrimaryKeyValue.
+type PrimaryKeyFieldORMIntegrator interface {
+	// GetPrimaryKeyField returns the name of the struct field holding the primary key of the model.
+	GetPrimaryKeyField(model interface{}) (string, error)
+}
+
+// ContextORMIntegrator is an optional interface ORM integrators can implement to run the queries of a request with its
+// context.Context, so they are cancelled when the client disconnects and carry the request's deadline and tracing
+// spans. Integrators that do not implement it are used as they are.
+type ContextORMIntegrator interface {
+	// WithContext returns an integrator running its queries with ctx.
+	WithContext(ctx context.Context) ORMIntegrator
+}
+
+// bindORMContext returns the integrator bound to ctx when it implements ContextORMIntegrator.
+func bindORMContext(orm ORMIntegrator, ctx context.Context) ORMIntegrator {
+	if contextORM, ok := orm.(ContextORMIntegrator); ok && ctx != nil {
+		return contextORM.WithContext(ctx)
+	}
+	return orm
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/go-advanced-admin/admin/internal/logging"
 	"github.com/go-advanced-admin/admin/internal/utils"
 	"net/http"
+	"sync"
 )
 
 // AdminPanel represents the admin panel, which manages apps, models, permissions, and configuration.
@@ -16,29 +17,18 @@ type AdminPanel struct {
 	ORM               ORMIntegrator
 	Web               WebIntegrator
 	Config            AdminConfig
+	permissionFunc    PermissionFunc
+	permissionCaches  *sync.Map
+	transactions      *sync.Map
 }
 
-// GetLogEntries retrieves log entries up to the specified maximum count.
+// GetLogEntries retrieves the most recent log entries the user may view, up to the specified maximum count.
 func (ap *AdminPanel) GetLogEntries(ctx interface{}, maxCount uint) []*logging.LogEntry {
-	if ap.Config.LogStore == nil {
-		return []*logging.LogEntry{}
-	}
-	entries, err := ap.Config.LogStore.GetLogEntries()
+	page, err := ap.QueryLogEntries(ctx, logging.LogQuery{Limit: maxCount})
 	if err != nil {
 		return []*logging.LogEntry{}
 	}
-	entries = entries[:utils.MinInt(len(entries), int(maxCount))]
-	permissibleEntries := make([]*logging.LogEntry, 0)
-	for _, entry := range entries {
-		allowed, err := ap.PermissionChecker.HasLogViewPermission(ctx, entry.ID)
-		if err != nil {
-			continue
-		}
-		if allowed {
-			permissibleEntries = append(permissibleEntries, entry)
-		}
-	}
-	return permissibleEntries
+	return page.Entries
 }
 
 // CreateViewLog creates a log entry when the admin panel is viewed.
@@ -46,6 +36,11 @@ func (ap *AdminPanel) CreateViewLog(ctx interface{}) error {
 	return ap.Config.CreateLog(ctx, logging.LogStoreLevelPanelView, "", nil, "", "")
 }
 
+// CreateLogListViewLog creates a log entry when the log entries are browsed.
+func (ap *AdminPanel) CreateLogListViewLog(ctx interface{}) error {
+	return ap.Config.CreateLog(ctx, logging.LogStoreLevelPanelView, "Admin | LogList", nil, "", "")
+}
+
 // CreateLogViewLog creates a log entry when a log entry is viewed.
 func (ap *AdminPanel) CreateLogViewLog(ctx interface{}, entry logging.LogEntry) error {
 	return ap.Config.CreateLog(ctx, logging.LogStoreLevelPanelView, "Admin | LogView", entry.ID, entry.Repr(), "")
@@ -57,6 +52,7 @@ func (ap *AdminPanel) GetORM() ORMIntegrator {
 }
 
 // NewAdminPanel creates a new admin panel with the given ORM integrator, web integrator, permission function, and configuration.
+// The permission function may be nil when the configuration sets a ContextPermissionChecker.
 func NewAdminPanel(orm ORMIntegrator, web WebIntegrator, permissionsCheck PermissionFunc, config *AdminConfig) (*AdminPanel, error) {
 	if orm == nil {
 		return nil, fmt.Errorf("orm integrator cannot be nil")
@@ -64,12 +60,18 @@ func NewAdminPanel(orm ORMIntegrator, web WebIntegrator, permissionsCheck Permis
 	if web == nil {
 		return nil, fmt.Errorf("web integrator cannot be nil")
 	}
-	if permissionsCheck == nil {
-		return nil, fmt.Errorf("permissions check function cannot be nil")
-	}
 	if config == nil {
 		config = NewDefaultAdminConfig()
 	}
+	if permissionsCheck == nil && config.ContextPermissionChecker == nil {
+		return nil, fmt.Errorf("permissions check function cannot be nil")
+	}
+	if config.OpenAPIRoute != "" && config.APIPrefix == "" {
+		return nil, fmt.Errorf("the OpenAPI route requires the JSON API prefix to be set")
+	}
+	if err := checkTransactionalLogStore(orm, config.LogStore); err != nil {
+		return nil, err
+	}
 	admin := AdminPanel{
 		Apps:              make(map[string]*App),
 		AppsSlice:         make([]*App, 0),
@@ -77,7 +79,15 @@ func NewAdminPanel(orm ORMIntegrator, web WebIntegrator, permissionsCheck Permis
 		ORM:               orm,
 		Web:               web,
 		Config:            *config,
+		permissionFunc:    permissionsCheck,
+		permissionCaches:  &sync.Map{},
+		transactions:      &sync.Map{},
 	}
+	if config.ContextPermissionChecker != nil {
+		admin.permissionFunc = admin.adaptContextPermissionFunc(config.ContextPermissionChecker)
+	}
+	admin.PermissionChecker = admin.checkPermission
+	admin.Config.requestContext = admin.GetContext
 
 	admin.Config.Renderer.RegisterDefaultTemplates(internal.TemplateFiles, "templates/")
 	admin.Config.Renderer.RegisterDefaultAssets(internal.AssetsFiles, "assets/")
@@ -85,7 +95,7 @@ func NewAdminPanel(orm ORMIntegrator, web WebIntegrator, permissionsCheck Permis
 	admin.Config.Renderer.RegisterAssetsFunc(admin.Config.GetAssetLink)
 
 	components := []string{"page.html"}
-	pages := []string{"root", "app", "model", "instance", "edit_instance", "new_instance", "log"}
+	pages := []string{"root", "app", "model", "instance", "edit_instance", "new_instance", "log", "logs", "log_verify", "history", "action_confirmation", "import"}
 
 	for _, page := range pages {
 		err := admin.Config.Renderer.RegisterCompositeDefaultTemplate(page, append([]string{page + ".html"}, components...)...)
@@ -95,12 +105,32 @@ func NewAdminPanel(orm ORMIntegrator, web WebIntegrator, permissionsCheck Permis
 	}
 
 	web.ServeAssets(config.AssetsPrefix, config.Renderer)
-	web.HandleRoute("GET", config.GetPrefix(), admin.GetHandler())
-	web.HandleRoute("GET", config.GetPrefix()+admin.GetLogBaseLink()+"/:id", admin.GetLogHandler())
+	admin.HandleRoute("GET", config.GetPrefix(), admin.GetHandler())
+	admin.HandleRoute("GET", config.GetPrefix()+admin.GetLogBaseLink(), admin.GetLogsHandler())
+	admin.HandleRoute("GET", config.GetPrefix()+admin.GetLogVerifyLink(), admin.GetLogVerifyHandler())
+	admin.HandleRoute("GET", config.GetPrefix()+admin.GetLogBaseLink()+"/:id", admin.GetLogHandler())
+	if config.OpenAPIRoute != "" {
+		admin.HandleResponseRoute("GET", config.GetPrefix()+config.GetOpenAPIRoute(), admin.GetOpenAPIHandler())
+	}
 
+	if admin.Config.LogFailurePolicy == LogBufferAndRetry && admin.Config.LogRetryBuffer == nil {
+		admin.Config.LogRetryBuffer = logging.NewRetryBuffer(admin.Config.LogStore, logging.RetryBufferOptions{
+			OnInsert: admin.Config.streamLogEntry,
+		})
+	}
 	return &admin, nil
 }
 
+// Close stops the background work of the admin panel. Under LogBufferAndRetry, it makes a last attempt to insert the
+// buffered log entries and returns an error when some could not be inserted, so they are not lost silently at
+// shutdown. It is safe to call more than once.
+func (ap *AdminPanel) Close() error {
+	if ap.Config.LogRetryBuffer == nil {
+		return nil
+	}
+	return ap.Config.LogRetryBuffer.Close()
+}
+
 // GetHandler returns the HTTP handler function for the admin panel's root page.
 func (ap *AdminPanel) GetHandler() HandlerFunc {
 	return func(data interface{}) (uint, string) {
@@ -147,7 +177,7 @@ func (ap *AdminPanel) RegisterApp(name, displayName string, orm ORMIntegrator) (
 	app := &App{Name: name, DisplayName: displayName, Models: make(map[string]*Model), ModelsSlice: make([]*Model, 0), Panel: ap, ORM: orm}
 	ap.Apps[name] = app
 	ap.AppsSlice = append(ap.AppsSlice, app)
-	ap.Web.HandleRoute("GET", ap.Config.GetPrefix()+app.GetLink(), app.GetHandler())
+	ap.HandleRoute("GET", ap.Config.GetPrefix()+app.GetLink(), app.GetHandler())
 	return ap.Apps[name], nil
 }
 
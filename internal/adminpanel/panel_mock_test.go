@@ -1,5 +1,24 @@
 package adminpanel
 
+import "testing"
+
 func NewMockAdminPanel() (*AdminPanel, error) {
 	return NewAdminPanel(&MockORMIntegrator{}, &MockWebIntegrator{}, MockPermissionFunc, nil)
 }
+
+// newTestModel registers the given model with the ORM integrator in the "TestApp" app of a new mock admin panel.
+func newTestModel(t *testing.T, instance interface{}, orm ORMIntegrator) *Model {
+	panel, err := NewMockAdminPanel()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testApp, err := panel.RegisterApp("TestApp", "Test App", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	model, err := testApp.RegisterModel(instance, orm)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return model
+}
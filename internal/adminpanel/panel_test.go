@@ -1,8 +1,11 @@
 package adminpanel
 
 import (
+	"bytes"
 	"errors"
+	"github.com/go-advanced-admin/admin/internal/logging"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -107,3 +110,45 @@ func TestAdminPanel_GetFullLink(t *testing.T) {
 		t.Errorf("expected %s, got %s", expected, link)
 	}
 }
+
+func TestAdminPanel_Close(t *testing.T) {
+	store := &failingLogStore{InMemoryLogStore: logging.NewInMemoryLogStore(10), failing: true}
+	config := NewDefaultAdminConfig()
+	config.LogStore = store
+	config.LogStoreLevel = logging.LogStoreLevelCreate
+	config.LogFailurePolicy = LogBufferAndRetry
+	var buffer bytes.Buffer
+	config.LogStreamer = logging.NewLogStreamer(logging.LogStreamerOptions{}, logging.NewWriterSink(&buffer))
+	panel, err := NewAdminPanel(&MockORMIntegrator{}, &MockWebIntegrator{}, MockPermissionFunc, config)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if config.LogRetryBuffer != nil || panel.Config.LogRetryBuffer == nil {
+		t.Fatal("expected the panel to create its own retry buffer without changing the given configuration")
+	}
+
+	if err = panel.Config.CreateLog(nil, logging.LogStoreLevelCreate, "Shop | Orders", 1, "Order 1", ""); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err = panel.Close(); err == nil {
+		t.Error("expected an error for the entries still buffered at shutdown")
+	}
+	_ = config.LogStreamer.Close()
+	if buffer.Len() != 0 {
+		t.Errorf("expected entries not stored yet not to be streamed, got %q", buffer.String())
+	}
+	config.LogStreamer = logging.NewLogStreamer(logging.LogStreamerOptions{}, logging.NewWriterSink(&buffer))
+	panel.Config.LogStreamer = config.LogStreamer
+
+	store.failing = false
+	if err = panel.Close(); err != nil {
+		t.Errorf("expected the buffered entries to be inserted, got %v", err)
+	}
+	if entries, _ := store.GetLogEntries(); len(entries) != 1 {
+		t.Errorf("expected 1 stored entry, got %d", len(entries))
+	}
+	_ = config.LogStreamer.Close()
+	if strings.Count(buffer.String(), "\n") != 1 {
+		t.Errorf("expected the stored entry to be streamed, got %q", buffer.String())
+	}
+}
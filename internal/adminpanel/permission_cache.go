@@ -0,0 +1,203 @@
+package adminpanel
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// BatchPermissionChecker evaluates many permission requests in a single call, for example with one database query.
+// When set in the admin configuration, it is used to preload the permissions the handlers need for a page.
+type BatchPermissionChecker interface {
+	// CheckPermissions returns whether each request is allowed, in the order of the requests.
+	CheckPermissions(requests []PermissionRequest, ctx interface{}) ([]bool, error)
+}
+
+// permissionCache memoizes the permission results of a single request.
+type permissionCache struct {
+	mu      sync.Mutex
+	results map[string]bool
+}
+
+func (c *permissionCache) get(key string) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	allowed, ok := c.results[key]
+	return allowed, ok
+}
+
+func (c *permissionCache) set(key string, allowed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[key] = allowed
+}
+
+// permissionCacheKey returns a key identifying the permission request.
+func permissionCacheKey(request PermissionRequest) string {
+	parts := make([]string, 5)
+	for i, value := range []*string{request.AppName, request.ModelName, request.FieldName} {
+		if value != nil {
+			parts[i] = "+" + *value
+		}
+	}
+	if request.InstanceID != nil {
+		parts[3] = fmt.Sprintf("%T:%v", request.InstanceID, request.InstanceID)
+	}
+	if request.Action != nil {
+		parts[4] = string(*request.Action)
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// isCacheableContext reports whether permission results can be memoized for the context. Only pointers identify a
+// single request reliably.
+func isCacheableContext(ctx interface{}) bool {
+	return ctx != nil && reflect.TypeOf(ctx).Kind() == reflect.Ptr
+}
+
+func (ap *AdminPanel) getPermissionCache(ctx interface{}) *permissionCache {
+	if ap.permissionCaches == nil || !isCacheableContext(ctx) {
+		return nil
+	}
+	cache, ok := ap.permissionCaches.Load(ctx)
+	if !ok {
+		return nil
+	}
+	return cache.(*permissionCache)
+}
+
+// WithPermissionCache wraps the handler so the permission results of each request are memoized until it returns.
+func (ap *AdminPanel) WithPermissionCache(handler HandlerFunc) HandlerFunc {
+	return AdaptResponseHandler(ap.WithResponsePermissionCache(AdaptHandler(handler)))
+}
+
+// WithResponsePermissionCache wraps the response handler so the permission results of each request are memoized
+// until it returns. When the response streams its body, the results stay memoized while the body is written.
+func (ap *AdminPanel) WithResponsePermissionCache(handler ResponseHandlerFunc) ResponseHandlerFunc {
+	return func(data interface{}) *Response {
+		if ap.permissionCaches == nil || !isCacheableContext(data) {
+			return handler(data)
+		}
+		cache := &permissionCache{results: make(map[string]bool)}
+		if _, loaded := ap.permissionCaches.LoadOrStore(data, cache); loaded {
+			return handler(data)
+		}
+		response := func() *Response {
+			defer ap.permissionCaches.Delete(data)
+			return handler(data)
+		}()
+		if response != nil && response.BodyWriter != nil {
+			writeBody := response.BodyWriter
+			response.BodyWriter = func(w io.Writer) error {
+				if _, loaded := ap.permissionCaches.LoadOrStore(data, cache); !loaded {
+					defer ap.permissionCaches.Delete(data)
+				}
+				return writeBody(w)
+			}
+		}
+		return response
+	}
+}
+
+// HandleRoute registers a route with the web integrator, memoizing permission results for each request.
+func (ap *AdminPanel) HandleRoute(method, path string, handler HandlerFunc) {
+	ap.HandleResponseRoute(method, path, AdaptHandler(handler))
+}
+
+// HandleResponseRoute registers a route returning a full Response with the web integrator, memoizing permission
+// results for each request.
+func (ap *AdminPanel) HandleResponseRoute(method, path string, handler ResponseHandlerFunc) {
+	HandleResponseRoute(ap.Web, method, path, ap.WithResponsePermissionCache(handler))
+}
+
+// checkPermission evaluates the request with the panel's permission function, memoizing the result for the current
+// request.
+func (ap *AdminPanel) checkPermission(request PermissionRequest, ctx interface{}) (bool, error) {
+	cache := ap.getPermissionCache(ctx)
+	if cache == nil {
+		return ap.permissionFunc(request, ctx)
+	}
+	key := permissionCacheKey(request)
+	if allowed, ok := cache.get(key); ok {
+		return allowed, nil
+	}
+	allowed, err := ap.permissionFunc(request, ctx)
+	if err != nil {
+		return false, err
+	}
+	cache.set(key, allowed)
+	return allowed, nil
+}
+
+// CheckPermissions evaluates many permission requests at once, using the configured BatchPermissionChecker for the
+// requests not memoized yet. It returns whether each request is allowed, in the order of the requests.
+func (ap *AdminPanel) CheckPermissions(requests []PermissionRequest, ctx interface{}) ([]bool, error) {
+	results := make([]bool, len(requests))
+	cache := ap.getPermissionCache(ctx)
+
+	pending := make([]int, 0, len(requests))
+	pendingKeys := make(map[string]int)
+	keys := make([]string, len(requests))
+	for i, request := range requests {
+		keys[i] = permissionCacheKey(request)
+		if cache != nil {
+			if allowed, ok := cache.get(keys[i]); ok {
+				results[i] = allowed
+				continue
+			}
+		}
+		if _, ok := pendingKeys[keys[i]]; !ok {
+			pendingKeys[keys[i]] = len(pending)
+			pending = append(pending, i)
+		}
+	}
+
+	pendingResults := make([]bool, len(pending))
+	if batch := ap.Config.BatchPermissionChecker; batch != nil && len(pending) > 0 {
+		pendingRequests := make([]PermissionRequest, len(pending))
+		for j, i := range pending {
+			pendingRequests[j] = requests[i]
+		}
+		allowed, err := batch.CheckPermissions(pendingRequests, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(allowed) != len(pending) {
+			return nil, fmt.Errorf("batch permission checker returned %d results for %d requests", len(allowed), len(pending))
+		}
+		copy(pendingResults, allowed)
+	} else {
+		for j, i := range pending {
+			allowed, err := ap.PermissionChecker(requests[i], ctx)
+			if err != nil {
+				return nil, err
+			}
+			pendingResults[j] = allowed
+		}
+	}
+
+	for i := range requests {
+		j, ok := pendingKeys[keys[i]]
+		if !ok {
+			continue
+		}
+		results[i] = pendingResults[j]
+		if cache != nil {
+			cache.set(keys[i], results[i])
+		}
+	}
+	return results, nil
+}
+
+// PreloadPermissions evaluates the requests at once and memoizes the results for the current request, so the
+// individual checks made by the handler afterwards do not reach the permission function. It does nothing when the
+// results cannot be memoized for the context.
+func (ap *AdminPanel) PreloadPermissions(requests []PermissionRequest, ctx interface{}) error {
+	if len(requests) == 0 || ap.getPermissionCache(ctx) == nil {
+		return nil
+	}
+	_, err := ap.CheckPermissions(requests, ctx)
+	return err
+}
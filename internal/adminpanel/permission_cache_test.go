@@ -0,0 +1,182 @@
+package adminpanel
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type countingPermissionChecker struct {
+	mu         sync.Mutex
+	calls      map[string]int
+	batchCalls int
+}
+
+func (c *countingPermissionChecker) check(request PermissionRequest, _ interface{}) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls[permissionCacheKey(request)]++
+	return true, nil
+}
+
+func (c *countingPermissionChecker) CheckPermissions(requests []PermissionRequest, ctx interface{}) ([]bool, error) {
+	c.mu.Lock()
+	c.batchCalls++
+	c.mu.Unlock()
+	results := make([]bool, len(requests))
+	for i := range requests {
+		results[i] = true
+	}
+	return results, nil
+}
+
+// newPermissionCacheTestPanel creates an admin panel counting its permission checks, using the checker as the batch
+// permission checker when batch is true.
+func newPermissionCacheTestPanel(t *testing.T, batch bool) (*HTTPWebIntegrator, *Model, *countingPermissionChecker) {
+	checker := &countingPermissionChecker{calls: make(map[string]int)}
+	web, model, _ := newHTTPTestPanel(t, checker.check, func(config *AdminConfig) {
+		if batch {
+			config.BatchPermissionChecker = checker
+		}
+	}, &ImportTestModel{ID: 2, Name: "Bob"}, &ImportTestModel{ID: 3, Name: "Carol"})
+	return web, model, checker
+}
+
+func TestPermissionCache_MemoizesPerRequest(t *testing.T) {
+	web, model, checker := newPermissionCacheTestPanel(t, false)
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		web.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, model.GetFullLink(), nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	if len(checker.calls) == 0 {
+		t.Fatal("expected the permission function to be called")
+	}
+	for key, calls := range checker.calls {
+		if calls != 2 {
+			t.Errorf("expected request %q to be evaluated once per page, got %d calls", key, calls)
+		}
+	}
+	if len(model.App.Panel.permissionCachesKeys()) != 0 {
+		t.Error("expected the permission caches to be released after each request")
+	}
+}
+
+func TestPermissionCache_UsesBatchChecker(t *testing.T) {
+	web, model, checker := newPermissionCacheTestPanel(t, true)
+
+	rec := httptest.NewRecorder()
+	web.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, model.GetFullLink(), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if checker.batchCalls == 0 {
+		t.Fatal("expected the batch permission checker to be used")
+	}
+	for i := 1; i <= 3; i++ {
+		key := permissionCacheKey(newModelPermissionRequests("TestApp", model.Name, uint(i), UpdateAction)[0])
+		if checker.calls[key] != 0 {
+			t.Errorf("expected instance permissions to be preloaded in a batch, got %d individual calls", checker.calls[key])
+		}
+	}
+}
+
+// PagedImportORMIntegrator serves the instances of ImportORMIntegrator one page at a time.
+type PagedImportORMIntegrator struct {
+	*ImportORMIntegrator
+}
+
+func (o *PagedImportORMIntegrator) FetchInstancesPage(model interface{}, query InstancesQuery) (interface{}, error) {
+	instances, _ := o.FetchInstancesOnlyFields(model, query.Fields)
+	page := make([]interface{}, 0, len(o.Existing))
+	for _, instance := range instances.([]*ImportTestModel) {
+		page = append(page, instance)
+	}
+	return pageInstances(page, query.Offset, query.Limit), nil
+}
+
+func (o *PagedImportORMIntegrator) CountInstances(interface{}, InstancesQuery) (uint, error) {
+	return uint(len(o.Existing)), nil
+}
+
+func TestPermissionCache_ExportUsesBatchChecker(t *testing.T) {
+	web, model, checker := newPermissionCacheTestPanel(t, true)
+	orm := model.GetORM().(*ImportORMIntegrator)
+	count := 2*int(ExportBatchSize) + 1
+	for i := 1; i <= count; i++ {
+		orm.Existing[uint(i)] = &ImportTestModel{ID: uint(i), Name: fmt.Sprintf("User %d", i)}
+	}
+	model.ORM = &PagedImportORMIntegrator{ImportORMIntegrator: orm}
+
+	rec := httptest.NewRecorder()
+	web.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, model.GetFullExportLink()+"?format=jsonl", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if lines := strings.Count(rec.Body.String(), "\n"); lines != count {
+		t.Errorf("expected %d exported instances, got %d", count, lines)
+	}
+
+	if checker.batchCalls < 3 {
+		t.Errorf("expected a batch permission check for each of the 3 export batches, got %d", checker.batchCalls)
+	}
+	for i := 1; i <= count; i++ {
+		key := permissionCacheKey(newModelPermissionRequests("TestApp", model.Name, uint(i), ReadAction)[0])
+		if checker.calls[key] != 0 {
+			t.Fatalf("expected instance %d to be checked in a batch, got %d individual calls", i, checker.calls[key])
+		}
+	}
+	if len(model.App.Panel.permissionCachesKeys()) != 0 {
+		t.Error("expected the permission cache to be released after the export is written")
+	}
+}
+
+func TestAdminPanel_CheckPermissions(t *testing.T) {
+	_, model, checker := newPermissionCacheTestPanel(t, false)
+	panel := model.App.Panel
+
+	requests := newModelPermissionRequests("TestApp", model.Name, uint(1), ReadAction, ReadAction, DeleteAction)
+	results, err := panel.CheckPermissions(requests, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 3 || !results[0] || !results[1] || !results[2] {
+		t.Errorf("unexpected results %v", results)
+	}
+	if calls := checker.calls[permissionCacheKey(requests[0])]; calls != 1 {
+		t.Errorf("expected duplicate requests to be evaluated once, got %d calls", calls)
+	}
+}
+
+func TestPermissionCacheKey(t *testing.T) {
+	app, model, field := "App", "Model", "Field"
+	read := ReadAction
+	keys := map[string]bool{
+		permissionCacheKey(PermissionRequest{Action: &read}):                                                      true,
+		permissionCacheKey(PermissionRequest{AppName: &app, Action: &read}):                                       true,
+		permissionCacheKey(PermissionRequest{AppName: &app, ModelName: &model, Action: &read}):                    true,
+		permissionCacheKey(PermissionRequest{AppName: &app, ModelName: &model, InstanceID: 1, Action: &read}):     true,
+		permissionCacheKey(PermissionRequest{AppName: &app, ModelName: &model, InstanceID: "1", Action: &read}):   true,
+		permissionCacheKey(PermissionRequest{AppName: &app, ModelName: &model, FieldName: &field, Action: &read}): true,
+	}
+	if len(keys) != 6 {
+		t.Errorf("expected distinct keys for distinct requests, got %d", len(keys))
+	}
+}
+
+func (ap *AdminPanel) permissionCachesKeys() []interface{} {
+	keys := make([]interface{}, 0)
+	ap.permissionCaches.Range(func(key, _ interface{}) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
@@ -1,5 +1,7 @@
 package adminpanel
 
+import "context"
+
 // Action represents an action type for permissions.
 type Action string
 
@@ -16,11 +18,13 @@ const (
 	LogViewAction Action = "log_view"
 )
 
-// PermissionRequest represents a request to check permissions for a specific action.
+// PermissionRequest represents a request to check permissions for a specific action. FieldName is set when checking
+// whether a single field of a model may be read or updated.
 type PermissionRequest struct {
 	AppName    *string
 	ModelName  *string
 	InstanceID interface{}
+	FieldName  *string
 	Action     *Action
 }
 
@@ -35,6 +39,10 @@ type Permissions struct {
 // PermissionFunc defines a function type for checking permissions.
 type PermissionFunc func(PermissionRequest, interface{}) (bool, error)
 
+// ContextPermissionFunc is the context-aware variant of PermissionFunc. It receives the context of the request, from
+// which RequestFromContext retrieves the request itself. Set it as AdminConfig.ContextPermissionChecker.
+type ContextPermissionFunc func(ctx context.Context, request PermissionRequest) (bool, error)
+
 // HasLogViewPermission checks if the user has permission to view logs.
 func (p PermissionFunc) HasLogViewPermission(data interface{}, logID interface{}) (bool, error) {
 	action := LogViewAction
@@ -108,10 +116,43 @@ func (p PermissionFunc) HasInstanceDeletePermission(appName, modelName string, i
 	return p(permissionRequest, data)
 }
 
+// HasFieldReadPermission checks if the user has read permission for the specified field. A nil instance ID checks the
+// field of every instance of the model.
+func (p PermissionFunc) HasFieldReadPermission(appName, modelName string, instanceID interface{}, fieldName string, data interface{}) (bool, error) {
+	action := ReadAction
+	permissionRequest := PermissionRequest{AppName: &appName, ModelName: &modelName, Action: &action, InstanceID: instanceID, FieldName: &fieldName}
+	return p(permissionRequest, data)
+}
+
+// HasFieldUpdatePermission checks if the user has update permission for the specified field. A nil instance ID checks
+// the field of every instance of the model.
+func (p PermissionFunc) HasFieldUpdatePermission(appName, modelName string, instanceID interface{}, fieldName string, data interface{}) (bool, error) {
+	action := UpdateAction
+	permissionRequest := PermissionRequest{AppName: &appName, ModelName: &modelName, Action: &action, InstanceID: instanceID, FieldName: &fieldName}
+	return p(permissionRequest, data)
+}
+
+// newModelPermissionRequests returns a request for each action on the given model or one of its instances.
+func newModelPermissionRequests(appName, modelName string, instanceID interface{}, actions ...Action) []PermissionRequest {
+	requests := make([]PermissionRequest, len(actions))
+	for i := range actions {
+		requests[i] = PermissionRequest{AppName: &appName, ModelName: &modelName, InstanceID: instanceID, Action: &actions[i]}
+	}
+	return requests
+}
+
 // GetModelsWithReadPermissions returns models for which the user has read permissions.
 func GetModelsWithReadPermissions(app *App, data interface{}) ([]map[string]interface{}, error) {
 	modelsSlice := make([]map[string]interface{}, 0)
 
+	requests := make([]PermissionRequest, 0, len(app.ModelsSlice)*4)
+	for _, model := range app.ModelsSlice {
+		requests = append(requests, newModelPermissionRequests(app.Name, model.Name, nil, ReadAction, CreateAction, UpdateAction, DeleteAction)...)
+	}
+	if err := app.Panel.PreloadPermissions(requests, data); err != nil {
+		return nil, err
+	}
+
 	for _, model := range app.ModelsSlice {
 		modelMap := make(map[string]interface{})
 		modelReadAllowed, err := app.Panel.PermissionChecker.HasModelReadPermission(app.Name, model.Name, data)
@@ -154,6 +195,18 @@ func GetModelsWithReadPermissions(app *App, data interface{}) ([]map[string]inte
 // GetAppsWithReadPermissions returns apps for which the user has read permissions.
 func GetAppsWithReadPermissions(panel *AdminPanel, data interface{}) ([]map[string]interface{}, error) {
 	apps := make([]map[string]interface{}, 0)
+
+	requests := make([]PermissionRequest, 0)
+	for _, app := range panel.AppsSlice {
+		appName, action := app.Name, ReadAction
+		requests = append(requests, PermissionRequest{AppName: &appName, Action: &action})
+		for _, model := range app.ModelsSlice {
+			requests = append(requests, newModelPermissionRequests(app.Name, model.Name, nil, ReadAction, CreateAction, UpdateAction, DeleteAction)...)
+		}
+	}
+	if err := panel.PreloadPermissions(requests, data); err != nil {
+		return nil, err
+	}
 	for _, app := range panel.AppsSlice {
 		appMap := make(map[string]interface{})
 		readAllowed, err := panel.PermissionChecker.HasAppReadPermission(app.Name, data)
@@ -0,0 +1,236 @@
+package adminpanel
+
+import (
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/utils"
+	"reflect"
+	"time"
+)
+
+// GetListQuery builds the list query described by the request's search, filter and ordering parameters. The fields
+// needed to filter and order are added to the given fields. Fields the user may not read are neither searched,
+// filtered nor ordered by, so they cannot be probed through the list, and a search without a readable search field is
+// ignored. The raw filter values are returned alongside the query.
+func (m *Model) GetListQuery(data interface{}, fields []string) (InstancesQuery, map[string]string, error) {
+	readableFields, err := m.FilterReadableFields(m.Fields, nil, data)
+	if err != nil {
+		return InstancesQuery{}, nil, err
+	}
+	readableFieldNames := getFieldNames(readableFields)
+
+	filterValues := m.GetFilterValues(data)
+	for field := range filterValues {
+		if !utils.ContainsString(readableFieldNames, field) {
+			delete(filterValues, field)
+		}
+	}
+	query := InstancesQuery{
+		Fields:   fields,
+		Search:   m.App.Panel.Web.GetQueryParam(data, "search"),
+		Filters:  m.GetFilterExpressions(filterValues, time.Now()),
+		Ordering: m.GetOrdering(m.App.Panel.Web.GetQueryParam(data, "ordering"), readableFieldNames),
+	}
+	for _, orderingField := range query.Ordering {
+		if !utils.ContainsString(query.Fields, orderingField.Field) {
+			query.Fields = append(query.Fields, orderingField.Field)
+		}
+	}
+	for _, filter := range query.Filters {
+		if !utils.ContainsString(query.Fields, filter.Field) {
+			query.Fields = append(query.Fields, filter.Field)
+		}
+	}
+	if query.Search != "" {
+		for _, fieldConfig := range readableFields {
+			if fieldConfig.IncludeInSearch {
+				query.SearchFields = append(query.SearchFields, fieldConfig.Name)
+			}
+		}
+		if len(query.SearchFields) == 0 {
+			query.Search = ""
+		}
+	}
+	return query, filterValues, nil
+}
+
+// FetchInstancesPage retrieves the instances matching the query. ORM integrators implementing
+// PaginatedORMIntegrator filter, order and paginate in the database; others fall back to fetching every
+// instance and filtering, ordering and slicing them in memory.
+func (m *Model) FetchInstancesPage(query InstancesQuery) ([]interface{}, error) {
+	return fetchInstancesPage(m.GetORM(), m.PTR, query)
+}
+
+// CountInstances returns the number of instances matching the query, ignoring its offset and limit.
+func (m *Model) CountInstances(query InstancesQuery) (uint, error) {
+	return countInstances(m.GetORM(), m.PTR, query)
+}
+
+// ListBatchSize is the number of instances checked for read permission at once while listing instances.
+const ListBatchSize uint = 500
+
+// FetchReadableInstancesPage retrieves the page of instances matching the query that the user may read, along with
+// the number of readable instances matching it. Instance read permissions are checked in Go rather than in the query,
+// so every match is read, ListBatchSize instances at a time, to keep pages full and the count exact. Restrictions
+// expressed as a query scope are applied by the query itself and keep this scan small.
+func (m *Model) FetchReadableInstancesPage(query InstancesQuery, data interface{}) ([]interface{}, uint, error) {
+	offset, limit := query.Offset, query.Limit
+	page := make([]interface{}, 0)
+	var count uint
+	err := m.ForEachInstanceBatch(query, ListBatchSize, func(instances []interface{}) error {
+		readable, err := filterInstancesByPermission(instances, m, data)
+		if err != nil {
+			return err
+		}
+		for _, instance := range readable {
+			if count >= offset && (limit == 0 || count < offset+limit) {
+				page = append(page, instance)
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return page, count, nil
+}
+
+func fetchInstancesPage(orm ORMIntegrator, model interface{}, query InstancesQuery) ([]interface{}, error) {
+	if paginated, ok := orm.(PaginatedORMIntegrator); ok {
+		instances, err := paginated.FetchInstancesPage(model, query)
+		if err != nil {
+			return nil, err
+		}
+		return instancesToSlice(instances)
+	}
+
+	instances, err := fetchAllInstances(orm, model, query)
+	if err != nil {
+		return nil, err
+	}
+	if err = sortInstances(instances, query.Ordering); err != nil {
+		return nil, err
+	}
+	return pageInstances(instances, query.Offset, query.Limit), nil
+}
+
+func countInstances(orm ORMIntegrator, model interface{}, query InstancesQuery) (uint, error) {
+	if paginated, ok := orm.(PaginatedORMIntegrator); ok {
+		return paginated.CountInstances(model, query)
+	}
+
+	instances, err := fetchAllInstances(orm, model, query)
+	if err != nil {
+		return 0, err
+	}
+	return uint(len(instances)), nil
+}
+
+func fetchAllInstances(orm ORMIntegrator, model interface{}, query InstancesQuery) ([]interface{}, error) {
+	var instances interface{}
+	var err error
+	if query.Search == "" {
+		instances, err = orm.FetchInstancesOnlyFields(model, query.Fields)
+	} else {
+		instances, err = orm.FetchInstancesOnlyFieldWithSearch(model, query.Fields, query.Search, query.SearchFields)
+	}
+	if err != nil {
+		return nil, err
+	}
+	all, err := instancesToSlice(instances)
+	if err != nil {
+		return nil, err
+	}
+	return filterInstances(all, query.Filters)
+}
+
+func instancesToSlice(instances interface{}) ([]interface{}, error) {
+	if instances == nil {
+		return []interface{}{}, nil
+	}
+
+	val := reflect.ValueOf(instances)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return nil, fmt.Errorf("instances must be a slice or array")
+	}
+
+	result := make([]interface{}, 0, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		result = append(result, val.Index(i).Interface())
+	}
+	return result, nil
+}
+
+func pageInstances(instances []interface{}, offset, limit uint) []interface{} {
+	total := uint(len(instances))
+	if offset > total {
+		offset = total
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+	return instances[offset:end]
+}
+
+// ForEachInstance calls fn for every instance matching the query, ignoring its offset and limit. ORM integrators
+// implementing PaginatedORMIntegrator are read in batches of batchSize instances so the matches never have to be
+// held in memory at once.
+func (m *Model) ForEachInstance(query InstancesQuery, batchSize uint, fn func(instance interface{}) error) error {
+	return m.ForEachInstanceBatch(query, batchSize, func(instances []interface{}) error {
+		for _, instance := range instances {
+			if err := fn(instance); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ForEachInstanceBatch calls fn with every batch of instances matching the query, ignoring its offset and limit. ORM
+// integrators implementing PaginatedORMIntegrator are read batchSize instances at a time, ordered by the query's
+// ordering and then by primary key so no instance is skipped or repeated between batches. Other integrators are read
+// at once and passed to fn as a single batch. The batch size must be positive.
+func (m *Model) ForEachInstanceBatch(query InstancesQuery, batchSize uint, fn func(instances []interface{}) error) error {
+	if batchSize == 0 {
+		return fmt.Errorf("batch size must be positive")
+	}
+	if _, ok := m.GetORM().(PaginatedORMIntegrator); !ok {
+		instances, err := fetchAllInstances(m.GetORM(), m.PTR, query)
+		if err != nil {
+			return err
+		}
+		if err = sortInstances(instances, query.Ordering); err != nil {
+			return err
+		}
+		return fn(instances)
+	}
+
+	primaryKeyField, err := m.GetPrimaryKeyField()
+	if err != nil {
+		return err
+	}
+	query.Ordering = append(append([]OrderingField{}, query.Ordering...), OrderingField{Field: primaryKeyField})
+	if !utils.ContainsString(query.Fields, primaryKeyField) {
+		query.Fields = append(append([]string{}, query.Fields...), primaryKeyField)
+	}
+
+	query.Limit = batchSize
+	for offset := uint(0); ; offset += batchSize {
+		query.Offset = offset
+		instances, err := m.FetchInstancesPage(query)
+		if err != nil {
+			return err
+		}
+		if err = fn(instances); err != nil {
+			return err
+		}
+		if uint(len(instances)) < batchSize {
+			return nil
+		}
+	}
+}
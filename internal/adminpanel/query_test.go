@@ -0,0 +1,186 @@
+package adminpanel
+
+import (
+	"testing"
+)
+
+type SliceORMIntegrator struct {
+	MockORMIntegrator
+	Instances []*TestModel
+}
+
+func (o *SliceORMIntegrator) FetchInstancesOnlyFields(interface{}, []string) (interface{}, error) {
+	return o.Instances, nil
+}
+
+func (o *SliceORMIntegrator) GetPrimaryKeyValue(instance interface{}) (interface{}, error) {
+	return instance.(*TestModel).ID, nil
+}
+
+type PaginatedMockORMIntegrator struct {
+	SliceORMIntegrator
+	LastQuery *InstancesQuery
+}
+
+func (o *PaginatedMockORMIntegrator) FetchInstancesPage(_ interface{}, query InstancesQuery) (interface{}, error) {
+	o.LastQuery = &query
+	return o.Instances[:1], nil
+}
+
+func (o *PaginatedMockORMIntegrator) CountInstances(interface{}, InstancesQuery) (uint, error) {
+	return 42, nil
+}
+
+type OffsetORMIntegrator struct {
+	SliceORMIntegrator
+	Queries []InstancesQuery
+}
+
+func (o *OffsetORMIntegrator) FetchInstancesPage(_ interface{}, query InstancesQuery) (interface{}, error) {
+	o.Queries = append(o.Queries, query)
+	instances := make([]interface{}, len(o.Instances))
+	for i, instance := range o.Instances {
+		instances[i] = instance
+	}
+	return pageInstances(instances, query.Offset, query.Limit), nil
+}
+
+func (o *OffsetORMIntegrator) CountInstances(interface{}, InstancesQuery) (uint, error) {
+	return uint(len(o.Instances)), nil
+}
+
+func newTestModelInstances(count int) []*TestModel {
+	instances := make([]*TestModel, count)
+	for i := range instances {
+		instances[i] = &TestModel{ID: uint(i + 1)}
+	}
+	return instances
+}
+
+func TestModel_FetchInstancesPage_Fallback(t *testing.T) {
+	orm := &SliceORMIntegrator{Instances: newTestModelInstances(25)}
+	model := newTestModel(t, &TestModel{}, orm)
+
+	tests := []struct {
+		name        string
+		offset      uint
+		limit       uint
+		expectLen   int
+		expectFirst uint
+	}{
+		{"First page", 0, 10, 10, 1},
+		{"Last partial page", 20, 10, 5, 21},
+		{"Out of range", 100, 10, 0, 0},
+		{"No limit", 5, 0, 20, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := model.FetchInstancesPage(InstancesQuery{Offset: tt.offset, Limit: tt.limit})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(page) != tt.expectLen {
+				t.Fatalf("expected %d instances, got %d", tt.expectLen, len(page))
+			}
+			if tt.expectLen > 0 && page[0].(*TestModel).ID != tt.expectFirst {
+				t.Errorf("expected first ID %d, got %d", tt.expectFirst, page[0].(*TestModel).ID)
+			}
+		})
+	}
+
+	count, err := model.CountInstances(InstancesQuery{Offset: 10, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 25 {
+		t.Errorf("expected count 25, got %d", count)
+	}
+}
+
+func TestModel_FetchInstancesPage_Paginated(t *testing.T) {
+	orm := &PaginatedMockORMIntegrator{SliceORMIntegrator: SliceORMIntegrator{Instances: newTestModelInstances(3)}}
+	model := newTestModel(t, &TestModel{}, orm)
+
+	code, _ := model.GetViewHandler()(map[string]string{"page": "3", "perPage": "15"})
+	if code != 200 {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	if orm.LastQuery == nil {
+		t.Fatalf("expected the paginated ORM integrator to be used")
+	}
+	if orm.LastQuery.Offset != 0 || orm.LastQuery.Limit != ListBatchSize {
+		t.Errorf("expected offset 0 and limit %d, got %d and %d", ListBatchSize, orm.LastQuery.Offset, orm.LastQuery.Limit)
+	}
+
+	count, err := model.CountInstances(InstancesQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 42 {
+		t.Errorf("expected count 42, got %d", count)
+	}
+}
+
+func TestModel_FetchReadableInstancesPage(t *testing.T) {
+	hideEvenIDs := func(request PermissionRequest, _ interface{}) (bool, error) {
+		id, ok := request.InstanceID.(uint)
+		return !ok || id%2 == 1, nil
+	}
+	panel, err := NewAdminPanel(&MockORMIntegrator{}, &MockWebIntegrator{}, hideEvenIDs, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testApp, err := panel.RegisterApp("TestApp", "Test App", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	orm := &OffsetORMIntegrator{SliceORMIntegrator: SliceORMIntegrator{Instances: newTestModelInstances(1200)}}
+	model, err := testApp.RegisterModel(&TestModel{}, orm)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	instances, count, err := model.FetchReadableInstancesPage(InstancesQuery{Offset: 300, Limit: 10}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 600 {
+		t.Errorf("expected 600 readable instances, got %d", count)
+	}
+	if len(instances) != 10 {
+		t.Fatalf("expected a full page of 10 instances, got %d", len(instances))
+	}
+	if first := instances[0].(*TestModel).ID; first != 601 {
+		t.Errorf("expected the page to start at instance 601, got %d", first)
+	}
+}
+
+func TestModel_ForEachInstanceBatch(t *testing.T) {
+	orm := &OffsetORMIntegrator{SliceORMIntegrator: SliceORMIntegrator{Instances: newTestModelInstances(5)}}
+	model := newTestModel(t, &TestModel{}, orm)
+
+	var sizes []int
+	query := InstancesQuery{Fields: []string{"Name"}, Ordering: []OrderingField{{Field: "Name"}}}
+	err := model.ForEachInstanceBatch(query, 2, func(instances []interface{}) error {
+		sizes = append(sizes, len(instances))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[2] != 1 {
+		t.Errorf("expected batches of 2, 2 and 1 instances, got %v", sizes)
+	}
+	last := orm.Queries[len(orm.Queries)-1]
+	if len(last.Ordering) != 2 || last.Ordering[1].Field != "ID" || last.Fields[len(last.Fields)-1] != "ID" {
+		t.Errorf("expected the primary key to break ordering ties, got %+v", last)
+	}
+	if len(query.Ordering) != 1 {
+		t.Errorf("expected the query of the caller to be left unchanged, got %+v", query.Ordering)
+	}
+
+	if err = model.ForEachInstanceBatch(query, 0, func([]interface{}) error { return nil }); err == nil {
+		t.Error("expected an error for a zero batch size")
+	}
+}
@@ -0,0 +1,139 @@
+package adminpanel
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// Response represents a complete HTTP response produced by an admin panel handler.
+type Response struct {
+	// StatusCode is the HTTP status code of the response. It defaults to 200, or 303 for redirects.
+	StatusCode uint
+	// Headers holds additional headers to send with the response.
+	Headers http.Header
+	// ContentType is the value of the Content-Type header.
+	ContentType string
+	// Body holds the response body. It is ignored when BodyWriter is set.
+	Body []byte
+	// BodyWriter streams the response body to the given writer.
+	BodyWriter func(w io.Writer) error
+	// RedirectLocation is the target of a redirect response.
+	RedirectLocation string
+}
+
+// ResponseHandlerFunc represents a handler function returning a full Response.
+type ResponseHandlerFunc = func(interface{}) *Response
+
+// NewHTMLResponse creates a new HTML response with the given status code and content.
+func NewHTMLResponse(code uint, html string) *Response {
+	return &Response{StatusCode: code, ContentType: "text/html; charset=utf-8", Body: []byte(html)}
+}
+
+// NewJSONResponse creates a new JSON response with the given status code and value.
+func NewJSONResponse(code uint, value interface{}) *Response {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return NewResponseFromResult(GetErrorHTML(http.StatusInternalServerError, err))
+	}
+	return &Response{StatusCode: code, ContentType: "application/json", Body: body}
+}
+
+// NewRedirectResponse creates a new 303 See Other response redirecting to the given location.
+func NewRedirectResponse(location string) *Response {
+	return &Response{StatusCode: http.StatusSeeOther, RedirectLocation: location}
+}
+
+// NewStreamResponse creates a new 200 OK response streaming its body through the given writer function.
+func NewStreamResponse(contentType string, writer func(w io.Writer) error) *Response {
+	return &Response{StatusCode: http.StatusOK, ContentType: contentType, BodyWriter: writer}
+}
+
+// NewResponseFromResult converts the result of a HandlerFunc into a Response. Redirection status codes treat the body
+// as the target location.
+func NewResponseFromResult(code uint, body string) *Response {
+	if IsRedirectStatus(code) {
+		return &Response{StatusCode: code, RedirectLocation: body}
+	}
+	return NewHTMLResponse(code, body)
+}
+
+// IsRedirectStatus reports whether the status code is a redirection carrying a location.
+func IsRedirectStatus(code uint) bool {
+	return code >= 300 && code < 400 && code != http.StatusNotModified
+}
+
+// GetStatusCode returns the status code of the response, applying the defaults.
+func (r *Response) GetStatusCode() uint {
+	if r.StatusCode != 0 {
+		return r.StatusCode
+	}
+	if r.RedirectLocation != "" {
+		return http.StatusSeeOther
+	}
+	return http.StatusOK
+}
+
+// Header returns the response headers, creating them when needed.
+func (r *Response) Header() http.Header {
+	if r.Headers == nil {
+		r.Headers = make(http.Header)
+	}
+	return r.Headers
+}
+
+// SetCookie adds a Set-Cookie header to the response.
+func (r *Response) SetCookie(cookie *http.Cookie) {
+	if v := cookie.String(); v != "" {
+		r.Header().Add("Set-Cookie", v)
+	}
+}
+
+// WriteBody writes the response body to the given writer.
+func (r *Response) WriteBody(w io.Writer) error {
+	if r.BodyWriter != nil {
+		return r.BodyWriter(w)
+	}
+	_, err := w.Write(r.Body)
+	return err
+}
+
+// ToResult converts the response into the (status, string) result of a HandlerFunc, buffering streamed bodies.
+// Headers and the content type cannot be represented and are dropped.
+func (r *Response) ToResult() (uint, string) {
+	code := r.GetStatusCode()
+	if r.RedirectLocation != "" {
+		return code, r.RedirectLocation
+	}
+	var buf bytes.Buffer
+	if err := r.WriteBody(&buf); err != nil {
+		return GetErrorHTML(http.StatusInternalServerError, err)
+	}
+	return code, buf.String()
+}
+
+// AdaptResponseHandler wraps a ResponseHandlerFunc into a HandlerFunc for web integrators that do not implement
+// ResponseWebIntegrator.
+func AdaptResponseHandler(handler ResponseHandlerFunc) HandlerFunc {
+	return func(data interface{}) (uint, string) {
+		return handler(data).ToResult()
+	}
+}
+
+// AdaptHandler wraps a HandlerFunc into a ResponseHandlerFunc.
+func AdaptHandler(handler HandlerFunc) ResponseHandlerFunc {
+	return func(data interface{}) *Response {
+		return NewResponseFromResult(handler(data))
+	}
+}
+
+// HandleResponseRoute registers a route returning a full Response, falling back to HandleRoute through
+// AdaptResponseHandler when the web integrator does not implement ResponseWebIntegrator.
+func HandleResponseRoute(web WebIntegrator, method, path string, handler ResponseHandlerFunc) {
+	if responseWeb, ok := web.(ResponseWebIntegrator); ok {
+		responseWeb.HandleResponseRoute(method, path, handler)
+		return
+	}
+	web.HandleRoute(method, path, AdaptResponseHandler(handler))
+}
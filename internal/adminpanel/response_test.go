@@ -0,0 +1,110 @@
+package adminpanel
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type responseWebIntegrator struct {
+	MockWebIntegrator
+	responseRoutes map[string]ResponseHandlerFunc
+}
+
+func (w *responseWebIntegrator) HandleResponseRoute(method, path string, handler ResponseHandlerFunc) {
+	w.responseRoutes[method+" "+path] = handler
+}
+
+type routeRecorderWebIntegrator struct {
+	MockWebIntegrator
+	routes map[string]HandlerFunc
+}
+
+func (w *routeRecorderWebIntegrator) HandleRoute(method, path string, handler HandlerFunc) {
+	w.routes[method+" "+path] = handler
+}
+
+func TestResponse_GetStatusCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *Response
+		expected uint
+	}{
+		{"Default", &Response{}, http.StatusOK},
+		{"Redirect", &Response{RedirectLocation: "/"}, http.StatusSeeOther},
+		{"Explicit", &Response{StatusCode: http.StatusNotFound}, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		if got := tt.response.GetStatusCode(); got != tt.expected {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestResponse_ToResult(t *testing.T) {
+	code, body := NewRedirectResponse("/target").ToResult()
+	if code != http.StatusSeeOther || body != "/target" {
+		t.Errorf("expected redirect to /target, got %d %q", code, body)
+	}
+
+	code, body = NewStreamResponse("text/plain", func(w io.Writer) error {
+		_, err := io.WriteString(w, "streamed")
+		return err
+	}).ToResult()
+	if code != http.StatusOK || body != "streamed" {
+		t.Errorf("expected streamed body, got %d %q", code, body)
+	}
+
+	code, _ = NewStreamResponse("text/plain", func(io.Writer) error {
+		return fmt.Errorf("failed")
+	}).ToResult()
+	if code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", code)
+	}
+}
+
+func TestNewResponseFromResult(t *testing.T) {
+	response := NewResponseFromResult(http.StatusSeeOther, "/target")
+	if response.RedirectLocation != "/target" {
+		t.Errorf("expected redirect location /target, got %q", response.RedirectLocation)
+	}
+
+	response = NewResponseFromResult(http.StatusOK, "<p>ok</p>")
+	if response.RedirectLocation != "" || string(response.Body) != "<p>ok</p>" {
+		t.Errorf("expected an HTML response, got %+v", response)
+	}
+	if !strings.HasPrefix(response.ContentType, "text/html") {
+		t.Errorf("expected an HTML content type, got %q", response.ContentType)
+	}
+}
+
+func TestResponse_SetCookie(t *testing.T) {
+	response := NewHTMLResponse(http.StatusOK, "")
+	response.SetCookie(&http.Cookie{Name: "session", Value: "abc"})
+	if got := response.Header().Get("Set-Cookie"); got != "session=abc" {
+		t.Errorf("expected cookie header, got %q", got)
+	}
+}
+
+func TestHandleResponseRoute(t *testing.T) {
+	handler := func(interface{}) *Response { return NewHTMLResponse(http.StatusCreated, "created") }
+
+	responseWeb := &responseWebIntegrator{responseRoutes: make(map[string]ResponseHandlerFunc)}
+	HandleResponseRoute(responseWeb, "GET", "/path", handler)
+	if _, ok := responseWeb.responseRoutes["GET /path"]; !ok {
+		t.Error("expected the route to be registered as a response route")
+	}
+
+	legacyWeb := &routeRecorderWebIntegrator{routes: make(map[string]HandlerFunc)}
+	HandleResponseRoute(legacyWeb, "GET", "/path", handler)
+	legacyHandler, ok := legacyWeb.routes["GET /path"]
+	if !ok {
+		t.Fatal("expected the route to be adapted to HandleRoute")
+	}
+	if code, body := legacyHandler(nil); code != http.StatusCreated || body != "created" {
+		t.Errorf("expected adapted result, got %d %q", code, body)
+	}
+}
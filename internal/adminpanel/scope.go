@@ -0,0 +1,168 @@
+package adminpanel
+
+import (
+	"github.com/go-advanced-admin/admin/internal/utils"
+)
+
+// QueryScopeFunc returns the constraints limiting the instances of the model the user may see and modify, for example
+// restricting a multi-tenant table to the rows of the user's organization.
+type QueryScopeFunc = func(model *Model, ctx interface{}) ([]FilterExpression, error)
+
+// AdminQueryScopeInterface allows a model to limit the instances the user may see and modify.
+type AdminQueryScopeInterface interface {
+	// AdminQueryScope returns the constraints every instance visible to the user in the given context must satisfy.
+	AdminQueryScope(ctx interface{}) ([]FilterExpression, error)
+}
+
+// ScopedORMIntegrator is an optional interface ORM integrators can implement to apply query scopes to single
+// instance operations in the database. Others fall back to fetching the instance and checking the scope in memory
+// before updating or deleting it. List queries carry the scope in their filters.
+type ScopedORMIntegrator interface {
+	// FetchInstanceInScope retrieves the instance with the given primary key and only the specified fields if it
+	// satisfies the scope. It returns nil when no such instance exists.
+	FetchInstanceInScope(model interface{}, id interface{}, fields []string, scope []FilterExpression) (interface{}, error)
+
+	// UpdateInstanceInScope updates the specified fields of the instance with the given primary key if it satisfies
+	// the scope. It reports whether such an instance was found.
+	UpdateInstanceInScope(instance interface{}, fields []string, primaryKey interface{}, scope []FilterExpression) (bool, error)
+
+	// DeleteInstanceInScope deletes the instance with the given primary key if it satisfies the scope. It reports
+	// whether such an instance was found.
+	DeleteInstanceInScope(model interface{}, id interface{}, scope []FilterExpression) (bool, error)
+}
+
+// GetQueryScope returns the constraints limiting the instances the user may see and modify, combining the panel's
+// QueryScope function and the model's AdminQueryScope method.
+func (m *Model) GetQueryScope(data interface{}) ([]FilterExpression, error) {
+	scope := make([]FilterExpression, 0)
+	if m.App.Panel.Config.QueryScope != nil {
+		panelScope, err := m.App.Panel.Config.QueryScope(m, data)
+		if err != nil {
+			return nil, err
+		}
+		scope = append(scope, panelScope...)
+	}
+	if scoped, ok := m.PTR.(AdminQueryScopeInterface); ok {
+		modelScope, err := scoped.AdminQueryScope(data)
+		if err != nil {
+			return nil, err
+		}
+		scope = append(scope, modelScope...)
+	}
+	return scope, nil
+}
+
+// GetScopedListQuery builds the list query described by the request like GetListQuery, adding the user's query scope
+// to its filters so instances outside of it are neither fetched nor counted.
+func (m *Model) GetScopedListQuery(data interface{}, fields []string) (InstancesQuery, map[string]string, error) {
+	query, filterValues, err := m.GetListQuery(data, fields)
+	if err != nil {
+		return query, filterValues, err
+	}
+	scope, err := m.GetQueryScope(data)
+	if err != nil {
+		return query, filterValues, err
+	}
+	query.Filters = append(query.Filters, scope...)
+	for _, filter := range scope {
+		if !utils.ContainsString(query.Fields, filter.Field) {
+			query.Fields = append(query.Fields, filter.Field)
+		}
+	}
+	return query, filterValues, nil
+}
+
+// FetchInstanceInScope retrieves the instance with the given primary key and only the specified fields, or every
+// field when fields is nil. It returns nil when the instance does not exist or does not satisfy the scope.
+func (m *Model) FetchInstanceInScope(id interface{}, fields []string, scope []FilterExpression) (interface{}, error) {
+	if len(scope) == 0 {
+		if fields == nil {
+			return m.GetORM().FetchInstance(m.PTR, id)
+		}
+		return m.GetORM().FetchInstanceOnlyFields(m.PTR, id, fields)
+	}
+	if orm, ok := m.GetORM().(ScopedORMIntegrator); ok {
+		return orm.FetchInstanceInScope(m.PTR, id, fields, scope)
+	}
+
+	var instance interface{}
+	var err error
+	if fields == nil {
+		instance, err = m.GetORM().FetchInstance(m.PTR, id)
+	} else {
+		fetchFields := append([]string(nil), fields...)
+		for _, filter := range scope {
+			if !utils.ContainsString(fetchFields, filter.Field) {
+				fetchFields = append(fetchFields, filter.Field)
+			}
+		}
+		instance, err = m.GetORM().FetchInstanceOnlyFields(m.PTR, id, fetchFields)
+	}
+	if err != nil || isNilInstance(instance) {
+		return nil, err
+	}
+	matches, err := filterInstances([]interface{}{instance}, scope)
+	if err != nil || len(matches) == 0 {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// UpdateInstanceInScope updates the specified fields of the instance with the given primary key. It returns
+// ErrOutOfScope when the instance does not satisfy the scope, or would no longer satisfy it after the update.
+func (m *Model) UpdateInstanceInScope(instance interface{}, fields []string, primaryKey interface{}, scope []FilterExpression) error {
+	if len(scope) == 0 {
+		return m.GetORM().UpdateInstanceOnlyFields(instance, fields, primaryKey)
+	}
+	for _, filter := range scope {
+		if !utils.ContainsString(fields, filter.Field) {
+			continue
+		}
+		matches, err := filter.Matches(instance)
+		if err != nil {
+			return err
+		}
+		if !matches {
+			return ErrOutOfScope
+		}
+	}
+
+	if orm, ok := m.GetORM().(ScopedORMIntegrator); ok {
+		found, err := orm.UpdateInstanceInScope(instance, fields, primaryKey, scope)
+		if err == nil && !found {
+			return ErrOutOfScope
+		}
+		return err
+	}
+	existing, err := m.FetchInstanceInScope(primaryKey, []string{}, scope)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrOutOfScope
+	}
+	return m.GetORM().UpdateInstanceOnlyFields(instance, fields, primaryKey)
+}
+
+// DeleteInstanceInScope deletes the instance with the given primary key. It returns ErrOutOfScope when the instance
+// does not satisfy the scope.
+func (m *Model) DeleteInstanceInScope(id interface{}, scope []FilterExpression) error {
+	if len(scope) == 0 {
+		return m.GetORM().DeleteInstance(m.PTR, id)
+	}
+	if orm, ok := m.GetORM().(ScopedORMIntegrator); ok {
+		found, err := orm.DeleteInstanceInScope(m.PTR, id, scope)
+		if err == nil && !found {
+			return ErrOutOfScope
+		}
+		return err
+	}
+	existing, err := m.FetchInstanceInScope(id, []string{}, scope)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrOutOfScope
+	}
+	return m.GetORM().DeleteInstance(m.PTR, id)
+}
@@ -0,0 +1,162 @@
+package adminpanel
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type ScopedTestModel struct {
+	ID    uint
+	Owner string
+}
+
+func (m *ScopedTestModel) AdminQueryScope(ctx interface{}) ([]FilterExpression, error) {
+	if ctx == nil {
+		return nil, errors.New("no user")
+	}
+	return []FilterExpression{{Field: "Owner", Operator: FilterEqual, Value: ctx}}, nil
+}
+
+// adultsScope limits every model to the instances with an Age of at least 18.
+func adultsScope(*Model, interface{}) ([]FilterExpression, error) {
+	return []FilterExpression{{Field: "Age", Operator: FilterGreaterThanOrEqual, Value: 18}}, nil
+}
+
+// scopeTestConfig serves the JSON API and limits every model to adults.
+func scopeTestConfig(config *AdminConfig) {
+	config.APIPrefix = "api"
+	config.QueryScope = adultsScope
+}
+
+// newScopeTestChild returns an instance hidden by adultsScope.
+func newScopeTestChild() *ImportTestModel {
+	return &ImportTestModel{ID: 2, Name: "Bobby", Age: 12}
+}
+
+func TestModel_GetQueryScope(t *testing.T) {
+	panel, err := NewMockAdminPanel()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	panel.Config.QueryScope = adultsScope
+	app, _ := panel.RegisterApp("TestApp", "Test App", nil)
+	model, err := app.RegisterModel(&ScopedTestModel{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	scope, err := model.GetQueryScope("alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(scope) != 2 || scope[0].Field != "Age" || scope[1].Field != "Owner" || scope[1].Value != "alice" {
+		t.Errorf("expected the panel and model scopes, got %+v", scope)
+	}
+
+	if _, err = model.GetQueryScope(nil); err == nil {
+		t.Error("expected the model scope error")
+	}
+}
+
+func TestModel_InstanceInScope(t *testing.T) {
+	_, model, orm := newHTTPTestPanel(t, MockPermissionFunc, scopeTestConfig, newScopeTestChild())
+	scope, _ := model.GetQueryScope(nil)
+
+	instance, err := model.FetchInstanceInScope(uint(1), []string{"Name"}, scope)
+	if err != nil || instance == nil {
+		t.Errorf("expected instance 1 to be in scope, got %v, %v", instance, err)
+	}
+	instance, err = model.FetchInstanceInScope(uint(2), []string{"Name"}, scope)
+	if err != nil || instance != nil {
+		t.Errorf("expected instance 2 to be out of scope, got %v, %v", instance, err)
+	}
+
+	err = model.UpdateInstanceInScope(&ImportTestModel{Name: "Mallory"}, []string{"Name"}, uint(2), scope)
+	if !errors.Is(err, ErrOutOfScope) {
+		t.Errorf("expected ErrOutOfScope, got %v", err)
+	}
+	err = model.UpdateInstanceInScope(&ImportTestModel{Age: 5}, []string{"Age"}, uint(1), scope)
+	if !errors.Is(err, ErrOutOfScope) {
+		t.Errorf("expected ErrOutOfScope when leaving the scope, got %v", err)
+	}
+	if err = model.DeleteInstanceInScope(uint(2), scope); !errors.Is(err, ErrOutOfScope) {
+		t.Errorf("expected ErrOutOfScope, got %v", err)
+	}
+	if orm.Existing[2].Name != "Bobby" || orm.Existing[1].Age != 30 {
+		t.Errorf("out of scope changes should not be saved, got %+v, %+v", orm.Existing[1], orm.Existing[2])
+	}
+
+	if err = model.DeleteInstanceInScope(uint(1), scope); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, ok := orm.Existing[1]; ok {
+		t.Error("expected instance 1 to be deleted")
+	}
+}
+
+func TestQueryScope_Views(t *testing.T) {
+	web, model, orm := newHTTPTestPanel(t, MockPermissionFunc, scopeTestConfig, newScopeTestChild())
+	apiLink := model.App.Panel.Config.GetLink(model.GetAPIInstanceLink(uint(2)))
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		code        int
+		notContains string
+	}{
+		{"List", http.MethodGet, model.GetFullLink(), "", http.StatusOK, "Bobby"},
+		{"Export", http.MethodGet, model.GetFullExportLink() + "?format=csv", "", http.StatusOK, "Bobby"},
+		{"Instance", http.MethodGet, model.GetFullLink() + "/2/view", "", http.StatusNotFound, "Bobby"},
+		{"Edit", http.MethodGet, model.GetFullLink() + "/2/edit", "", http.StatusNotFound, "Bobby"},
+		{"Delete", http.MethodDelete, model.GetFullLink() + "/2/view", "", http.StatusNotFound, ""},
+		{"APIRetrieve", http.MethodGet, apiLink, "", http.StatusNotFound, "Bobby"},
+		{"APIUpdate", http.MethodPatch, apiLink, `{"Name":"Mallory"}`, http.StatusNotFound, ""},
+		{"APIDelete", http.MethodDelete, apiLink, "", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			web.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body)))
+			if rec.Code != tt.code {
+				t.Fatalf("expected status %d, got %d: %s", tt.code, rec.Code, rec.Body.String())
+			}
+			if tt.code == http.StatusNotFound && !strings.Contains(rec.Body.String(), ErrOutOfScope.Error()) {
+				t.Errorf("expected an out of scope error, got %s", rec.Body.String())
+			}
+			if tt.notContains != "" && strings.Contains(rec.Body.String(), tt.notContains) {
+				t.Errorf("expected body not to contain %q, got %s", tt.notContains, rec.Body.String())
+			}
+		})
+	}
+
+	if instance, ok := orm.Existing[2]; !ok || instance.Name != "Bobby" {
+		t.Errorf("expected instance 2 to be untouched, got %+v", instance)
+	}
+}
+
+func TestQueryScope_BulkDelete(t *testing.T) {
+	web, model, orm := newHTTPTestPanel(t, MockPermissionFunc, scopeTestConfig, newScopeTestChild())
+
+	values := url.Values{"action": {DeleteSelectedActionName}, "ids": {"1", "2"}, "confirm": {"yes"}}
+	req := httptest.NewRequest(http.MethodPost, model.GetFullLink()+"/action", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	web.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), ErrOutOfScope.Error()) {
+		t.Errorf("expected an out of scope error, got %s", rec.Body.String())
+	}
+	if len(orm.Existing) != 2 {
+		t.Errorf("expected no instance to be deleted, got %d left", len(orm.Existing))
+	}
+}
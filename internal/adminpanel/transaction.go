@@ -0,0 +1,189 @@
+package adminpanel
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/form"
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"reflect"
+)
+
+// TransactionalORMIntegrator is an optional interface ORM integrators can implement to run the admin panel's mutations
+// in database transactions, so a failure halfway leaves no partial data.
+type TransactionalORMIntegrator interface {
+	// WithTransaction runs fn in a transaction with an integrator bound to it. The transaction is committed when fn
+	// returns nil and rolled back otherwise.
+	WithTransaction(ctx interface{}, fn func(tx ORMIntegrator) error) error
+}
+
+// TransactionalLogStore is an optional interface log stores can implement to insert the log entry of a mutation in
+// the same transaction, so the mutation and its audit entry are written atomically.
+type TransactionalLogStore interface {
+	// InTransaction returns a store inserting entries through tx, a transaction started from orm, or nil when the
+	// store does not use the database of orm.
+	InTransaction(orm ORMIntegrator, tx ORMIntegrator) logging.LogStore
+}
+
+// transaction is the state of the transaction running for a request. Its entries are inserted through logStore and
+// streamed once the transaction commits. When no store takes part in the transaction, they are inserted at the end of
+// the transaction under the LogFailClosed policy, and once it commits under the other policies.
+type transaction struct {
+	tx       ORMIntegrator
+	logStore logging.LogStore
+	entries  []*logging.LogEntry
+	inserted bool
+}
+
+func (ap *AdminPanel) getTransaction(ctx interface{}) *transaction {
+	if ap.transactions == nil || !isCacheableContext(ctx) {
+		return nil
+	}
+	current, ok := ap.transactions.Load(ctx)
+	if !ok {
+		return nil
+	}
+	return current.(*transaction)
+}
+
+// GetTransaction returns the ORM integrator bound to the transaction running for the request, or nil when none runs.
+// Custom actions can use it to take part in the transaction of the admin panel.
+func (ap *AdminPanel) GetTransaction(ctx interface{}) ORMIntegrator {
+	if current := ap.getTransaction(ctx); current != nil {
+		return current.tx
+	}
+	return nil
+}
+
+// CreateLog creates a log entry like AdminConfig.CreateLog. While a transaction runs for the request, the entry is
+// inserted in it when the log store implements TransactionalLogStore, and it is streamed once the transaction commits.
+// Other log stores cannot take part in the transaction, so the entry is only inserted once the mutation succeeded and
+// never recorded when it rolls back; the mutation and its entry are then not written atomically.
+func (ap *AdminPanel) CreateLog(ctx interface{}, action logging.LogStoreLevel, contentType string, objectID interface{}, objectRepr string, message string) error {
+	current := ap.getTransaction(ctx)
+	if current == nil {
+		return ap.Config.CreateLog(ctx, action, contentType, objectID, objectRepr, message)
+	}
+	entry, err := ap.Config.newLogEntry(ctx, action, contentType, objectID, objectRepr, message)
+	if entry == nil || err != nil {
+		return err
+	}
+	if current.logStore == nil {
+		current.entries = append(current.entries, entry)
+		return nil
+	}
+	status, err := ap.Config.insertLogEntry(context.WithoutCancel(ap.Config.getContext(ctx)), current.logStore, entry)
+	if status == logRecorded {
+		current.entries = append(current.entries, entry)
+	}
+	return err
+}
+
+// WithTransaction runs fn with a copy of the model bound to a transaction when its ORM integrator implements
+// TransactionalORMIntegrator, and with the model itself otherwise. Calls made for a request while its transaction runs
+// join that transaction. Log entries of a log store outside the transaction are inserted once fn succeeds: under the
+// LogFailClosed policy before the commit, so a failure to insert them rolls the mutation back, and under the other
+// policies, which never fail the request on a store error, after the commit so a rolled back mutation leaves no entry.
+func (m *Model) WithTransaction(ctx interface{}, fn func(tm *Model) error) error {
+	panel := m.App.Panel
+	if current := panel.getTransaction(ctx); current != nil {
+		return fn(m.boundTo(current.tx))
+	}
+	orm, ok := m.GetORM().(TransactionalORMIntegrator)
+	if !ok {
+		return fn(m)
+	}
+
+	current := &transaction{}
+	err := orm.WithTransaction(ctx, func(tx ORMIntegrator) error {
+		current.tx = tx
+		current.entries = nil
+		if store, ok := panel.Config.LogStore.(TransactionalLogStore); ok {
+			current.logStore = store.InTransaction(m.getORM(), tx)
+		}
+		current.inserted = current.logStore != nil
+		if panel.transactions != nil && isCacheableContext(ctx) {
+			panel.transactions.Store(ctx, current)
+			defer panel.transactions.Delete(ctx)
+		}
+		if err := fn(m.boundTo(tx)); err != nil {
+			return err
+		}
+		if current.inserted || panel.Config.LogFailurePolicy != LogFailClosed {
+			return nil
+		}
+		for _, entry := range current.entries {
+			if _, err := panel.Config.insertLogEntry(context.WithoutCancel(panel.Config.getContext(ctx)), nil, entry); err != nil {
+				return err
+			}
+		}
+		current.inserted = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var logErr error
+	for _, entry := range current.entries {
+		if !current.inserted {
+			status, err := panel.Config.insertLogEntry(context.WithoutCancel(panel.Config.getContext(ctx)), nil, entry)
+			if err != nil && logErr == nil {
+				logErr = err
+			}
+			if status != logRecorded {
+				continue
+			}
+		}
+		panel.Config.streamLogEntry(entry)
+	}
+	return logErr
+}
+
+func (m *Model) boundTo(tx ORMIntegrator) *Model {
+	bound := *m
+	bound.ORM = tx
+	return &bound
+}
+
+// bindForm points the model forms at tm, so saving them runs in the transaction tm is bound to.
+func bindForm(f form.Form, tm *Model) {
+	switch f := f.(type) {
+	case *ModelAddForm:
+		f.Model = tm
+	case *ModelEditForm:
+		f.Model = tm
+	}
+}
+
+// checkTransactionalLogStore rejects a ChainedLogStore wrapping a TransactionalLogStore when the ORM integrator runs
+// transactions. Chaining an entry in a transaction that may roll back would break the chain, so the chained store
+// cannot take part in transactions and its entries would silently lose the atomicity the wrapped store provides.
+func checkTransactionalLogStore(orm ORMIntegrator, store logging.LogStore) error {
+	chained, ok := store.(*logging.ChainedLogStore)
+	if !ok {
+		return nil
+	}
+	if _, ok = chained.Store.(TransactionalLogStore); !ok {
+		return nil
+	}
+	if _, ok = orm.(TransactionalORMIntegrator); !ok {
+		return nil
+	}
+	return fmt.Errorf("a ChainedLogStore cannot insert entries in the transactions of the ORM integrator, so it cannot wrap a TransactionalLogStore")
+}
+
+// InTransaction returns a store inserting entries through tx when the store uses orm, so log entries are written in
+// the transaction of the mutation they record. Retention is left to the store itself.
+func (s *ORMLogStore) InTransaction(orm ORMIntegrator, tx ORMIntegrator) logging.LogStore {
+	if !sameORMIntegrator(s.ORM, orm) {
+		return nil
+	}
+	return &ORMLogStore{ORM: tx}
+}
+
+func sameORMIntegrator(a, b ORMIntegrator) bool {
+	if a == nil || b == nil || reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
@@ -0,0 +1,276 @@
+package adminpanel
+
+import (
+	"bytes"
+	"errors"
+	"github.com/go-advanced-admin/admin/internal/logging"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type TransactionalImportORMIntegrator struct {
+	*ImportORMIntegrator
+	FailName  string
+	Commits   int
+	Rollbacks int
+}
+
+func (o *TransactionalImportORMIntegrator) CreateInstanceOnlyFields(instance interface{}, fields []string) error {
+	if instance.(*ImportTestModel).Name == o.FailName {
+		return errors.New("insert failed")
+	}
+	return o.ImportORMIntegrator.CreateInstanceOnlyFields(instance, fields)
+}
+
+func (o *TransactionalImportORMIntegrator) WithTransaction(_ interface{}, fn func(tx ORMIntegrator) error) error {
+	existing := make(map[uint]*ImportTestModel, len(o.Existing))
+	for id, instance := range o.Existing {
+		existing[id] = instance
+	}
+	updated := make(map[uint]*ImportTestModel, len(o.Updated))
+	for id, instance := range o.Updated {
+		updated[id] = instance
+	}
+	created := len(o.Created)
+
+	if err := fn(o); err != nil {
+		o.Existing, o.Updated, o.Created = existing, updated, o.Created[:created]
+		o.Rollbacks++
+		return err
+	}
+	o.Commits++
+	return nil
+}
+
+func newTransactionTestModel(t *testing.T) (*Model, *TransactionalImportORMIntegrator) {
+	orm := newImportTestORM()
+	model := newTestModel(t, &ImportTestModel{}, orm)
+	txORM := &TransactionalImportORMIntegrator{ImportORMIntegrator: orm}
+	model.ORM = txORM
+	return model, txORM
+}
+
+func prepareTransactionTestImport(t *testing.T, model *Model, content string) *ImportPreview {
+	records, err := model.ParseImportRecords(ExportFormatCSV, []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	preview, err := model.PrepareImport(records, nil)
+	if err != nil || preview.HasErrors() {
+		t.Fatalf("unexpected errors: %v, %+v", err, preview)
+	}
+	return preview
+}
+
+func TestModel_WithTransaction_Commits(t *testing.T) {
+	model, orm := newTransactionTestModel(t)
+	var buffer bytes.Buffer
+	model.App.Panel.Config.LogStore = logging.NewInMemoryLogStore(10)
+	model.App.Panel.Config.LogStreamer = logging.NewLogStreamer(logging.LogStreamerOptions{}, logging.NewWriterSink(&buffer))
+
+	preview := prepareTransactionTestImport(t, model, "ID,Name,Age\n1,Alicia,\n,Bob,20\n")
+	if _, _, err := model.ApplyImport(preview, new(int)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = model.App.Panel.Config.LogStreamer.Close()
+
+	if orm.Commits != 1 || orm.Rollbacks != 0 {
+		t.Errorf("expected the import to commit a single transaction, got %d commits and %d rollbacks", orm.Commits, orm.Rollbacks)
+	}
+	if lines := strings.Count(buffer.String(), "\n"); lines != 2 {
+		t.Errorf("expected both entries to be streamed after the commit, got %q", buffer.String())
+	}
+}
+
+func TestModel_WithTransaction_RollsBack(t *testing.T) {
+	model, orm := newTransactionTestModel(t)
+	orm.FailName = "Carol"
+	var buffer bytes.Buffer
+	model.App.Panel.Config.LogStore = logging.NewInMemoryLogStore(10)
+	model.App.Panel.Config.LogStreamer = logging.NewLogStreamer(logging.LogStreamerOptions{}, logging.NewWriterSink(&buffer))
+
+	preview := prepareTransactionTestImport(t, model, "ID,Name,Age\n1,Alicia,\n,Bob,20\n,Carol,25\n")
+	if _, _, err := model.ApplyImport(preview, new(int)); err == nil {
+		t.Fatal("expected the failing row to fail the import")
+	}
+	_ = model.App.Panel.Config.LogStreamer.Close()
+
+	if orm.Rollbacks != 1 || len(orm.Created) != 0 || orm.Existing[1].Name != "Alice" {
+		t.Errorf("expected the earlier rows to be rolled back, got %d rollbacks, %v created and %v", orm.Rollbacks, orm.Created, orm.Existing[1])
+	}
+	if buffer.Len() != 0 {
+		t.Errorf("expected no entries of a rolled back transaction to be streamed, got %q", buffer.String())
+	}
+	if entries, _ := model.App.Panel.Config.LogStore.GetLogEntries(); len(entries) != 0 {
+		t.Errorf("expected no entries of a rolled back transaction to be inserted, got %v", entries)
+	}
+}
+
+func TestModel_WithTransaction_LogFailClosed(t *testing.T) {
+	model, orm := newTransactionTestModel(t)
+	model.App.Panel.Config.LogStore = &failingLogStore{InMemoryLogStore: logging.NewInMemoryLogStore(10), failing: true}
+	model.App.Panel.Config.LogFailurePolicy = LogFailClosed
+
+	preview := prepareTransactionTestImport(t, model, "ID,Name,Age\n1,Alicia,\n")
+	if _, _, err := model.ApplyImport(preview, nil); err == nil {
+		t.Fatal("expected the log failure to fail the import")
+	}
+	if orm.Rollbacks != 1 || orm.Existing[1].Name != "Alice" {
+		t.Errorf("expected the update to be rolled back with its log entry, got %d rollbacks and %v", orm.Rollbacks, orm.Existing[1])
+	}
+}
+
+func TestModel_WithTransaction_LogAfterCommit(t *testing.T) {
+	model, orm := newTransactionTestModel(t)
+	store := &failingLogStore{InMemoryLogStore: logging.NewInMemoryLogStore(10)}
+	model.App.Panel.Config.LogStore = store
+	var logErr error
+	model.App.Panel.Config.OnLogError = func(_ *logging.LogEntry, err error) { logErr = err }
+	ctx := new(int)
+
+	err := model.WithTransaction(ctx, func(tm *Model) error {
+		if err := model.App.Panel.CreateLog(ctx, logging.LogStoreLevelUpdate, tm.GetLogContentType(), uint(1), "", ""); err != nil {
+			return err
+		}
+		if entries, _ := store.GetLogEntries(); len(entries) != 0 {
+			t.Errorf("expected the entry to wait for the commit, got %v", entries)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries, _ := store.GetLogEntries(); len(entries) != 1 {
+		t.Errorf("expected the entry to be inserted after the commit, got %v", entries)
+	}
+
+	store.failing = true
+	err = model.WithTransaction(ctx, func(tm *Model) error {
+		return model.App.Panel.CreateLog(ctx, logging.LogStoreLevelUpdate, tm.GetLogContentType(), uint(1), "", "")
+	})
+	if err != nil || logErr == nil || orm.Commits != 2 {
+		t.Errorf("expected the log failure to be reported to the hook after the commit, got %v, %v and %d commits", err, logErr, orm.Commits)
+	}
+}
+
+func TestModel_WithTransaction_LogFailClosedOutsideStore(t *testing.T) {
+	model, orm := newTransactionTestModel(t)
+	store := &failingLogStore{InMemoryLogStore: logging.NewInMemoryLogStore(10), failing: true}
+	model.App.Panel.Config.LogStore = store
+	model.App.Panel.Config.LogFailurePolicy = LogFailClosed
+	ctx := new(int)
+
+	err := model.WithTransaction(ctx, func(tm *Model) error {
+		if err := tm.GetORM().UpdateInstanceOnlyFields(&ImportTestModel{Name: "Alicia"}, []string{"Name"}, uint(1)); err != nil {
+			return err
+		}
+		return model.App.Panel.CreateLog(ctx, logging.LogStoreLevelUpdate, tm.GetLogContentType(), uint(1), "", "")
+	})
+	if err == nil {
+		t.Fatal("expected the log failure to fail the mutation")
+	}
+	if orm.Commits != 0 || orm.Rollbacks != 1 || orm.Existing[1].Name != "Alice" {
+		t.Errorf("expected the update to be rolled back, got %d commits, %d rollbacks and %v", orm.Commits, orm.Rollbacks, orm.Existing[1])
+	}
+
+	store.failing = false
+	err = model.WithTransaction(ctx, func(tm *Model) error {
+		return model.App.Panel.CreateLog(ctx, logging.LogStoreLevelUpdate, tm.GetLogContentType(), uint(1), "", "")
+	})
+	if entries, _ := store.GetLogEntries(); err != nil || len(entries) != 1 || orm.Commits != 1 {
+		t.Errorf("expected the entry to be inserted once, got %v, %v and %d commits", err, entries, orm.Commits)
+	}
+}
+
+func TestNewAdminPanel_ChainedTransactionalLogStore(t *testing.T) {
+	orm := &TransactionalImportORMIntegrator{ImportORMIntegrator: &ImportORMIntegrator{}}
+	chained, err := logging.NewChainedLogStore(NewORMLogStore(orm, logging.RetentionPolicy{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config := NewDefaultAdminConfig()
+	config.LogStore = chained
+	if _, err = NewAdminPanel(orm, &MockWebIntegrator{}, MockPermissionFunc, config); err == nil {
+		t.Error("expected a chained store to be rejected in transactions")
+	}
+
+	config.LogStore, _ = logging.NewChainedLogStore(logging.NewInMemoryLogStore(10))
+	if _, err = NewAdminPanel(orm, &MockWebIntegrator{}, MockPermissionFunc, config); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestModel_WithTransaction_Nested(t *testing.T) {
+	model, orm := newTransactionTestModel(t)
+	ctx := new(int)
+
+	err := model.WithTransaction(ctx, func(tm *Model) error {
+		if model.App.Panel.GetTransaction(ctx) != orm || tm.GetORM() != orm {
+			t.Error("expected the transaction to be available for the request")
+		}
+		return model.WithTransaction(ctx, func(*Model) error { return nil })
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if orm.Commits != 1 || model.App.Panel.GetTransaction(ctx) != nil {
+		t.Errorf("expected the nested call to join the transaction, got %d commits", orm.Commits)
+	}
+}
+
+func TestORMLogStore_InTransaction(t *testing.T) {
+	orm := &ImportORMIntegrator{}
+	tx := &ImportORMIntegrator{}
+	store := NewORMLogStore(orm, logging.RetentionPolicy{})
+
+	if bound, ok := store.InTransaction(orm, tx).(*ORMLogStore); !ok || bound.ORM != tx {
+		t.Errorf("expected a store bound to the transaction, got %v", bound)
+	}
+	if bound := store.InTransaction(&ImportORMIntegrator{}, tx); bound != nil {
+		t.Errorf("expected no store for another database, got %v", bound)
+	}
+}
+
+func TestModel_GetActionHandler_Transaction(t *testing.T) {
+	model, orm := newTransactionTestModel(t)
+	code, _ := model.GetActionHandler()(map[string][]string{"action": {DeleteSelectedActionName}, "ids": {"1"}, "confirm": {"yes"}})
+	if code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d", code)
+	}
+	if orm.Commits != 1 || len(orm.Existing) != 0 {
+		t.Errorf("expected the deletion to commit a single transaction, got %d commits and %v", orm.Commits, orm.Existing)
+	}
+
+	model, orm = newTransactionTestModel(t)
+	_ = model.RegisterAction("rename", "Rename", func(tm *Model, _ interface{}, ids []interface{}) error {
+		if err := tm.GetORM().UpdateInstanceOnlyFields(&ImportTestModel{Name: "Renamed"}, []string{"Name"}, ids[0]); err != nil {
+			return err
+		}
+		return errors.New("failed")
+	})
+	code, _ = model.GetActionHandler()(map[string][]string{"action": {"rename"}, "ids": {"1"}, "confirm": {"yes"}})
+	if code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", code)
+	}
+	if orm.Rollbacks != 1 || orm.Existing[1].Name != "Alice" {
+		t.Errorf("expected the writes of the action to be rolled back, got %d rollbacks and %v", orm.Rollbacks, orm.Existing[1])
+	}
+}
+
+func TestModel_GetInstanceActionHandler_Transaction(t *testing.T) {
+	model, orm := newTransactionTestModel(t)
+	_ = model.RegisterInstanceAction("rename", "Rename", func(tm *Model, _ interface{}, id interface{}) (string, error) {
+		if err := tm.GetORM().UpdateInstanceOnlyFields(&ImportTestModel{Name: "Renamed"}, []string{"Name"}, id); err != nil {
+			return "", err
+		}
+		return "", errors.New("failed")
+	})
+	code, _ := model.GetInstanceActionHandler()(map[string]string{"id": "1", "name": "rename"})
+	if code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", code)
+	}
+	if orm.Rollbacks != 1 || orm.Existing[1].Name != "Alice" {
+		t.Errorf("expected the writes of the action to be rolled back, got %d rollbacks and %v", orm.Rollbacks, orm.Existing[1])
+	}
+}
@@ -1,5 +1,7 @@
 package adminpanel
 
+import "context"
+
 // HandlerFunc represents a handler function used in the admin panel routes.
 type HandlerFunc = func(interface{}) (uint, string)
 
@@ -23,3 +25,34 @@ type WebIntegrator interface {
 	// GetFormData retrieves form data from the context.
 	GetFormData(ctx interface{}) map[string][]string
 }
+
+// MultipartWebIntegrator is an optional interface web integrators can implement to give handlers access to files
+// uploaded through multipart forms.
+type MultipartWebIntegrator interface {
+	// GetFormFile retrieves the name and content of the uploaded file with the given form field name. It returns an
+	// empty name and nil content when no file was uploaded.
+	GetFormFile(ctx interface{}, name string) (fileName string, content []byte, err error)
+}
+
+// ResponseWebIntegrator is an optional interface web integrators can implement to write full responses, including
+// headers, content types and streamed bodies. Routes returning a Response on integrators that do not implement it are
+// adapted to HandleRoute, buffering the body.
+type ResponseWebIntegrator interface {
+	// HandleResponseRoute registers a route with the given method, path, and handler function returning a Response.
+	HandleResponseRoute(method, path string, handler ResponseHandlerFunc)
+}
+
+// RequestBodyWebIntegrator is an optional interface web integrators can implement to give handlers access to the raw
+// request body, used by the JSON API. The API falls back to form data on integrators that do not implement it.
+type RequestBodyWebIntegrator interface {
+	// GetRequestBody retrieves the raw body of the request from the context.
+	GetRequestBody(ctx interface{}) ([]byte, error)
+}
+
+// ContextWebIntegrator is an optional interface web integrators can implement to provide the context.Context of a
+// request, so ORM queries, permission checks and log entries are cancelled with the request and carry its deadline and
+// tracing spans. Handlers of integrators that do not implement it run with context.Background().
+type ContextWebIntegrator interface {
+	// GetContext retrieves the context.Context of the request from the context.
+	GetContext(ctx interface{}) context.Context
+}
@@ -0,0 +1,270 @@
+package adminpanel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// HTTPMaxMultipartMemory is the maximum number of bytes of a multipart form kept in memory by HTTPWebIntegrator. The
+// rest is stored in temporary files.
+const HTTPMaxMultipartMemory = 32 << 20
+
+// HTTPDefaultMaxBodySize is the default maximum number of bytes of a request body read by HTTPWebIntegrator.
+const HTTPDefaultMaxBodySize = 64 << 20
+
+// HTTPContext is the context passed to handlers registered through HTTPWebIntegrator.
+type HTTPContext struct {
+	Writer  http.ResponseWriter
+	Request *http.Request
+}
+
+// HTTPWebIntegrator is a WebIntegrator built on net/http's ServeMux, allowing the admin panel to be served without any
+// third-party web framework.
+type HTTPWebIntegrator struct {
+	Mux *http.ServeMux
+	// MaxBodySize is the maximum number of bytes of a request body, including uploaded files. Larger requests are
+	// answered with 413 Request Entity Too Large. Zero uses HTTPDefaultMaxBodySize.
+	MaxBodySize int64
+}
+
+// NewHTTPWebIntegrator creates a new HTTPWebIntegrator registering its routes on the given mux. A new mux is created
+// when mux is nil.
+func NewHTTPWebIntegrator(mux *http.ServeMux) *HTTPWebIntegrator {
+	if mux == nil {
+		mux = http.NewServeMux()
+	}
+	return &HTTPWebIntegrator{Mux: mux, MaxBodySize: HTTPDefaultMaxBodySize}
+}
+
+// ServeHTTP dispatches the request to the admin panel routes, so the integrator can be used as an http.Handler.
+func (w *HTTPWebIntegrator) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w.Mux.ServeHTTP(rw, r)
+}
+
+// HandleRoute registers a route with the given method, path, and handler function. Path parameters written as
+// ":name" are converted to ServeMux wildcards.
+func (w *HTTPWebIntegrator) HandleRoute(method, path string, handler HandlerFunc) {
+	w.HandleResponseRoute(method, path, AdaptHandler(handler))
+}
+
+// HandleResponseRoute registers a route with the given method, path, and handler function returning a Response.
+func (w *HTTPWebIntegrator) HandleResponseRoute(method, path string, handler ResponseHandlerFunc) {
+	w.Mux.HandleFunc(method+" "+HTTPPattern(path), func(rw http.ResponseWriter, r *http.Request) {
+		maxBodySize := w.getMaxBodySize()
+		if r.ContentLength > maxBodySize {
+			http.Error(rw, ErrRequestTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(rw, r.Body, maxBodySize)
+		}
+		w.WriteResponse(rw, r, handler(&HTTPContext{Writer: rw, Request: r}))
+	})
+}
+
+func (w *HTTPWebIntegrator) getMaxBodySize() int64 {
+	if w.MaxBodySize <= 0 {
+		return HTTPDefaultMaxBodySize
+	}
+	return w.MaxBodySize
+}
+
+// WriteResponse writes the response to the response writer. When a streamed body fails before anything was written,
+// an error page is sent instead.
+func (w *HTTPWebIntegrator) WriteResponse(rw http.ResponseWriter, r *http.Request, response *Response) {
+	for key, values := range response.Headers {
+		for _, value := range values {
+			rw.Header().Add(key, value)
+		}
+	}
+	if response.RedirectLocation != "" {
+		http.Redirect(rw, r, response.RedirectLocation, int(response.GetStatusCode()))
+		return
+	}
+	if response.ContentType != "" {
+		rw.Header().Set("Content-Type", response.ContentType)
+	}
+
+	body := &httpBodyWriter{rw: rw, status: int(response.GetStatusCode())}
+	if err := response.WriteBody(body); err != nil && !body.started {
+		code, html := GetErrorHTML(http.StatusInternalServerError, err)
+		rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+		rw.Header().Del("Content-Disposition")
+		rw.WriteHeader(int(code))
+		_, _ = io.WriteString(rw, html)
+		return
+	}
+	body.start()
+}
+
+// ServeAssets serves static assets under the specified prefix using the provided renderer.
+func (w *HTTPWebIntegrator) ServeAssets(prefix string, renderer TemplateRenderer) {
+	prefix = "/" + strings.Trim(prefix, "/")
+	if prefix == "/" {
+		prefix = ""
+	}
+	w.Mux.HandleFunc("GET "+prefix+"/{file...}", func(rw http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("file")
+		asset, err := renderer.GetAsset(name)
+		if err != nil {
+			http.NotFound(rw, r)
+			return
+		}
+		contentType := mime.TypeByExtension(path.Ext(name))
+		if contentType == "" {
+			contentType = http.DetectContentType(asset)
+		}
+		rw.Header().Set("Content-Type", contentType)
+		_, _ = rw.Write(asset)
+	})
+}
+
+// GetQueryParam retrieves the value of a query parameter from the context.
+func (w *HTTPWebIntegrator) GetQueryParam(ctx interface{}, name string) string {
+	r := getHTTPRequest(ctx)
+	if r == nil {
+		return ""
+	}
+	return r.URL.Query().Get(name)
+}
+
+// GetPathParam retrieves the value of a path parameter from the context.
+func (w *HTTPWebIntegrator) GetPathParam(ctx interface{}, name string) string {
+	r := getHTTPRequest(ctx)
+	if r == nil {
+		return ""
+	}
+	return r.PathValue(name)
+}
+
+// GetRequestMethod retrieves the HTTP method of the request from the context.
+func (w *HTTPWebIntegrator) GetRequestMethod(ctx interface{}) string {
+	r := getHTTPRequest(ctx)
+	if r == nil {
+		return ""
+	}
+	return r.Method
+}
+
+// GetFormData retrieves the form data sent in the request body, including the values of multipart forms.
+func (w *HTTPWebIntegrator) GetFormData(ctx interface{}) map[string][]string {
+	r := getHTTPRequest(ctx)
+	if r == nil || parseHTTPForm(r) != nil {
+		return make(map[string][]string)
+	}
+	return r.PostForm
+}
+
+// GetFormFile retrieves the name and content of the uploaded file with the given form field name.
+func (w *HTTPWebIntegrator) GetFormFile(ctx interface{}, name string) (string, []byte, error) {
+	r := getHTTPRequest(ctx)
+	if r == nil {
+		return "", nil, nil
+	}
+	if err := parseHTTPForm(r); err != nil {
+		return "", nil, wrapHTTPBodyError(err)
+	}
+	file, header, err := r.FormFile(name)
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) || errors.Is(err, http.ErrNotMultipart) {
+			return "", nil, nil
+		}
+		return "", nil, wrapHTTPBodyError(err)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", nil, err
+	}
+	return header.Filename, content, nil
+}
+
+// GetContext retrieves the context of the request, which is cancelled when the client disconnects.
+func (w *HTTPWebIntegrator) GetContext(ctx interface{}) context.Context {
+	r := getHTTPRequest(ctx)
+	if r == nil {
+		return nil
+	}
+	return r.Context()
+}
+
+// GetRequestBody retrieves the raw body of the request from the context. It returns ErrRequestTooLarge when the body
+// exceeds the integrator's MaxBodySize.
+func (w *HTTPWebIntegrator) GetRequestBody(ctx interface{}) ([]byte, error) {
+	r := getHTTPRequest(ctx)
+	if r == nil || r.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, w.getMaxBodySize()))
+	return body, wrapHTTPBodyError(err)
+}
+
+// wrapHTTPBodyError wraps the errors of request bodies read past their maximum size with ErrRequestTooLarge.
+func wrapHTTPBodyError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return fmt.Errorf("%w: the limit is %d bytes", ErrRequestTooLarge, maxBytesErr.Limit)
+	}
+	return err
+}
+
+// HTTPPattern converts a route path using ":name" parameters into a ServeMux pattern using "{name}" wildcards.
+func HTTPPattern(path string) string {
+	if path == "" {
+		return "/{$}"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") && len(segment) > 1 {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// httpBodyWriter delays writing the status code until the first body bytes are written.
+type httpBodyWriter struct {
+	rw      http.ResponseWriter
+	status  int
+	started bool
+}
+
+func (b *httpBodyWriter) start() {
+	if !b.started {
+		b.started = true
+		b.rw.WriteHeader(b.status)
+	}
+}
+
+func (b *httpBodyWriter) Write(p []byte) (int, error) {
+	b.start()
+	return b.rw.Write(p)
+}
+
+func getHTTPRequest(ctx interface{}) *http.Request {
+	switch c := ctx.(type) {
+	case *HTTPContext:
+		return c.Request
+	case *http.Request:
+		return c
+	}
+	return nil
+}
+
+func parseHTTPForm(r *http.Request) error {
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType == "multipart/form-data" {
+		if r.MultipartForm != nil {
+			return nil
+		}
+		return r.ParseMultipartForm(HTTPMaxMultipartMemory)
+	}
+	return r.ParseForm()
+}
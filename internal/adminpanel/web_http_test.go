@@ -0,0 +1,257 @@
+package adminpanel
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func (o *ImportORMIntegrator) FetchInstanceOnlyFields(model interface{}, id interface{}, _ []string) (interface{}, error) {
+	return o.FetchInstance(model, id)
+}
+
+// newHTTPTestPanel creates an admin panel served through HTTPWebIntegrator with ImportTestModel registered in a
+// "TestApp" app. The ORM holds Alice along with the given instances, and configure, when not nil, adjusts the default
+// configuration before the panel is created.
+func newHTTPTestPanel(t *testing.T, permissionFunc PermissionFunc, configure func(*AdminConfig), instances ...*ImportTestModel) (*HTTPWebIntegrator, *Model, *ImportORMIntegrator) {
+	web := NewHTTPWebIntegrator(nil)
+	orm := newImportTestORM()
+	for _, instance := range instances {
+		orm.Existing[instance.ID] = instance
+	}
+	config := NewDefaultAdminConfig()
+	if configure != nil {
+		configure(config)
+	}
+	panel, err := NewAdminPanel(orm, web, permissionFunc, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testApp, err := panel.RegisterApp("TestApp", "Test App", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	model, err := testApp.RegisterModel(&ImportTestModel{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return web, model, orm
+}
+
+func TestHTTPPattern(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"", "/{$}"},
+		{"/admin", "/admin"},
+		{"/admin/app/model/:id/view", "/admin/app/model/{id}/view"},
+		{"/admin/app/model/:id/action/:name", "/admin/app/model/{id}/action/{name}"},
+	}
+
+	for _, tt := range tests {
+		if got := HTTPPattern(tt.path); got != tt.expected {
+			t.Errorf("HTTPPattern(%q) = %q, expected %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestHTTPWebIntegrator_Pages(t *testing.T) {
+	web, model, _ := newHTTPTestPanel(t, MockPermissionFunc, nil)
+
+	tests := []struct {
+		name     string
+		path     string
+		code     int
+		contains string
+	}{
+		{"Root", "/admin", http.StatusOK, "Test App"},
+		{"App", "/admin/a/TestApp", http.StatusOK, "Test App"},
+		{"Model", model.GetFullLink() + "?search=Alice", http.StatusOK, "Alice"},
+		{"Instance", model.GetFullLink() + "/1/view", http.StatusOK, "Alice"},
+		{"InvalidInstanceID", model.GetFullLink() + "/abc/view", http.StatusBadRequest, ""},
+		{"Asset", "/admin-assets/sample.css", http.StatusOK, ""},
+		{"MissingAsset", "/admin-assets/missing.css", http.StatusNotFound, ""},
+		{"UnknownRoute", "/admin/Unknown", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			web.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.code {
+				t.Fatalf("expected status %d, got %d: %s", tt.code, rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.contains) {
+				t.Errorf("expected body to contain %q, got %s", tt.contains, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHTTPWebIntegrator_FormRedirect(t *testing.T) {
+	web, model, orm := newHTTPTestPanel(t, MockPermissionFunc, nil)
+
+	form := url.Values{"Name": {"Bob"}, "Age": {"20"}}
+	req := httptest.NewRequest(http.MethodPost, model.GetFullLink()+"/add", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	web.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Location") == "" {
+		t.Error("expected a redirect location")
+	}
+	if len(orm.Created) != 1 || orm.Created[0].Name != "Bob" || orm.Created[0].Age != 20 {
+		t.Errorf("expected Bob to be created, got %v", orm.Created)
+	}
+}
+
+func TestHTTPWebIntegrator_Multipart(t *testing.T) {
+	web, model, orm := newHTTPTestPanel(t, MockPermissionFunc, nil)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("confirm", "yes"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	file, err := writer.CreateFormFile("file", "people.csv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = file.Write([]byte("Name,Age\nCarol,41\n"))
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, model.GetFullImportLink(), &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	web.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(orm.Created) != 1 || orm.Created[0].Name != "Carol" {
+		t.Errorf("expected Carol to be imported, got %v", orm.Created)
+	}
+}
+
+func TestHTTPWebIntegrator_MaxBodySize(t *testing.T) {
+	web, model, orm := newHTTPTestPanel(t, MockPermissionFunc, apiTestConfig)
+	web.MaxBodySize = 64
+
+	var upload bytes.Buffer
+	writer := multipart.NewWriter(&upload)
+	file, err := writer.CreateFormFile("file", "people.csv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = file.Write([]byte("Name,Age\n" + strings.Repeat("Carol,41\n", 20)))
+	_ = writer.Close()
+
+	apiBody := `{"Name": "` + strings.Repeat("C", 100) + `"}`
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        io.Reader
+	}{
+		{"Content Length", model.GetFullAPILink(), "application/json", strings.NewReader(apiBody)},
+		{"Streamed JSON", model.GetFullAPILink(), "application/json", io.MultiReader(strings.NewReader(apiBody))},
+		{"Streamed Upload", model.GetFullImportLink(), writer.FormDataContentType(), io.MultiReader(bytes.NewReader(upload.Bytes()))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, tt.body)
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			web.ServeHTTP(rec, req)
+			if rec.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("expected status 413, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+	if len(orm.Created) != 0 {
+		t.Errorf("expected nothing to be created, got %v", orm.Created)
+	}
+}
+
+func TestHTTPWebIntegrator_Export(t *testing.T) {
+	web, model, _ := newHTTPTestPanel(t, MockPermissionFunc, nil)
+
+	rec := httptest.NewRecorder()
+	web.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, model.GetFullExportLink()+"?format=csv", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != ExportFormatCSV.ContentType() {
+		t.Errorf("expected content type %q, got %q", ExportFormatCSV.ContentType(), got)
+	}
+	expectedDisposition := `attachment; filename="TestApp-ImportTestModel.csv"`
+	if got := rec.Header().Get("Content-Disposition"); got != expectedDisposition {
+		t.Errorf("expected disposition %q, got %q", expectedDisposition, got)
+	}
+}
+
+func TestHTTPWebIntegrator_WriteResponse(t *testing.T) {
+	web := NewHTTPWebIntegrator(nil)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	response := NewStreamResponse("text/plain", func(io.Writer) error { return fmt.Errorf("failed") })
+	response.Header().Set("Content-Disposition", "attachment")
+	rec := httptest.NewRecorder()
+	web.WriteResponse(rec, req, response)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500 for a failed stream, got %d", rec.Code)
+	}
+	if rec.Header().Get("Content-Disposition") != "" {
+		t.Error("expected the attachment header to be dropped on error")
+	}
+
+	response = NewRedirectResponse("/target")
+	response.SetCookie(&http.Cookie{Name: "session", Value: "abc"})
+	rec = httptest.NewRecorder()
+	web.WriteResponse(rec, req, response)
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/target" {
+		t.Errorf("expected redirect to /target, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	if rec.Header().Get("Set-Cookie") != "session=abc" {
+		t.Errorf("expected cookie to be set, got %q", rec.Header().Get("Set-Cookie"))
+	}
+}
+
+func TestHTTPWebIntegrator_ContextAccessors(t *testing.T) {
+	web := NewHTTPWebIntegrator(nil)
+
+	if web.GetQueryParam(nil, "q") != "" || web.GetPathParam(nil, "id") != "" || web.GetRequestMethod(nil) != "" {
+		t.Error("expected empty values for an unknown context")
+	}
+	if len(web.GetFormData(nil)) != 0 {
+		t.Error("expected empty form data for an unknown context")
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/path?q=search", strings.NewReader("field=value"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx := &HTTPContext{Request: req}
+	if got := web.GetQueryParam(ctx, "q"); got != "search" {
+		t.Errorf("expected query param 'search', got %q", got)
+	}
+	if got := web.GetRequestMethod(ctx); got != http.MethodPost {
+		t.Errorf("expected method POST, got %q", got)
+	}
+	if got := web.GetFormData(ctx)["field"]; len(got) != 1 || got[0] != "value" {
+		t.Errorf("expected form value 'value', got %v", got)
+	}
+	if _, _, err := web.GetFormFile(ctx, "file"); err != nil {
+		t.Errorf("expected no error for a missing file, got %v", err)
+	}
+}
@@ -1,5 +1,7 @@
 package form
 
+import "strings"
+
 type FieldValidationFunc func(value interface{}) (frontend []error, backend error)
 
 type Field interface {
@@ -30,3 +32,37 @@ func FieldValueIsValid(field Field, value interface{}) ([]error, error) {
 	}
 	return errs, nil
 }
+
+// ReadOnlyField wraps a field so it is rendered disabled and skips its validations. Browsers do not submit disabled
+// fields, so forms must leave the values of read-only fields unchanged when saving.
+type ReadOnlyField struct {
+	Field
+}
+
+// NewReadOnlyField wraps the field into a ReadOnlyField.
+func NewReadOnlyField(field Field) *ReadOnlyField {
+	return &ReadOnlyField{Field: field}
+}
+
+// HTML renders the wrapped field with the disabled attribute added to its root element.
+func (f *ReadOnlyField) HTML() (string, error) {
+	html, err := f.Field.HTML()
+	if err != nil {
+		return "", err
+	}
+	start := strings.Index(html, "<")
+	if start == -1 {
+		return html, nil
+	}
+	end := strings.IndexAny(html[start+1:], " />")
+	if end == -1 {
+		return html, nil
+	}
+	end += start + 1
+	return html[:end] + " disabled" + html[end:], nil
+}
+
+// GetValidationFunctions returns no validation functions, as read-only fields are never submitted.
+func (f *ReadOnlyField) GetValidationFunctions() []FieldValidationFunc {
+	return make([]FieldValidationFunc, 0)
+}
@@ -30,3 +30,26 @@ func TestFieldValueIsValid(t *testing.T) {
 	assert.Nil(t, fieldErrs)
 	assert.Nil(t, err)
 }
+
+func TestReadOnlyField(t *testing.T) {
+	textField := &TextField{Required: true}
+	err := textField.RegisterName("exampleField")
+	assert.Nil(t, err)
+	textField.RegisterInitialValue("value")
+
+	readOnly := form.NewReadOnlyField(textField)
+	html, err := readOnly.HTML()
+	assert.Nil(t, err)
+	assert.Contains(t, html, "<input disabled ")
+	assert.Contains(t, html, `value="value"`)
+
+	fieldErrs, err := form.FieldValueIsValid(readOnly, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, fieldErrs)
+
+	choiceField := &ChoiceField{}
+	_ = choiceField.RegisterName("choice")
+	html, err = form.NewReadOnlyField(choiceField).HTML()
+	assert.Nil(t, err)
+	assert.Contains(t, html, "<select disabled ")
+}
@@ -35,3 +35,18 @@ func TestTextFieldConversions(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "test", goType)
 }
+
+func TestTextFieldValidationOfEmptyValue(t *testing.T) {
+	textField := &TextField{}
+	maxLength := uint(5)
+	textField.MaxLength = &maxLength
+
+	errs, err := form.FieldValueIsValid(textField, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, errs)
+
+	textField.Required = true
+	errs, err = form.FieldValueIsValid(textField, nil)
+	assert.Nil(t, err)
+	assert.Len(t, errs, 1)
+}
@@ -12,6 +12,9 @@ type LogEntry struct {
 	ObjectRepr  string
 	ActionFlag  LogStoreLevel
 	Message     string
+	// PrevHash and Hash chain the entry to the one before it when it is stored through a ChainedLogStore.
+	PrevHash string
+	Hash     string
 }
 
 func (l *LogEntry) Repr() string {
@@ -26,3 +29,22 @@ type LogStore interface {
 	GetLogEntry(id interface{}) (*LogEntry, error)
 	GetLogEntries() ([]*LogEntry, error)
 }
+
+// QueryableLogStore is an optional interface log stores can implement to filter and paginate entries themselves
+// instead of in memory.
+type QueryableLogStore interface {
+	QueryLogEntries(query LogQuery) (*LogPage, error)
+}
+
+// QueryLogEntries returns the page of entries the query selects from the store. Stores that do not implement
+// QueryableLogStore have their entries fetched with GetLogEntries and paged in memory.
+func QueryLogEntries(store LogStore, query LogQuery) (*LogPage, error) {
+	if queryable, ok := store.(QueryableLogStore); ok {
+		return queryable.QueryLogEntries(query)
+	}
+	entries, err := store.GetLogEntries()
+	if err != nil {
+		return nil, err
+	}
+	return PageLogEntries(entries, query)
+}
@@ -0,0 +1,251 @@
+package logging
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ChainedLogStore wraps a log store to make its entries tamper-evident. Every inserted entry records the hash of the
+// entry before it, and its own hash covers that link along with the entry's canonical serialization, so altering,
+// inserting or removing an entry breaks the chain from that point on.
+//
+// Entries are chained in the newest first order of log queries. To keep that order equal to the insertion order,
+// action times are truncated to microseconds, which persistent stores keep exactly, and an entry no later than the
+// previous one is moved a microsecond after it.
+//
+// The newest hash is kept in memory, read from the store once by NewChainedLogStore, so the store must have a single
+// writer: every entry has to be inserted through the same ChainedLogStore. Several processes or replicas chaining to
+// one shared store would each extend the chain from their own head, forking it and failing verification. Deployments
+// with several writers should give each its own store or route log inserts through a single process.
+type ChainedLogStore struct {
+	Store LogStore
+
+	mu       sync.Mutex
+	lastHash string
+	lastTime time.Time
+}
+
+// ChainBreak describes the first entry of a chain that does not verify.
+type ChainBreak struct {
+	Entry  *LogEntry
+	Reason string
+}
+
+// ChainReport is the result of verifying a chain of log entries.
+type ChainReport struct {
+	// Verified is the number of entries verified before the first broken link.
+	Verified uint
+	// Unchained is the number of entries older than the chain, recorded before it was enabled.
+	Unchained uint
+	// Break is the first broken link, or nil when the whole chain verifies.
+	Break *ChainBreak
+}
+
+// VerifiableLogStore is the optional interface for log stores able to prove their entries were not altered.
+type VerifiableLogStore interface {
+	Verify() (*ChainReport, error)
+}
+
+// NewChainedLogStore wraps the store, continuing the chain from its newest entry.
+func NewChainedLogStore(store LogStore) (*ChainedLogStore, error) {
+	page, err := QueryLogEntries(store, LogQuery{Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	chained := &ChainedLogStore{Store: store}
+	if len(page.Entries) > 0 {
+		chained.lastHash = page.Entries[0].Hash
+		chained.lastTime = page.Entries[0].ActionTime
+	}
+	return chained, nil
+}
+
+// HashLogEntry returns the hash of the entry's canonical serialization, which includes the hash of the previous entry.
+// Identifiers are serialized by their string form so entries read back from persistent stores hash the same.
+func HashLogEntry(entry *LogEntry) (string, error) {
+	canonical, err := json.Marshal([]string{
+		canonicalLogValue(entry.ID),
+		entry.ActionTime.UTC().Format(time.RFC3339Nano),
+		canonicalLogValue(entry.UserID),
+		entry.UserRepr,
+		entry.ContentType,
+		canonicalLogValue(entry.ObjectID),
+		entry.ObjectRepr,
+		string(entry.ActionFlag),
+		entry.Message,
+		entry.PrevHash,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func canonicalLogValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// InsertLogEntry chains a copy of the entry to the newest one and inserts it, leaving the given entry unchanged.
+func (s *ChainedLogStore) InsertLogEntry(logEntry *LogEntry) error {
+	return s.InsertLogEntryContext(context.Background(), logEntry)
+}
+
+// InsertLogEntryContext is InsertLogEntry inserting the chained entry with ctx.
+func (s *ChainedLogStore) InsertLogEntryContext(ctx context.Context, logEntry *LogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chained := *logEntry
+	chained.ActionTime = chained.ActionTime.UTC().Truncate(time.Microsecond)
+	if !s.lastTime.IsZero() && !chained.ActionTime.After(s.lastTime) {
+		chained.ActionTime = s.lastTime.Add(time.Microsecond)
+	}
+	chained.PrevHash = s.lastHash
+	hash, err := HashLogEntry(&chained)
+	if err != nil {
+		return err
+	}
+	chained.Hash = hash
+
+	if err = AdaptLogStore(s.Store).InsertLogEntryContext(ctx, &chained); err != nil {
+		return err
+	}
+	s.lastHash = hash
+	s.lastTime = chained.ActionTime
+	return nil
+}
+
+func (s *ChainedLogStore) GetLogEntry(id interface{}) (*LogEntry, error) {
+	return s.Store.GetLogEntry(id)
+}
+
+func (s *ChainedLogStore) GetLogEntries() ([]*LogEntry, error) {
+	return s.Store.GetLogEntries()
+}
+
+func (s *ChainedLogStore) QueryLogEntries(query LogQuery) (*LogPage, error) {
+	return QueryLogEntries(s.Store, query)
+}
+
+func (s *ChainedLogStore) GetLogEntryContext(ctx context.Context, id interface{}) (*LogEntry, error) {
+	return AdaptLogStore(s.Store).GetLogEntryContext(ctx, id)
+}
+
+func (s *ChainedLogStore) GetLogEntriesContext(ctx context.Context) ([]*LogEntry, error) {
+	return AdaptLogStore(s.Store).GetLogEntriesContext(ctx)
+}
+
+func (s *ChainedLogStore) QueryLogEntriesContext(ctx context.Context, query LogQuery) (*LogPage, error) {
+	return AdaptLogStore(s.Store).QueryLogEntriesContext(ctx, query)
+}
+
+// chainVerifyPageSize is the number of entries Verify reads at once.
+var chainVerifyPageSize uint = 500
+
+// Verify walks the chain and reports the first entry that was altered, inserted or follows a removed entry, counting
+// from the oldest one. The oldest remaining entry may link to an entry pruned by a retention policy. Entries are read
+// a page at a time, so the log never has to be held in memory at once.
+func (s *ChainedLogStore) Verify() (*ChainReport, error) {
+	verifier := &chainVerifier{}
+	query := LogQuery{Limit: chainVerifyPageSize}
+	for {
+		page, err := QueryLogEntries(s.Store, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range page.Entries {
+			if err = verifier.add(entry); err != nil {
+				return nil, err
+			}
+		}
+		if page.NextCursor == "" {
+			return verifier.report(), nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+// VerifyLogChain verifies the chain formed by the entries, given newest first as returned by log queries.
+func VerifyLogChain(entries []*LogEntry) (*ChainReport, error) {
+	verifier := &chainVerifier{}
+	for _, entry := range entries {
+		if err := verifier.add(entry); err != nil {
+			return nil, err
+		}
+	}
+	return verifier.report(), nil
+}
+
+// chainVerifier verifies a chain fed newest first. Since every entry only links to the one before it, each link is
+// checked once the older entry arrives, and the oldest break found so far is kept, so the report matches a walk from
+// the oldest entry.
+type chainVerifier struct {
+	count    uint
+	newer    *LogEntry
+	breakAt  *ChainBreak
+	breakPos uint
+	// unhashed is the oldest entry of the run of entries without a hash fed since the last hashed one, and
+	// unhashedRun the length of that run. Such entries break the chain unless no hashed entry is older.
+	unhashed    *LogEntry
+	unhashedPos uint
+	unhashedRun uint
+}
+
+func (v *chainVerifier) add(entry *LogEntry) error {
+	pos, newer := v.count, v.newer
+	v.count++
+	v.newer = entry
+
+	if entry.Hash == "" {
+		v.unhashed, v.unhashedPos = entry, pos
+		v.unhashedRun++
+		return nil
+	}
+	if v.unhashedRun > 0 {
+		v.setBreak(v.unhashed, v.unhashedPos, "the entry is not part of the chain")
+		v.unhashed, v.unhashedRun = nil, 0
+	} else if newer != nil && newer.PrevHash != entry.Hash && (v.breakAt == nil || v.breakAt.Entry != newer) {
+		v.setBreak(newer, pos-1, "the entry does not link to the entry before it")
+	}
+
+	hash, err := HashLogEntry(entry)
+	if err != nil {
+		return err
+	}
+	if hash != entry.Hash {
+		v.setBreak(entry, pos, "the entry does not match its hash")
+	}
+	return nil
+}
+
+func (v *chainVerifier) setBreak(entry *LogEntry, pos uint, reason string) {
+	v.breakAt = &ChainBreak{Entry: entry, Reason: reason}
+	v.breakPos = pos
+}
+
+// report returns the result of the walk. The trailing run of entries without a hash is older than the chain.
+func (v *chainVerifier) report() *ChainReport {
+	report := &ChainReport{Unchained: v.unhashedRun, Break: v.breakAt}
+	if v.breakAt == nil {
+		report.Verified = v.count - v.unhashedRun
+	} else {
+		report.Verified = v.count - v.breakPos - 1 - v.unhashedRun
+	}
+	return report
+}
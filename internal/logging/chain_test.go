@@ -0,0 +1,156 @@
+package logging
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func newTestChainedLogStore(t *testing.T, store LogStore, count int) *ChainedLogStore {
+	chained, err := NewChainedLogStore(store)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actionTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < count; i++ {
+		entry := &LogEntry{ID: fmt.Sprint(i), ActionTime: actionTime, UserID: uint(1000000), ObjectID: i, ActionFlag: LogStoreLevelUpdate, Message: "changed"}
+		if err = chained.InsertLogEntry(entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return chained
+}
+
+func verifyTestChain(t *testing.T, store *ChainedLogStore) *ChainReport {
+	report, err := store.Verify()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return report
+}
+
+func TestChainedLogStore_Verify(t *testing.T) {
+	chained := newTestChainedLogStore(t, NewInMemoryLogStore(100), 5)
+
+	entries, _ := chained.GetLogEntries()
+	if entries[0].ID != "4" || !entries[0].ActionTime.After(entries[1].ActionTime) || entries[0].PrevHash != entries[1].Hash {
+		t.Errorf("expected equal action times to be ordered and linked, got %+v", entries[:2])
+	}
+	if report := verifyTestChain(t, chained); report.Break != nil || report.Verified != 5 {
+		t.Errorf("expected an intact chain of 5 entries, got %+v", report)
+	}
+
+	resumed, err := NewChainedLogStore(chained.Store)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = resumed.InsertLogEntry(&LogEntry{ID: "5", ActionTime: time.Now()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report := verifyTestChain(t, resumed); report.Break != nil || report.Verified != 6 {
+		t.Errorf("expected the chain to continue after a restart, got %+v", report)
+	}
+}
+
+func TestChainedLogStore_Verify_Tampered(t *testing.T) {
+	chained := newTestChainedLogStore(t, NewInMemoryLogStore(100), 5)
+	entry, _ := chained.GetLogEntry("2")
+	entry.Message = "nothing changed"
+
+	report := verifyTestChain(t, chained)
+	if report.Break == nil || report.Break.Entry.ID != "2" || report.Verified != 2 {
+		t.Errorf("expected the altered entry to break the chain, got %+v", report)
+	}
+
+	entry.Hash, _ = HashLogEntry(entry)
+	report = verifyTestChain(t, chained)
+	if report.Break == nil || report.Break.Entry.ID != "3" {
+		t.Errorf("expected a rehashed entry to break the link after it, got %+v", report)
+	}
+}
+
+func TestChainedLogStore_Verify_Removed(t *testing.T) {
+	memory := NewInMemoryLogStore(100)
+	chained := newTestChainedLogStore(t, memory, 5)
+	delete(memory.logEntryMap, "2")
+	memory.logIDs = append(memory.logIDs[:2:2], memory.logIDs[3:]...)
+
+	report := verifyTestChain(t, chained)
+	if report.Break == nil || report.Break.Entry.ID != "3" || report.Verified != 2 {
+		t.Errorf("expected the entry after the removed one to break the chain, got %+v", report)
+	}
+
+	delete(memory.logEntryMap, "0")
+	delete(memory.logEntryMap, "1")
+	memory.logIDs = memory.logIDs[:2]
+	if report = verifyTestChain(t, chained); report.Break != nil || report.Verified != 2 {
+		t.Errorf("expected the oldest entries to be prunable, got %+v", report)
+	}
+}
+
+func TestChainedLogStore_Verify_Unchained(t *testing.T) {
+	memory := NewInMemoryLogStore(100)
+	_ = memory.InsertLogEntry(&LogEntry{ID: "old", ActionTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+	chained := newTestChainedLogStore(t, memory, 2)
+
+	if report := verifyTestChain(t, chained); report.Break != nil || report.Unchained != 1 || report.Verified != 2 {
+		t.Errorf("expected the older entry to be skipped, got %+v", report)
+	}
+
+	_ = memory.InsertLogEntry(&LogEntry{ID: "forged", ActionTime: time.Now()})
+	if report := verifyTestChain(t, chained); report.Break == nil || report.Break.Entry.ID != "forged" {
+		t.Errorf("expected an entry inserted around the chain to break it, got %+v", report)
+	}
+}
+
+func TestChainedLogStore_JSONL(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestJSONLLogStore(t, JSONLLogStoreOptions{Dir: dir})
+	newTestChainedLogStore(t, store, 3)
+	_ = store.Close()
+
+	recovered, err := NewChainedLogStore(newTestJSONLLogStore(t, JSONLLogStoreOptions{Dir: dir}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report := verifyTestChain(t, recovered); report.Break != nil || report.Verified != 3 {
+		t.Errorf("expected the chain to verify after reading the entries back, got %+v", report)
+	}
+}
+
+func TestChainedLogStore_InsertLogEntry_Copies(t *testing.T) {
+	chained := newTestChainedLogStore(t, NewInMemoryLogStore(100), 1)
+	actionTime := time.Date(2024, 5, 1, 14, 0, 0, 1, time.FixedZone("CEST", 2*60*60))
+	entry := &LogEntry{ID: "1", ActionTime: actionTime}
+	if err := chained.InsertLogEntry(entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if entry.ActionTime != actionTime || entry.Hash != "" || entry.PrevHash != "" {
+		t.Errorf("expected the given entry to be left unchanged, got %+v", entry)
+	}
+	stored, _ := chained.GetLogEntry("1")
+	if stored == entry || stored.Hash == "" || stored.ActionTime.Location() != time.UTC {
+		t.Errorf("expected a chained copy to be stored, got %+v", stored)
+	}
+}
+
+func TestChainedLogStore_Verify_Paged(t *testing.T) {
+	pageSize := chainVerifyPageSize
+	chainVerifyPageSize = 2
+	t.Cleanup(func() { chainVerifyPageSize = pageSize })
+
+	memory := NewInMemoryLogStore(100)
+	_ = memory.InsertLogEntry(&LogEntry{ID: "old", ActionTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+	chained := newTestChainedLogStore(t, memory, 6)
+	if report := verifyTestChain(t, chained); report.Break != nil || report.Unchained != 1 || report.Verified != 6 {
+		t.Errorf("expected the chain to verify across pages, got %+v", report)
+	}
+
+	entry, _ := chained.GetLogEntry("1")
+	entry.Message = "nothing changed"
+	entry.Hash, _ = HashLogEntry(entry)
+	if report := verifyTestChain(t, chained); report.Break == nil || report.Break.Entry.ID != "2" || report.Verified != 2 {
+		t.Errorf("expected the link across a page boundary to break, got %+v", report)
+	}
+}
@@ -0,0 +1,81 @@
+package logging
+
+import "context"
+
+// ContextLogStore is the context-aware variant of LogStore. The admin panel calls these methods with the context of
+// the request on stores implementing them.
+type ContextLogStore interface {
+	InsertLogEntryContext(ctx context.Context, logEntry *LogEntry) error
+	GetLogEntryContext(ctx context.Context, id interface{}) (*LogEntry, error)
+	GetLogEntriesContext(ctx context.Context) ([]*LogEntry, error)
+	QueryLogEntriesContext(ctx context.Context, query LogQuery) (*LogPage, error)
+}
+
+// AdaptLogStore returns the store as a ContextLogStore. Stores implementing only LogStore are wrapped to check that the
+// context is not done before every call, otherwise ignoring it.
+func AdaptLogStore(store LogStore) ContextLogStore {
+	if contextStore, ok := store.(ContextLogStore); ok {
+		return contextStore
+	}
+	return logStoreAdapter{store: store}
+}
+
+type logStoreAdapter struct {
+	store LogStore
+}
+
+func (a logStoreAdapter) InsertLogEntryContext(ctx context.Context, logEntry *LogEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.store.InsertLogEntry(logEntry)
+}
+
+func (a logStoreAdapter) GetLogEntryContext(ctx context.Context, id interface{}) (*LogEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.store.GetLogEntry(id)
+}
+
+func (a logStoreAdapter) GetLogEntriesContext(ctx context.Context) ([]*LogEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.store.GetLogEntries()
+}
+
+func (a logStoreAdapter) QueryLogEntriesContext(ctx context.Context, query LogQuery) (*LogPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return QueryLogEntries(a.store, query)
+}
+
+// ContextLogStoreAdapter adapts a store implementing only ContextLogStore to LogStore, so it can be used wherever a
+// LogStore is expected. The LogStore methods run with context.Background(), while the admin panel keeps passing the
+// context of the request to the ContextLogStore methods.
+type ContextLogStoreAdapter struct {
+	ContextLogStore
+}
+
+// NewContextLogStoreAdapter adapts the context-aware store to LogStore.
+func NewContextLogStoreAdapter(store ContextLogStore) *ContextLogStoreAdapter {
+	return &ContextLogStoreAdapter{ContextLogStore: store}
+}
+
+func (a *ContextLogStoreAdapter) InsertLogEntry(logEntry *LogEntry) error {
+	return a.InsertLogEntryContext(context.Background(), logEntry)
+}
+
+func (a *ContextLogStoreAdapter) GetLogEntry(id interface{}) (*LogEntry, error) {
+	return a.GetLogEntryContext(context.Background(), id)
+}
+
+func (a *ContextLogStoreAdapter) GetLogEntries() ([]*LogEntry, error) {
+	return a.GetLogEntriesContext(context.Background())
+}
+
+func (a *ContextLogStoreAdapter) QueryLogEntries(query LogQuery) (*LogPage, error) {
+	return a.QueryLogEntriesContext(context.Background(), query)
+}
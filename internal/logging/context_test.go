@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestAdaptLogStore(t *testing.T) {
+	memory := NewInMemoryLogStore(10)
+	store := AdaptLogStore(memory)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := store.InsertLogEntryContext(cancelled, &LogEntry{ID: "1"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancelled context to be reported, got %v", err)
+	}
+	if _, err := store.QueryLogEntriesContext(cancelled, LogQuery{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancelled context to be reported, got %v", err)
+	}
+
+	if err := store.InsertLogEntryContext(context.Background(), &LogEntry{ID: "2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries, _ := memory.GetLogEntries(); len(entries) != 1 || entries[0].ID != "2" {
+		t.Errorf("expected only the entry inserted with a live context, got %v", entries)
+	}
+}
+
+func TestContextLogStoreAdapter(t *testing.T) {
+	adapter := NewContextLogStoreAdapter(AdaptLogStore(NewInMemoryLogStore(10)))
+	var store LogStore = adapter
+
+	if err := store.InsertLogEntry(&LogEntry{ID: "1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry, err := store.GetLogEntry("1"); err != nil || entry == nil {
+		t.Errorf("expected the entry to be found, got %v, %v", entry, err)
+	}
+	if AdaptLogStore(store) != ContextLogStore(adapter) {
+		t.Error("expected a context-aware store to be used as it is")
+	}
+}
+
+type contextKey struct{}
+
+// contextRecordingStore records the context value of the last call made through its ContextLogStore methods.
+type contextRecordingStore struct {
+	*InMemoryLogStore
+	value interface{}
+}
+
+func (s *contextRecordingStore) InsertLogEntryContext(ctx context.Context, logEntry *LogEntry) error {
+	s.value = ctx.Value(contextKey{})
+	return s.InsertLogEntry(logEntry)
+}
+
+func (s *contextRecordingStore) GetLogEntryContext(ctx context.Context, id interface{}) (*LogEntry, error) {
+	s.value = ctx.Value(contextKey{})
+	return s.GetLogEntry(id)
+}
+
+func (s *contextRecordingStore) GetLogEntriesContext(ctx context.Context) ([]*LogEntry, error) {
+	s.value = ctx.Value(contextKey{})
+	return s.GetLogEntries()
+}
+
+func (s *contextRecordingStore) QueryLogEntriesContext(ctx context.Context, query LogQuery) (*LogPage, error) {
+	s.value = ctx.Value(contextKey{})
+	return s.QueryLogEntries(query)
+}
+
+func TestWrappedStores_ForwardContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextKey{}, "request")
+
+	inner := &contextRecordingStore{InMemoryLogStore: NewInMemoryLogStore(10)}
+	chained, err := NewChainedLogStore(inner)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store := AdaptLogStore(chained)
+	if err = store.InsertLogEntryContext(ctx, &LogEntry{ID: "1"}); err != nil || inner.value != "request" {
+		t.Errorf("expected the chained store to insert with the context, got %v, %v", inner.value, err)
+	}
+	inner.value = nil
+	if _, err = store.QueryLogEntriesContext(ctx, LogQuery{}); err != nil || inner.value != "request" {
+		t.Errorf("expected the chained store to query with the context, got %v, %v", inner.value, err)
+	}
+
+	inner = &contextRecordingStore{InMemoryLogStore: NewInMemoryLogStore(10)}
+	buffer := NewRetryBuffer(inner, RetryBufferOptions{})
+	defer func() { _ = buffer.Close() }()
+	if err = buffer.InsertLogEntryContext(ctx, &LogEntry{ID: "1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = buffer.Flush(); err != nil || inner.value != "request" {
+		t.Errorf("expected the retry buffer to insert with the context, got %v, %v", inner.value, err)
+	}
+}
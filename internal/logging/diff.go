@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"encoding/json"
+)
+
+// RedactedValue replaces the values of sensitive fields in log entries.
+const RedactedValue = "[redacted]"
+
+// FieldDiff is the change of a field recorded by an update log entry.
+type FieldDiff struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// ParseFieldDiffs decodes the message of an update log entry recording a diff of the form {field: {old, new}}. It
+// reports false for messages in any other form, such as the submitted values recorded by earlier versions.
+func ParseFieldDiffs(message string) (map[string]FieldDiff, bool) {
+	var raw map[string]map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(message), &raw); err != nil || raw == nil {
+		return nil, false
+	}
+
+	diffs := make(map[string]FieldDiff, len(raw))
+	for field, values := range raw {
+		oldValue, hasOld := values["old"]
+		newValue, hasNew := values["new"]
+		if len(values) != 2 || !hasOld || !hasNew {
+			return nil, false
+		}
+		var diff FieldDiff
+		if err := json.Unmarshal(oldValue, &diff.Old); err != nil {
+			return nil, false
+		}
+		if err := json.Unmarshal(newValue, &diff.New); err != nil {
+			return nil, false
+		}
+		diffs[field] = diff
+	}
+	return diffs, true
+}
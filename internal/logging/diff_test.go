@@ -0,0 +1,18 @@
+package logging
+
+import (
+	"testing"
+)
+
+func TestParseFieldDiffs(t *testing.T) {
+	diffs, ok := ParseFieldDiffs(`{"Name":{"old":"Alice","new":"Alicia"},"Age":{"old":null,"new":31}}`)
+	if !ok || len(diffs) != 2 || diffs["Name"].Old != "Alice" || diffs["Name"].New != "Alicia" || diffs["Age"].Old != nil || diffs["Age"].New != float64(31) {
+		t.Errorf("expected the diff to be parsed, got %v, %v", diffs, ok)
+	}
+
+	for _, message := range []string{"", "renamed", "null", `{"Name":"Alicia"}`, `{"Name":{"new":"Alicia"}}`, `{"Name":{"old":1,"new":2,"at":3}}`} {
+		if _, ok = ParseFieldDiffs(message); ok {
+			t.Errorf("expected %q not to be parsed as a diff", message)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const jsonlDateLayout = "2006-01-02"
+
+// JSONLLogStoreOptions configures a JSONLLogStore.
+type JSONLLogStoreOptions struct {
+	// Dir is the directory holding the log files. It is created when missing.
+	Dir string
+	// BaseName prefixes the log file names, which are "<BaseName>-<date>.<sequence>.jsonl". Defaults to "admin-log".
+	BaseName string
+	// MaxFileSize is the size in bytes after which a new file is started. Zero disables rotation by size.
+	MaxFileSize int64
+	// RotateDaily starts a new file when the UTC date changes.
+	RotateDaily bool
+	// TailSize is the number of most recent entries kept in memory. Defaults to 1000.
+	TailSize uint
+	// Sync flushes every entry to disk before InsertLogEntry returns, so entries survive a machine crash at the cost
+	// of a disk flush per entry. Files are always flushed when they are rotated and when the store is closed.
+	Sync bool
+}
+
+// JSONLLogStore is an append-only log store writing each entry as one JSON line to rotated files. The most recent
+// entries are indexed in memory; older ones are read back from the files. It is safe for concurrent use.
+//
+// Queries read the files newest first and stop once the page is filled, which requires the files to be in action time
+// order. An entry no later than the newest stored one, such as an entry flushed late by a RetryBuffer, is therefore
+// stored with its action time moved a nanosecond after the newest one.
+type JSONLLogStore struct {
+	options JSONLLogStoreOptions
+	now     func() time.Time
+
+	mu       sync.RWMutex
+	file     *os.File
+	fileDate string
+	fileSeq  int
+	fileSize int64
+	lastTime time.Time
+	tail     []*LogEntry
+	index    map[string]*LogEntry
+}
+
+type jsonlFile struct {
+	path string
+	date string
+	seq  int
+}
+
+// NewJSONLLogStore creates a JSONL log store, recovering the in-memory index from the existing files in the
+// directory.
+func NewJSONLLogStore(options JSONLLogStoreOptions) (*JSONLLogStore, error) {
+	if options.Dir == "" {
+		return nil, fmt.Errorf("log directory is required")
+	}
+	if options.BaseName == "" {
+		options.BaseName = "admin-log"
+	}
+	if options.TailSize == 0 {
+		options.TailSize = 1000
+	}
+	if err := os.MkdirAll(options.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	store := &JSONLLogStore{
+		options: options,
+		now:     time.Now,
+		index:   make(map[string]*LogEntry),
+	}
+	if err := store.recover(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// recover rebuilds the tail index from the newest files and reopens the latest one for appending. A truncated last
+// line, left in the latest file by an interrupted write, is cut off so the next entry starts on a line of its own.
+func (store *JSONLLogStore) recover() error {
+	files, err := store.listFiles()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	latest := files[len(files)-1]
+	size, err := truncatePartialLine(latest.path)
+	if err != nil {
+		return err
+	}
+	store.fileDate, store.fileSeq, store.fileSize = latest.date, latest.seq, size
+
+	for i := len(files) - 1; i >= 0 && uint(len(store.tail)) < store.options.TailSize; i-- {
+		entries, err := readJSONLFile(files[i].path)
+		if err != nil {
+			return err
+		}
+		for j := len(entries) - 1; j >= 0 && uint(len(store.tail)) < store.options.TailSize; j-- {
+			store.tail = append(store.tail, entries[j])
+			store.index[fmt.Sprint(entries[j].ID)] = entries[j]
+			if entries[j].ActionTime.After(store.lastTime) {
+				store.lastTime = entries[j].ActionTime
+			}
+		}
+	}
+	return nil
+}
+
+// truncatePartialLine cuts the file after its last newline and returns its new size.
+func truncatePartialLine(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	size := int64(bytes.LastIndexByte(data, '\n') + 1)
+	if size == int64(len(data)) {
+		return size, nil
+	}
+	return size, os.Truncate(path, size)
+}
+
+// listFiles returns the store's log files, oldest first.
+func (store *JSONLLogStore) listFiles() ([]jsonlFile, error) {
+	dirEntries, err := os.ReadDir(store.options.Dir)
+	if err != nil {
+		return nil, err
+	}
+	prefix := store.options.BaseName + "-"
+	files := make([]jsonlFile, 0)
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".jsonl") {
+			continue
+		}
+		parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".jsonl"), ".")
+		if len(parts) != 2 {
+			continue
+		}
+		if _, err := time.Parse(jsonlDateLayout, parts[0]); err != nil {
+			continue
+		}
+		seq, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		files = append(files, jsonlFile{path: filepath.Join(store.options.Dir, name), date: parts[0], seq: seq})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].date != files[j].date {
+			return files[i].date < files[j].date
+		}
+		return files[i].seq < files[j].seq
+	})
+	return files, nil
+}
+
+func (store *JSONLLogStore) filePath(date string, seq int) string {
+	return filepath.Join(store.options.Dir, fmt.Sprintf("%s-%s.%d.jsonl", store.options.BaseName, date, seq))
+}
+
+// readJSONLFile decodes the entries of a file in the order they were written. A last line missing its newline, left by
+// an interrupted write, is ignored; any other line that cannot be decoded means the file is corrupted.
+func readJSONLFile(path string) ([]*LogEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*LogEntry, 0)
+	for lineNumber := 1; ; lineNumber++ {
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			return entries, nil
+		}
+		line := bytes.TrimSpace(data[:end])
+		data = data[end+1:]
+		if len(line) == 0 {
+			continue
+		}
+		var entry LogEntry
+		if err = json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("log file %s is corrupted at line %d: %w", path, lineNumber, err)
+		}
+		entries = append(entries, &entry)
+	}
+}
+
+// rotate opens the file the next entry of the given size is appended to, starting a new one when the date changed or
+// the current one is full.
+func (store *JSONLLogStore) rotate(lineSize int64) error {
+	date := store.now().UTC().Format(jsonlDateLayout)
+	seq := store.fileSeq
+	switch {
+	case store.fileDate == "":
+		store.fileDate = date
+		seq = 0
+	case store.options.RotateDaily && date != store.fileDate:
+		seq = 0
+	case store.options.MaxFileSize > 0 && store.fileSize > 0 && store.fileSize+lineSize > store.options.MaxFileSize:
+		date = store.fileDate
+		seq++
+	default:
+		date = store.fileDate
+	}
+	if store.file != nil && date == store.fileDate && seq == store.fileSeq {
+		return nil
+	}
+
+	if store.file != nil {
+		if err := closeJSONLFile(store.file); err != nil {
+			return err
+		}
+		store.file = nil
+	}
+	file, err := os.OpenFile(store.filePath(date, seq), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	store.file, store.fileDate, store.fileSeq, store.fileSize = file, date, seq, info.Size()
+	return nil
+}
+
+// InsertLogEntry appends the entry to the current file, moving its action time after the newest stored entry when it
+// is not later already; the given entry is left unchanged. Duplicate IDs are only detected among the recent entries
+// kept in memory; older entries in the files are not checked.
+func (store *JSONLLogStore) InsertLogEntry(logEntry *LogEntry) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	logID := fmt.Sprint(logEntry.ID)
+	if _, exists := store.index[logID]; exists {
+		return fmt.Errorf("log entry with ID %s already exists", logID)
+	}
+
+	log := logEntry
+	if !store.lastTime.IsZero() && !log.ActionTime.After(store.lastTime) {
+		ordered := *logEntry
+		ordered.ActionTime = store.lastTime.Add(time.Nanosecond)
+		log = &ordered
+	}
+	line, err := json.Marshal(log)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if err = store.rotate(int64(len(line))); err != nil {
+		return err
+	}
+	n, err := store.file.Write(line)
+	store.fileSize += int64(n)
+	if err != nil {
+		return err
+	}
+	if store.options.Sync {
+		if err = store.file.Sync(); err != nil {
+			return err
+		}
+	}
+	store.lastTime = log.ActionTime
+
+	store.tail = append([]*LogEntry{log}, store.tail...)
+	store.index[logID] = log
+	if uint(len(store.tail)) > store.options.TailSize {
+		delete(store.index, fmt.Sprint(store.tail[len(store.tail)-1].ID))
+		store.tail = store.tail[:len(store.tail)-1]
+	}
+	return nil
+}
+
+// GetLogEntry returns the entry with the given ID, looking it up in the files when it is not among the recent
+// entries.
+func (store *JSONLLogStore) GetLogEntry(id interface{}) (*LogEntry, error) {
+	logID := fmt.Sprint(id)
+	store.mu.RLock()
+	logEntry, exists := store.index[logID]
+	store.mu.RUnlock()
+	if exists {
+		return logEntry, nil
+	}
+
+	var found *LogEntry
+	err := store.scan(func(entry *LogEntry) bool {
+		if fmt.Sprint(entry.ID) == logID {
+			found = entry
+			return false
+		}
+		return true
+	})
+	return found, err
+}
+
+// GetLogEntries returns the recent entries kept in memory, newest first.
+func (store *JSONLLogStore) GetLogEntries() ([]*LogEntry, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	logEntries := make([]*LogEntry, len(store.tail))
+	copy(logEntries, store.tail)
+	return logEntries, nil
+}
+
+// QueryLogEntries returns the page of entries the query selects. The page is served from the recent entries kept in
+// memory when they hold the whole store or fill the page; otherwise files are read newest first until the page is
+// filled. Both shortcuts rely on the entries being stored in action time order, which InsertLogEntry maintains.
+func (store *JSONLLogStore) QueryLogEntries(query LogQuery) (*LogPage, error) {
+	var cursor *LogCursor
+	if query.Cursor != "" {
+		parsed, err := ParseLogCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &parsed
+	}
+
+	store.mu.RLock()
+	tail := make([]*LogEntry, len(store.tail))
+	copy(tail, store.tail)
+	complete := uint(len(store.tail)) < store.options.TailSize
+	store.mu.RUnlock()
+	page, err := PageLogEntries(tail, query)
+	if err != nil {
+		return nil, err
+	}
+	if complete || page.NextCursor != "" {
+		return page, nil
+	}
+
+	files, err := store.snapshotFiles()
+	if err != nil {
+		return nil, err
+	}
+	logEntries := make([]*LogEntry, 0)
+	for i := len(files) - 1; i >= 0; i-- {
+		if query.Limit > 0 && uint(len(logEntries)) > query.Limit {
+			break
+		}
+		entries, err := readJSONLFile(files[i].path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if query.Matches(entry) && (cursor == nil || cursor.Precedes(entry)) {
+				logEntries = append(logEntries, entry)
+			}
+		}
+	}
+	return PageLogEntries(logEntries, query)
+}
+
+// snapshotFiles returns the store's log files, oldest first, as they are when it is called. The files can then be read
+// without holding the lock: appends only add complete lines, and a line still being written is ignored.
+func (store *JSONLLogStore) snapshotFiles() ([]jsonlFile, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	return store.listFiles()
+}
+
+// scan calls fn for every stored entry, newest first, until it returns false.
+func (store *JSONLLogStore) scan(fn func(entry *LogEntry) bool) error {
+	files, err := store.snapshotFiles()
+	if err != nil {
+		return err
+	}
+	for i := len(files) - 1; i >= 0; i-- {
+		entries, err := readJSONLFile(files[i].path)
+		if err != nil {
+			return err
+		}
+		for j := len(entries) - 1; j >= 0; j-- {
+			if !fn(entries[j]) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// Close flushes the current file to disk and closes it.
+func (store *JSONLLogStore) Close() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.file == nil {
+		return nil
+	}
+	err := closeJSONLFile(store.file)
+	store.file = nil
+	return err
+}
+
+// closeJSONLFile flushes the file to disk before closing it.
+func closeJSONLFile(file *os.File) error {
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
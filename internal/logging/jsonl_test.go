@@ -0,0 +1,227 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestJSONLLogStore(t *testing.T, options JSONLLogStoreOptions) *JSONLLogStore {
+	store, err := NewJSONLLogStore(options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func insertTestEntries(t *testing.T, store *JSONLLogStore, from, to int) {
+	for i := from; i < to; i++ {
+		entry := &LogEntry{ID: fmt.Sprint(i), ActionTime: time.Now(), UserID: "alice", ActionFlag: LogStoreLevelUpdate, Message: "changed"}
+		if err := store.InsertLogEntry(entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestJSONLLogStore_InsertAndRecover(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestJSONLLogStore(t, JSONLLogStoreOptions{Dir: dir, TailSize: 3})
+	insertTestEntries(t, store, 0, 5)
+
+	if err := store.InsertLogEntry(&LogEntry{ID: "4"}); err == nil {
+		t.Error("expected an error for a duplicate ID")
+	}
+	entries, _ := store.GetLogEntries()
+	if len(entries) != 3 || entries[0].ID != "4" || entries[2].ID != "2" {
+		t.Errorf("expected the 3 newest entries, got %v", entries)
+	}
+	entry, err := store.GetLogEntry("0")
+	if err != nil || entry == nil || entry.Message != "changed" {
+		t.Errorf("expected entry 0 to be read from the file, got %v, %v", entry, err)
+	}
+	if err = store.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	recovered := newTestJSONLLogStore(t, JSONLLogStoreOptions{Dir: dir, TailSize: 3})
+	entries, _ = recovered.GetLogEntries()
+	if len(entries) != 3 || entries[0].ID != "4" || entries[2].ID != "2" {
+		t.Errorf("expected the index to be recovered, got %v", entries)
+	}
+	insertTestEntries(t, recovered, 5, 6)
+	files, _ := recovered.listFiles()
+	if len(files) != 1 {
+		t.Errorf("expected appends to continue the existing file, got %d files", len(files))
+	}
+}
+
+func TestJSONLLogStore_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestJSONLLogStore(t, JSONLLogStoreOptions{Dir: dir, MaxFileSize: 300, RotateDaily: true})
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return day }
+
+	insertTestEntries(t, store, 0, 4)
+	files, _ := store.listFiles()
+	if len(files) < 2 || files[0].date != "2024-05-01" || files[1].seq != 1 {
+		t.Fatalf("expected rotation by size, got %v", files)
+	}
+
+	day = day.Add(24 * time.Hour)
+	insertTestEntries(t, store, 4, 5)
+	files, _ = store.listFiles()
+	if last := files[len(files)-1]; last.date != "2024-05-02" || last.seq != 0 {
+		t.Errorf("expected rotation by date, got %v", files)
+	}
+
+	page, err := store.QueryLogEntries(LogQuery{UserID: "alice"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries := page.Entries; len(entries) != 5 || entries[0].ID != "4" || entries[4].ID != "0" {
+		t.Errorf("expected every entry across files newest first, got %v", entries)
+	}
+}
+
+func TestJSONLLogStore_QueryFromTail(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestJSONLLogStore(t, JSONLLogStoreOptions{Dir: dir, TailSize: 5})
+	insertTestEntries(t, store, 0, 10)
+	files, _ := store.listFiles()
+	data, err := os.ReadFile(files[0].path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = os.WriteFile(files[0].path, append([]byte("not json\n"), data...), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	page, err := store.QueryLogEntries(LogQuery{Limit: 3})
+	if err != nil {
+		t.Fatalf("expected the first page to be served from memory, got %v", err)
+	}
+	if len(page.Entries) != 3 || page.NextCursor == "" {
+		t.Errorf("expected a full first page, got %v", page)
+	}
+
+	if _, err = store.QueryLogEntries(LogQuery{Limit: 3, Cursor: page.NextCursor}); err == nil {
+		t.Error("expected pages beyond the recent entries to be read from the files")
+	}
+}
+
+func TestJSONLLogStore_OutOfOrderInsert(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestJSONLLogStore(t, JSONLLogStoreOptions{Dir: dir, MaxFileSize: 300, TailSize: 2, Sync: true})
+	insertTestEntries(t, store, 0, 5)
+
+	backdated := time.Now().Add(-time.Hour)
+	late := &LogEntry{ID: "late", ActionTime: backdated, UserID: "alice", ActionFlag: LogStoreLevelUpdate}
+	if err := store.InsertLogEntry(late); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !late.ActionTime.Equal(backdated) {
+		t.Errorf("expected the given entry to be left unchanged, got %v", late.ActionTime)
+	}
+
+	var ids []interface{}
+	query := LogQuery{Limit: 2}
+	for {
+		page, err := store.QueryLogEntries(query)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, entry := range page.Entries {
+			ids = append(ids, entry.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	if fmt.Sprint(ids) != "[late 4 3 2 1 0]" {
+		t.Errorf("expected the late entry to be stored after the newest one, got %v", ids)
+	}
+}
+
+func TestJSONLLogStore_TruncatedLine(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestJSONLLogStore(t, JSONLLogStoreOptions{Dir: dir})
+	insertTestEntries(t, store, 0, 2)
+	_ = store.Close()
+
+	files, _ := store.listFiles()
+	file, err := os.OpenFile(files[0].path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = file.WriteString(`{"ID":"2","Acti`)
+	_ = file.Close()
+
+	recovered := newTestJSONLLogStore(t, JSONLLogStoreOptions{Dir: dir})
+	entries, _ := recovered.GetLogEntries()
+	if len(entries) != 2 {
+		t.Errorf("expected the truncated line to be ignored, got %v", entries)
+	}
+
+	insertTestEntries(t, recovered, 2, 3)
+	_ = recovered.Close()
+	reopened := newTestJSONLLogStore(t, JSONLLogStoreOptions{Dir: dir})
+	entries, _ = reopened.GetLogEntries()
+	if len(entries) != 3 || entries[0].ID != "2" {
+		t.Errorf("expected the entry written after recovery to survive a restart, got %v", entries)
+	}
+}
+
+func TestJSONLLogStore_CorruptedLine(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestJSONLLogStore(t, JSONLLogStoreOptions{Dir: dir})
+	insertTestEntries(t, store, 0, 1)
+	_ = store.Close()
+
+	files, _ := store.listFiles()
+	file, err := os.OpenFile(files[0].path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = file.WriteString("not json\n")
+	_ = file.Close()
+
+	if _, err = NewJSONLLogStore(JSONLLogStoreOptions{Dir: dir}); err == nil {
+		t.Error("expected an error for a corrupted line in the middle of a file")
+	}
+}
+
+func TestJSONLLogStore_ConcurrentWriters(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestJSONLLogStore(t, JSONLLogStoreOptions{Dir: dir, MaxFileSize: 1024})
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				if err := store.InsertLogEntry(&LogEntry{ID: fmt.Sprintf("%d-%d", w, i), ActionTime: time.Now()}); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				_, _ = store.GetLogEntries()
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	page, err := store.QueryLogEntries(LogQuery{})
+	if err != nil || len(page.Entries) != 200 {
+		t.Errorf("expected 200 entries, got %v, %v", page, err)
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	for _, path := range matches {
+		if info, _ := os.Stat(path); info.Size() > 1024 {
+			t.Errorf("expected %s to respect the size limit, got %d bytes", path, info.Size())
+		}
+	}
+}
@@ -6,6 +6,8 @@ const (
 	LogStoreLevelDelete       LogStoreLevel = "delete"
 	LogStoreLevelCreate       LogStoreLevel = "create"
 	LogStoreLevelUpdate       LogStoreLevel = "update"
+	LogStoreLevelAction       LogStoreLevel = "action"
+	LogStoreLevelExport       LogStoreLevel = "export"
 	LogStoreLevelInstanceView LogStoreLevel = "instance_view"
 	LogStoreLevelListView     LogStoreLevel = "list_view"
 	LogStoreLevelPanelView    LogStoreLevel = "panel_view"
@@ -15,6 +17,8 @@ var levelsHierarchy = map[LogStoreLevel]int{
 	LogStoreLevelDelete:       1,
 	LogStoreLevelCreate:       2,
 	LogStoreLevelUpdate:       3,
+	LogStoreLevelAction:       3,
+	LogStoreLevelExport:       3,
 	LogStoreLevelInstanceView: 4,
 	LogStoreLevelListView:     5,
 	LogStoreLevelPanelView:    6,
@@ -53,3 +53,8 @@ func (store *InMemoryLogStore) GetLogEntries() ([]*LogEntry, error) {
 	}
 	return logEntries, nil
 }
+
+func (store *InMemoryLogStore) QueryLogEntries(query LogQuery) (*LogPage, error) {
+	logEntries, _ := store.GetLogEntries()
+	return PageLogEntries(logEntries, query)
+}
@@ -0,0 +1,158 @@
+package logging
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LogQuery describes a lookup of log entries. Zero fields do not constrain the results, which are ordered newest
+// first.
+type LogQuery struct {
+	UserID interface{}
+	// ActionFlags lists the accepted actions. Empty accepts every action.
+	ActionFlags []LogStoreLevel
+	ContentType string
+	ObjectID    interface{}
+	// Since and Until bound the action time of the entries, Since inclusive and Until exclusive.
+	Since time.Time
+	Until time.Time
+	// Search matches entries whose user, content type, object or message contains it, ignoring case.
+	Search string
+	// Cursor continues a previous lookup after the last entry of its page, as returned in LogPage.NextCursor.
+	Cursor string
+	// Limit is the maximum number of entries to return. Zero means no limit.
+	Limit uint
+}
+
+// LogPage holds a page of log entries matching a query.
+type LogPage struct {
+	Entries []*LogEntry
+	// NextCursor continues the lookup after the last entry of the page. It is empty on the last page.
+	NextCursor string
+}
+
+// Matches reports whether the entry satisfies the filters of the query, ignoring its cursor and limit. Identifiers
+// are compared by their string form so they match entries read back from persistent stores.
+func (q LogQuery) Matches(entry *LogEntry) bool {
+	if q.UserID != nil && fmt.Sprint(entry.UserID) != fmt.Sprint(q.UserID) {
+		return false
+	}
+	if len(q.ActionFlags) > 0 && !containsLevel(q.ActionFlags, entry.ActionFlag) {
+		return false
+	}
+	if q.ContentType != "" && entry.ContentType != q.ContentType {
+		return false
+	}
+	if q.ObjectID != nil && fmt.Sprint(entry.ObjectID) != fmt.Sprint(q.ObjectID) {
+		return false
+	}
+	if !q.Since.IsZero() && entry.ActionTime.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !entry.ActionTime.Before(q.Until) {
+		return false
+	}
+	if q.Search != "" {
+		search := strings.ToLower(q.Search)
+		found := false
+		for _, value := range []string{entry.UserRepr, entry.ContentType, entry.ObjectRepr, entry.Message} {
+			if strings.Contains(strings.ToLower(value), search) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func containsLevel(levels []LogStoreLevel, level LogStoreLevel) bool {
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// LogCursor is the position of an entry in the newest first order of log queries.
+type LogCursor struct {
+	ActionTime time.Time
+	ID         string
+}
+
+// NewLogCursor returns the position of the entry.
+func NewLogCursor(entry *LogEntry) LogCursor {
+	return LogCursor{ActionTime: entry.ActionTime, ID: fmt.Sprint(entry.ID)}
+}
+
+// ParseLogCursor decodes a cursor produced by LogCursor.String.
+func ParseLogCursor(cursor string) (LogCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return LogCursor{}, fmt.Errorf("invalid log cursor: %w", err)
+	}
+	actionTime, id, ok := strings.Cut(string(decoded), "|")
+	if !ok {
+		return LogCursor{}, fmt.Errorf("invalid log cursor")
+	}
+	parsedTime, err := time.Parse(time.RFC3339Nano, actionTime)
+	if err != nil {
+		return LogCursor{}, fmt.Errorf("invalid log cursor: %w", err)
+	}
+	return LogCursor{ActionTime: parsedTime, ID: id}, nil
+}
+
+// String encodes the cursor for use in LogQuery.Cursor.
+func (c LogCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.ActionTime.Format(time.RFC3339Nano) + "|" + c.ID))
+}
+
+// Precedes reports whether the entry comes after the cursor in newest first order, that is whether it is older, or
+// as old with a lower ID.
+func (c LogCursor) Precedes(entry *LogEntry) bool {
+	if !entry.ActionTime.Equal(c.ActionTime) {
+		return entry.ActionTime.Before(c.ActionTime)
+	}
+	return fmt.Sprint(entry.ID) < c.ID
+}
+
+// SortLogEntries orders the entries newest first, breaking ties by descending ID.
+func SortLogEntries(entries []*LogEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return NewLogCursor(entries[i]).Precedes(entries[j])
+	})
+}
+
+// PageLogEntries returns the page of the entries the query selects. It lets stores holding their entries in memory
+// implement QueryableLogStore.
+func PageLogEntries(entries []*LogEntry, query LogQuery) (*LogPage, error) {
+	var cursor *LogCursor
+	if query.Cursor != "" {
+		parsed, err := ParseLogCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &parsed
+	}
+
+	matching := make([]*LogEntry, 0)
+	for _, entry := range entries {
+		if query.Matches(entry) && (cursor == nil || cursor.Precedes(entry)) {
+			matching = append(matching, entry)
+		}
+	}
+	SortLogEntries(matching)
+
+	page := &LogPage{Entries: matching}
+	if query.Limit > 0 && uint(len(matching)) > query.Limit {
+		page.Entries = matching[:query.Limit]
+		page.NextCursor = NewLogCursor(page.Entries[len(page.Entries)-1]).String()
+	}
+	return page, nil
+}
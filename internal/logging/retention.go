@@ -0,0 +1,22 @@
+package logging
+
+import "time"
+
+// RetentionPolicy describes which log entries a store keeps. Zero fields do not prune anything.
+type RetentionPolicy struct {
+	// MaxAge is the age after which entries are pruned.
+	MaxAge time.Duration
+	// MaxEntries is the number of most recent entries kept.
+	MaxEntries uint
+	// Interval is the minimum time between two automatic prunes when entries are inserted. Zero disables automatic
+	// pruning.
+	Interval time.Duration
+}
+
+// Cutoff returns the action time before which entries are pruned, or the zero time when entries never expire.
+func (p RetentionPolicy) Cutoff(now time.Time) time.Time {
+	if p.MaxAge <= 0 {
+		return time.Time{}
+	}
+	return now.Add(-p.MaxAge)
+}
@@ -0,0 +1,151 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RetryBufferOptions configures a RetryBuffer.
+type RetryBufferOptions struct {
+	// MaxEntries is the number of entries the buffer holds before refusing new ones. Defaults to 1000.
+	MaxEntries int
+	// Interval is the delay between background retries. Defaults to 5 seconds.
+	Interval time.Duration
+	// OnInsert is called with every entry once the store inserted it, usually from the background goroutine, for
+	// example to stream it.
+	OnInsert func(entry *LogEntry)
+}
+
+// RetryBuffer inserts log entries into a store from a background goroutine, keeping the entries the store fails to
+// insert and retrying them. Entries are inserted in the order they were given, so a new entry waits for the buffered
+// ones, but the callers never wait for the store.
+type RetryBuffer struct {
+	Store LogStore
+
+	options   RetryBufferOptions
+	mu        sync.Mutex
+	flushMu   sync.Mutex
+	entries   []bufferedLogEntry
+	wake      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// bufferedLogEntry is an entry waiting in a RetryBuffer along with the context it is inserted with.
+type bufferedLogEntry struct {
+	ctx   context.Context
+	entry *LogEntry
+}
+
+// NewRetryBuffer creates a retry buffer for the store and starts retrying in the background.
+func NewRetryBuffer(store LogStore, options RetryBufferOptions) *RetryBuffer {
+	if options.MaxEntries <= 0 {
+		options.MaxEntries = 1000
+	}
+	if options.Interval <= 0 {
+		options.Interval = 5 * time.Second
+	}
+	buffer := &RetryBuffer{
+		Store:   store,
+		options: options,
+		entries: make([]bufferedLogEntry, 0),
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go buffer.retry()
+	return buffer
+}
+
+func (b *RetryBuffer) retry() {
+	defer close(b.done)
+	ticker := time.NewTicker(b.options.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = b.Flush()
+		case <-b.wake:
+			_ = b.Flush()
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+// InsertLogEntry buffers the entry for the background goroutine to insert once the entries buffered before it are
+// inserted. A nil error means the entry is buffered, not that it is stored yet; OnInsert reports when it is. It only
+// returns an error when the buffer is full and the entry is dropped.
+func (b *RetryBuffer) InsertLogEntry(entry *LogEntry) error {
+	return b.InsertLogEntryContext(context.Background(), entry)
+}
+
+// InsertLogEntryContext is InsertLogEntry inserting the entry into the store with the values of ctx. The entry is
+// inserted after ctx may be done, so its cancellation is not kept.
+func (b *RetryBuffer) InsertLogEntryContext(ctx context.Context, entry *LogEntry) error {
+	b.mu.Lock()
+	if len(b.entries) >= b.options.MaxEntries {
+		b.mu.Unlock()
+		return fmt.Errorf("log retry buffer is full")
+	}
+	b.entries = append(b.entries, bufferedLogEntry{ctx: context.WithoutCancel(ctx), entry: entry})
+	b.mu.Unlock()
+
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Flush inserts the buffered entries in order, stopping at the first failure. Flushes run one at a time, but the
+// buffer stays available to new entries while the store is called. The background goroutine flushes the buffer on its
+// own; calling Flush waits for the store.
+func (b *RetryBuffer) Flush() error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+	for {
+		b.mu.Lock()
+		if len(b.entries) == 0 {
+			b.mu.Unlock()
+			return nil
+		}
+		buffered := b.entries[0]
+		b.mu.Unlock()
+
+		if err := AdaptLogStore(b.Store).InsertLogEntryContext(buffered.ctx, buffered.entry); err != nil {
+			return err
+		}
+
+		b.mu.Lock()
+		b.entries[0] = bufferedLogEntry{}
+		b.entries = b.entries[1:]
+		b.mu.Unlock()
+		if b.options.OnInsert != nil {
+			b.options.OnInsert(buffered.entry)
+		}
+	}
+}
+
+// Len returns the number of buffered entries.
+func (b *RetryBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.entries)
+}
+
+// Close stops the background retries and makes a last attempt to insert the buffered entries.
+func (b *RetryBuffer) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.stop)
+	})
+	<-b.done
+
+	if err := b.Flush(); err != nil {
+		return fmt.Errorf("%d log entries could not be inserted: %w", b.Len(), err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+type flakyLogStore struct {
+	*InMemoryLogStore
+	mu      sync.Mutex
+	failing bool
+}
+
+func (s *flakyLogStore) InsertLogEntry(entry *LogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failing {
+		return errors.New("store unavailable")
+	}
+	return s.InMemoryLogStore.InsertLogEntry(entry)
+}
+
+func (s *flakyLogStore) setFailing(failing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing = failing
+}
+
+func TestRetryBuffer(t *testing.T) {
+	store := &flakyLogStore{InMemoryLogStore: NewInMemoryLogStore(100), failing: true}
+	buffer := NewRetryBuffer(store, RetryBufferOptions{MaxEntries: 3, Interval: time.Hour})
+	t.Cleanup(func() { _ = buffer.Close() })
+
+	for i := 0; i < 3; i++ {
+		if err := buffer.InsertLogEntry(&LogEntry{ID: fmt.Sprint(i)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := buffer.InsertLogEntry(&LogEntry{ID: "3"}); err == nil {
+		t.Error("expected an error once the buffer is full")
+	}
+	if buffer.Len() != 3 {
+		t.Fatalf("expected 3 buffered entries, got %d", buffer.Len())
+	}
+
+	store.setFailing(false)
+	if err := buffer.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := buffer.InsertLogEntry(&LogEntry{ID: "4"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := buffer.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, _ := store.GetLogEntries()
+	if buffer.Len() != 0 || len(entries) != 4 || entries[0].ID != "4" || entries[3].ID != "0" {
+		t.Errorf("expected the buffered entries to be inserted first, got %v", entries)
+	}
+}
+
+func TestRetryBuffer_BackgroundRetry(t *testing.T) {
+	store := &flakyLogStore{InMemoryLogStore: NewInMemoryLogStore(100), failing: true}
+	buffer := NewRetryBuffer(store, RetryBufferOptions{Interval: time.Millisecond})
+	_ = buffer.InsertLogEntry(&LogEntry{ID: "1"})
+
+	store.setFailing(false)
+	deadline := time.Now().Add(time.Second)
+	for buffer.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if buffer.Len() != 0 {
+		t.Error("expected the entry to be retried in the background")
+	}
+
+	store.setFailing(true)
+	_ = buffer.InsertLogEntry(&LogEntry{ID: "2"})
+	if err := buffer.Close(); err == nil {
+		t.Error("expected Close to report the entries left")
+	}
+}
+
+type blockingLogStore struct {
+	*InMemoryLogStore
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingLogStore) InsertLogEntry(entry *LogEntry) error {
+	s.entered <- struct{}{}
+	<-s.release
+	return s.InMemoryLogStore.InsertLogEntry(entry)
+}
+
+func TestRetryBuffer_UnlockedWhileInserting(t *testing.T) {
+	store := &blockingLogStore{InMemoryLogStore: NewInMemoryLogStore(100), entered: make(chan struct{}), release: make(chan struct{})}
+	buffer := NewRetryBuffer(store, RetryBufferOptions{Interval: time.Hour})
+
+	inserted := make(chan error)
+	go func() { inserted <- buffer.InsertLogEntry(&LogEntry{ID: "1"}) }()
+	<-store.entered
+
+	lengths := make(chan int)
+	go func() { lengths <- buffer.Len() }()
+	select {
+	case length := <-lengths:
+		if length != 1 {
+			t.Errorf("expected the entry being inserted to stay buffered, got %d", length)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the buffer not to be locked while the store inserts")
+	}
+
+	close(store.release)
+	if err := <-inserted; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := buffer.Close(); err != nil || buffer.Len() != 0 {
+		t.Errorf("expected the entry to be inserted, got %d buffered, %v", buffer.Len(), err)
+	}
+}
+
+func TestRetryBuffer_InsertDoesNotWaitForStore(t *testing.T) {
+	store := &blockingLogStore{InMemoryLogStore: NewInMemoryLogStore(100), entered: make(chan struct{}, 1), release: make(chan struct{})}
+	inserted := make(chan *LogEntry, 1)
+	buffer := NewRetryBuffer(store, RetryBufferOptions{Interval: time.Hour, OnInsert: func(entry *LogEntry) { inserted <- entry }})
+
+	done := make(chan error)
+	go func() { done <- buffer.InsertLogEntry(&LogEntry{ID: "1"}) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the entry to be buffered without waiting for the store")
+	}
+	select {
+	case <-inserted:
+		t.Fatal("expected the entry not to be reported before the store inserted it")
+	default:
+	}
+
+	<-store.entered
+	close(store.release)
+	select {
+	case entry := <-inserted:
+		if entry.ID != "1" {
+			t.Errorf("expected entry 1 to be reported, got %v", entry.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the inserted entry to be reported")
+	}
+	if err := buffer.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,307 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LogSink receives the log entries streamed by a LogStreamer.
+type LogSink interface {
+	WriteLogEntry(entry *LogEntry) error
+}
+
+// ContextLogSink is a LogSink whose deliveries can be interrupted. The LogStreamer cancels the context when it is
+// closed before every queued entry was delivered, so slow retries do not hold up shutdown.
+type ContextLogSink interface {
+	LogSink
+	WriteLogEntryContext(ctx context.Context, entry *LogEntry) error
+}
+
+// LogStreamerOptions configures a LogStreamer.
+type LogStreamerOptions struct {
+	// QueueSize is the number of entries each sink may have waiting for delivery. Defaults to 1000.
+	QueueSize int
+	// OnError is called when a sink fails to write an entry.
+	OnError func(sink LogSink, entry *LogEntry, err error)
+	// OnDrop is called when an entry is dropped because the queue of a sink is full, or because the streamer was closed
+	// before delivering it.
+	OnDrop func(sink LogSink, entry *LogEntry)
+	// CloseTimeout is how long Close waits for the queued entries to be delivered. Defaults to 30 seconds.
+	CloseTimeout time.Duration
+}
+
+// LogStreamer fans log entries out to sinks asynchronously. Each sink has its own bounded queue and worker, so a slow
+// sink neither blocks the caller nor delays the other sinks; entries arriving while its queue is full are dropped.
+type LogStreamer struct {
+	options LogStreamerOptions
+	sinks   []LogSink
+	queues  []chan *LogEntry
+
+	mu          sync.RWMutex
+	closed      bool
+	wg          sync.WaitGroup
+	ctx         context.Context
+	cancel      context.CancelFunc
+	undelivered atomic.Int64
+}
+
+// NewLogStreamer starts streaming to the sinks.
+func NewLogStreamer(options LogStreamerOptions, sinks ...LogSink) *LogStreamer {
+	if options.QueueSize <= 0 {
+		options.QueueSize = 1000
+	}
+	if options.CloseTimeout <= 0 {
+		options.CloseTimeout = 30 * time.Second
+	}
+	streamer := &LogStreamer{options: options, sinks: sinks, queues: make([]chan *LogEntry, len(sinks))}
+	streamer.ctx, streamer.cancel = context.WithCancel(context.Background())
+	for i, sink := range sinks {
+		queue := make(chan *LogEntry, options.QueueSize)
+		streamer.queues[i] = queue
+		streamer.wg.Add(1)
+		go streamer.deliver(sink, queue)
+	}
+	return streamer
+}
+
+func (s *LogStreamer) deliver(sink LogSink, queue chan *LogEntry) {
+	defer s.wg.Done()
+	contextSink, _ := sink.(ContextLogSink)
+	for entry := range queue {
+		if s.ctx.Err() != nil {
+			s.undelivered.Add(1)
+			if s.options.OnDrop != nil {
+				s.options.OnDrop(sink, entry)
+			}
+			continue
+		}
+
+		var err error
+		if contextSink != nil {
+			err = contextSink.WriteLogEntryContext(s.ctx, entry)
+		} else {
+			err = sink.WriteLogEntry(entry)
+		}
+		if err != nil && s.ctx.Err() != nil {
+			s.undelivered.Add(1)
+		}
+		if err != nil && s.options.OnError != nil {
+			s.options.OnError(sink, entry, err)
+		}
+	}
+}
+
+// Stream queues a copy of the entry for every sink without blocking. It reports false when the entry was dropped for
+// any sink, or when the streamer is closed.
+func (s *LogStreamer) Stream(entry *LogEntry) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return false
+	}
+
+	queued := true
+	for i, queue := range s.queues {
+		copied := *entry
+		select {
+		case queue <- &copied:
+		default:
+			queued = false
+			if s.options.OnDrop != nil {
+				s.options.OnDrop(s.sinks[i], entry)
+			}
+		}
+	}
+	return queued
+}
+
+// Close stops accepting entries and waits up to CloseTimeout for the queued ones to be delivered, like CloseContext.
+func (s *LogStreamer) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.options.CloseTimeout)
+	defer cancel()
+	return s.CloseContext(ctx)
+}
+
+// CloseContext stops accepting entries and waits for the queued ones to be delivered until ctx is done. Deliveries
+// still running then are interrupted and reported to OnError, and the entries still queued are reported to OnDrop.
+// It returns an error when some entries were not delivered.
+func (s *LogStreamer) CloseContext(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	for _, queue := range s.queues {
+		close(queue)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	defer s.cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+	s.cancel()
+	<-done
+	if n := s.undelivered.Load(); n > 0 {
+		return fmt.Errorf("log streamer closed before delivering %d entries: %w", n, ctx.Err())
+	}
+	return nil
+}
+
+// SlogSink writes log entries as records of a slog.Handler.
+type SlogSink struct {
+	Handler slog.Handler
+	Level   slog.Level
+}
+
+// NewSlogSink creates a sink writing entries to the handler at the info level.
+func NewSlogSink(handler slog.Handler) *SlogSink {
+	return &SlogSink{Handler: handler, Level: slog.LevelInfo}
+}
+
+func (s *SlogSink) WriteLogEntry(entry *LogEntry) error {
+	ctx := context.Background()
+	if !s.Handler.Enabled(ctx, s.Level) {
+		return nil
+	}
+	record := slog.NewRecord(entry.ActionTime, s.Level, "admin log entry", 0)
+	record.AddAttrs(
+		slog.String("id", canonicalLogValue(entry.ID)),
+		slog.String("action", string(entry.ActionFlag)),
+		slog.String("user_id", canonicalLogValue(entry.UserID)),
+		slog.String("user", entry.UserRepr),
+		slog.String("content_type", entry.ContentType),
+		slog.String("object_id", canonicalLogValue(entry.ObjectID)),
+		slog.String("object", entry.ObjectRepr),
+		slog.String("message", entry.Message),
+	)
+	return s.Handler.Handle(ctx, record)
+}
+
+// WriterSink writes log entries to an io.Writer as JSON lines, in the format of the JSONL log store.
+type WriterSink struct {
+	Writer io.Writer
+
+	mu sync.Mutex
+}
+
+// NewWriterSink creates a sink writing entries to the writer.
+func NewWriterSink(writer io.Writer) *WriterSink {
+	return &WriterSink{Writer: writer}
+}
+
+func (s *WriterSink) WriteLogEntry(entry *LogEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.Writer.Write(append(line, '\n'))
+	return err
+}
+
+// WebhookSink posts log entries as JSON to an HTTP endpoint. Failed deliveries are retried with exponential backoff
+// when the request fails or the endpoint answers with a 429 or 5xx status.
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+	// Headers are added to every request, for example to authenticate with the endpoint.
+	Headers http.Header
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// Backoff is the delay before the first retry. It doubles with every retry up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// NewWebhookSink creates a sink posting entries to the URL, retrying 3 times from a 500ms backoff.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		URL:        url,
+		Client:     &http.Client{Timeout: 10 * time.Second},
+		MaxRetries: 3,
+		Backoff:    500 * time.Millisecond,
+		MaxBackoff: 30 * time.Second,
+	}
+}
+
+func (s *WebhookSink) WriteLogEntry(entry *LogEntry) error {
+	return s.WriteLogEntryContext(context.Background(), entry)
+}
+
+// WriteLogEntryContext posts the entry, giving up on the request and the retries when ctx is done.
+func (s *WebhookSink) WriteLogEntryContext(ctx context.Context, entry *LogEntry) error {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	backoff := s.Backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= s.MaxRetries || ctx.Err() != nil {
+			return fmt.Errorf("webhook delivery failed after %d attempts: %w", attempt+1, err)
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("webhook delivery abandoned after %d attempts: %w", attempt+1, ctx.Err())
+		}
+		backoff *= 2
+		if s.MaxBackoff > 0 && backoff > s.MaxBackoff {
+			backoff = s.MaxBackoff
+		}
+	}
+}
+
+func (s *WebhookSink) post(ctx context.Context, body []byte) (retry bool, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for key, values := range s.Headers {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return true, err
+	}
+	_, _ = io.Copy(io.Discard, response.Body)
+	_ = response.Body.Close()
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("unexpected status %d", response.StatusCode)
+	return response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500, err
+}
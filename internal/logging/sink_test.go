@@ -0,0 +1,227 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type blockingSink struct {
+	release chan struct{}
+	mu      sync.Mutex
+	written []string
+}
+
+func (s *blockingSink) WriteLogEntry(entry *LogEntry) error {
+	<-s.release
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.written = append(s.written, entry.ID.(string))
+	return nil
+}
+
+type failingSink struct{}
+
+func (failingSink) WriteLogEntry(*LogEntry) error {
+	return errors.New("unavailable")
+}
+
+func newTestWebhookSink(url string) *WebhookSink {
+	sink := NewWebhookSink(url)
+	sink.Backoff = time.Millisecond
+	return sink
+}
+
+func TestLogStreamer_SlowSinkDoesNotBlock(t *testing.T) {
+	slow := &blockingSink{release: make(chan struct{})}
+	var buffer bytes.Buffer
+	writer := NewWriterSink(&buffer)
+	var mu sync.Mutex
+	dropped := make(map[LogSink]int)
+	var failed atomic.Int32
+	streamer := NewLogStreamer(LogStreamerOptions{
+		QueueSize: 2,
+		OnDrop: func(sink LogSink, _ *LogEntry) {
+			mu.Lock()
+			defer mu.Unlock()
+			dropped[sink]++
+		},
+		OnError: func(LogSink, *LogEntry, error) { failed.Add(1) },
+	}, slow, writer, failingSink{})
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			streamer.Stream(&LogEntry{ID: string(rune('a' + i))})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected streaming not to wait for the slow sink")
+	}
+
+	close(slow.release)
+	_ = streamer.Close()
+	if streamer.Stream(&LogEntry{ID: "z"}) {
+		t.Error("expected a closed streamer to refuse entries")
+	}
+
+	if len(slow.written) < 2 || len(slow.written) > 3 || len(slow.written)+dropped[slow] != 5 {
+		t.Errorf("expected the slow sink to drop the entries beyond its queue, got %v with %d dropped", slow.written, dropped[slow])
+	}
+	if lines := strings.Count(buffer.String(), "\n"); lines+dropped[writer] != 5 {
+		t.Errorf("expected every queued entry to reach the writer sink, got %d lines and %d dropped", lines, dropped[writer])
+	}
+	if int(failed.Load())+dropped[failingSink{}] != 5 {
+		t.Errorf("expected an error for every queued entry of the failing sink, got %d", failed.Load())
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	var buffer bytes.Buffer
+	entry := &LogEntry{ID: "1", ActionTime: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), ActionFlag: LogStoreLevelDelete, ObjectRepr: "Order 1"}
+	if err := NewWriterSink(&buffer).WriteLogEntry(entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded LogEntry
+	if err := json.Unmarshal(buffer.Bytes(), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.ID != "1" || decoded.ActionFlag != LogStoreLevelDelete || decoded.ObjectRepr != "Order 1" || !decoded.ActionTime.Equal(entry.ActionTime) {
+		t.Errorf("unexpected entry %+v", decoded)
+	}
+}
+
+func TestSlogSink(t *testing.T) {
+	var buffer bytes.Buffer
+	entry := &LogEntry{ID: "1", ActionTime: time.Now(), UserID: uint(7), UserRepr: "alice", ActionFlag: LogStoreLevelUpdate, Message: "renamed"}
+	if err := NewSlogSink(slog.NewJSONHandler(&buffer, nil)).WriteLogEntry(entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record["action"] != "update" || record["user_id"] != "7" || record["user"] != "alice" || record["message"] != "renamed" {
+		t.Errorf("unexpected record %v", record)
+	}
+
+	buffer.Reset()
+	sink := NewSlogSink(slog.NewJSONHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	if err := sink.WriteLogEntry(entry); err != nil || buffer.Len() != 0 {
+		t.Errorf("expected disabled levels to be skipped, got %q, %v", buffer.String(), err)
+	}
+}
+
+func TestWebhookSink_Retries(t *testing.T) {
+	var attempts atomic.Int32
+	var received LogEntry
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink := newTestWebhookSink(server.URL)
+	sink.Headers = http.Header{"Authorization": {"Bearer secret"}}
+	if err := sink.WriteLogEntry(&LogEntry{ID: "1", ActionFlag: LogStoreLevelCreate}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts.Load() != 3 || received.ID != "1" || received.ActionFlag != LogStoreLevelCreate {
+		t.Errorf("expected delivery on the third attempt, got %d attempts and %+v", attempts.Load(), received)
+	}
+}
+
+func TestWebhookSink_GivesUp(t *testing.T) {
+	var attempts atomic.Int32
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := newTestWebhookSink(server.URL)
+	if err := sink.WriteLogEntry(&LogEntry{ID: "1"}); err == nil || attempts.Load() != 4 {
+		t.Errorf("expected an error after 4 attempts, got %v after %d", err, attempts.Load())
+	}
+
+	attempts.Store(0)
+	status = http.StatusBadRequest
+	if err := sink.WriteLogEntry(&LogEntry{ID: "1"}); err == nil || attempts.Load() != 1 {
+		t.Errorf("expected client errors not to be retried, got %v after %d attempts", err, attempts.Load())
+	}
+}
+
+func TestLogStreamer_Webhook(t *testing.T) {
+	var mu sync.Mutex
+	ids := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entry LogEntry
+		_ = json.NewDecoder(r.Body).Decode(&entry)
+		mu.Lock()
+		ids = append(ids, entry.ID.(string))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	streamer := NewLogStreamer(LogStreamerOptions{}, newTestWebhookSink(server.URL))
+	for _, id := range []string{"1", "2", "3"} {
+		streamer.Stream(&LogEntry{ID: id})
+	}
+	_ = streamer.Close()
+	if strings.Join(ids, ",") != "1,2,3" {
+		t.Errorf("expected the entries to be delivered in order, got %v", ids)
+	}
+}
+
+func TestLogStreamer_CloseInterruptsRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL)
+	sink.Backoff = time.Hour
+	var dropped, failed atomic.Int32
+	streamer := NewLogStreamer(LogStreamerOptions{
+		CloseTimeout: 50 * time.Millisecond,
+		OnDrop:       func(LogSink, *LogEntry) { dropped.Add(1) },
+		OnError:      func(LogSink, *LogEntry, error) { failed.Add(1) },
+	}, sink)
+	for _, id := range []string{"1", "2", "3"} {
+		streamer.Stream(&LogEntry{ID: id})
+	}
+
+	start := time.Now()
+	err := streamer.Close()
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected closing to interrupt the retries, took %v", elapsed)
+	}
+	if err == nil || !strings.Contains(err.Error(), "3 entries") {
+		t.Errorf("expected an error reporting 3 undelivered entries, got %v", err)
+	}
+	if failed.Load() != 1 || dropped.Load() != 2 {
+		t.Errorf("expected the interrupted delivery to fail and the queued entries to be dropped, got %d failed and %d dropped", failed.Load(), dropped.Load())
+	}
+}
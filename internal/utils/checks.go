@@ -6,3 +6,12 @@ func MinInt(a, b int) int {
 	}
 	return b
 }
+
+func ContainsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
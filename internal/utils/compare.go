@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// CompareValues compares two values of the same kind and returns -1, 0 or 1. Nil values and nil pointers sort before
+// any other value. Values of unsupported kinds are compared by their string representation.
+func CompareValues(a, b interface{}) int {
+	aVal, aNil := dereference(reflect.ValueOf(a))
+	bVal, bNil := dereference(reflect.ValueOf(b))
+
+	switch {
+	case aNil && bNil:
+		return 0
+	case aNil:
+		return -1
+	case bNil:
+		return 1
+	}
+
+	if aTime, ok := aVal.Interface().(time.Time); ok {
+		if bTime, ok := bVal.Interface().(time.Time); ok {
+			return compareOrdered(aTime.Before(bTime), aTime.After(bTime))
+		}
+	}
+
+	if aVal.Kind() == bVal.Kind() {
+		switch aVal.Kind() {
+		case reflect.String:
+			return strings.Compare(aVal.String(), bVal.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return compareOrdered(aVal.Int() < bVal.Int(), aVal.Int() > bVal.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return compareOrdered(aVal.Uint() < bVal.Uint(), aVal.Uint() > bVal.Uint())
+		case reflect.Float32, reflect.Float64:
+			return compareOrdered(aVal.Float() < bVal.Float(), aVal.Float() > bVal.Float())
+		case reflect.Bool:
+			return compareOrdered(!aVal.Bool() && bVal.Bool(), aVal.Bool() && !bVal.Bool())
+		}
+	}
+
+	return strings.Compare(fmt.Sprint(aVal.Interface()), fmt.Sprint(bVal.Interface()))
+}
+
+func dereference(val reflect.Value) (reflect.Value, bool) {
+	for val.IsValid() && (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) {
+		if val.IsNil() {
+			return val, true
+		}
+		val = val.Elem()
+	}
+	return val, !val.IsValid()
+}
+
+func compareOrdered(less, greater bool) int {
+	if less {
+		return -1
+	}
+	if greater {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompareValues(t *testing.T) {
+	name := "b"
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		a        interface{}
+		b        interface{}
+		expected int
+	}{
+		{"Strings Less", "a", "b", -1},
+		{"Strings Equal", "a", "a", 0},
+		{"Ints Greater", 10, 2, 1},
+		{"Uints Less", uint(1), uint(2), -1},
+		{"Floats Equal", 1.5, 1.5, 0},
+		{"Bools Less", false, true, -1},
+		{"Times Greater", now.Add(time.Hour), now, 1},
+		{"Pointer And Value", &name, "a", 1},
+		{"Nil First", nil, "a", -1},
+		{"Nil Pointer First", (*string)(nil), &name, -1},
+		{"Both Nil", nil, (*string)(nil), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := CompareValues(tt.a, tt.b); result != tt.expected {
+				t.Errorf("CompareValues(%v, %v) = %d; expected %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
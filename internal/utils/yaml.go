@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var plainYAMLKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// MarshalYAML encodes the value as YAML. The value is first encoded as JSON, so json struct tags apply, and object keys
+// are written in sorted order.
+func MarshalYAML(value interface{}) ([]byte, error) {
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+	var generic interface{}
+	if err = decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writeYAMLValue(&buf, generic, 0)
+	return buf.Bytes(), nil
+}
+
+func writeYAMLValue(buf *bytes.Buffer, value interface{}, indent int) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			buf.WriteString("{}\n")
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteString(strings.Repeat("  ", indent))
+			}
+			buf.WriteString(yamlKey(key))
+			buf.WriteString(":")
+			writeYAMLChild(buf, v[key], indent+1)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString("[]\n")
+			return
+		}
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteString(strings.Repeat("  ", indent))
+			}
+			buf.WriteString("- ")
+			writeYAMLValue(buf, item, indent+1)
+		}
+	default:
+		buf.WriteString(yamlScalar(v))
+		buf.WriteString("\n")
+	}
+}
+
+func writeYAMLChild(buf *bytes.Buffer, value interface{}, indent int) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) > 0 {
+			buf.WriteString("\n")
+			buf.WriteString(strings.Repeat("  ", indent))
+			writeYAMLValue(buf, v, indent)
+			return
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			buf.WriteString("\n")
+			buf.WriteString(strings.Repeat("  ", indent))
+			writeYAMLValue(buf, v, indent)
+			return
+		}
+	}
+	buf.WriteString(" ")
+	writeYAMLValue(buf, value, indent)
+}
+
+func yamlKey(key string) string {
+	if plainYAMLKey.MatchString(key) {
+		return key
+	}
+	return yamlScalar(key)
+}
+
+func yamlScalar(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case json.Number:
+		return v.String()
+	case string:
+		quoted, _ := json.Marshal(v)
+		return string(quoted)
+	default:
+		quoted, _ := json.Marshal(v)
+		return string(quoted)
+	}
+}
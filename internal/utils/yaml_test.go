@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestMarshalYAML(t *testing.T) {
+	value := map[string]interface{}{
+		"openapi": "3.0.3",
+		"paths": map[string]interface{}{
+			"/items/{id}": map[string]interface{}{"$ref": "#/item"},
+		},
+		"tags":     []interface{}{"a", map[string]interface{}{"name": "b", "count": 2}, []interface{}{}},
+		"empty":    map[string]interface{}{},
+		"nullable": nil,
+		"enabled":  true,
+		"text":     "line\nbreak: \"quoted\"",
+	}
+
+	expected := `empty: {}
+enabled: true
+nullable: null
+openapi: "3.0.3"
+paths:
+  "/items/{id}":
+    "$ref": "#/item"
+tags:
+  - "a"
+  - count: 2
+    name: "b"
+  - []
+text: "line\nbreak: \"quoted\""
+`
+
+	got, err := MarshalYAML(value)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got) != expected {
+		t.Errorf("unexpected YAML:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+func TestMarshalYAML_Struct(t *testing.T) {
+	value := struct {
+		Name  string   `json:"name"`
+		Items []string `json:"items,omitempty"`
+	}{Name: "test"}
+
+	got, err := MarshalYAML(value)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got) != "name: \"test\"\n" {
+		t.Errorf("unexpected YAML: %q", got)
+	}
+}
@@ -0,0 +1,388 @@
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/adminpanel"
+	"reflect"
+	"strings"
+)
+
+// RoleRecord is the admin panel representation of a Role. Grants are written as a comma separated list.
+type RoleRecord struct {
+	Name        string `admin:"required;regex:^[A-Za-z0-9_.-]+$;editForm:exclude"`
+	Description string `admin:"listDisplay:exclude"`
+	Grants      string `admin:"search:exclude"`
+}
+
+// AdminName returns the name of the role model in the admin panel.
+func (r *RoleRecord) AdminName() string { return "Role" }
+
+// AdminInstanceRepr returns the name of the role.
+func (r *RoleRecord) AdminInstanceRepr() string { return r.Name }
+
+// GroupRecord is the admin panel representation of a Group. Roles are written as a comma separated list of names.
+type GroupRecord struct {
+	Name        string `admin:"required;regex:^[A-Za-z0-9_.-]+$;editForm:exclude"`
+	Description string `admin:"listDisplay:exclude"`
+	Roles       string
+}
+
+// AdminName returns the name of the group model in the admin panel.
+func (g *GroupRecord) AdminName() string { return "Group" }
+
+// AdminInstanceRepr returns the name of the group.
+func (g *GroupRecord) AdminInstanceRepr() string { return g.Name }
+
+// UserRecord is the admin panel representation of a User. Roles and groups are written as comma separated lists of
+// names.
+type UserRecord struct {
+	ID        string `admin:"required;regex:^[A-Za-z0-9_.@-]+$;editForm:exclude;displayName:User ID"`
+	Superuser bool   `admin:"filter"`
+	Roles     string
+	Groups    string
+}
+
+// AdminName returns the name of the user model in the admin panel.
+func (u *UserRecord) AdminName() string { return "User" }
+
+// AdminInstanceRepr returns the ID of the user.
+func (u *UserRecord) AdminInstanceRepr() string { return u.ID }
+
+// RegisterAdminApp registers an admin app letting superusers manage the roles, groups and users of the store through
+// the panel. The panel must use a PermissionFunc built on the same store so that only superusers can access it.
+func RegisterAdminApp(panel *adminpanel.AdminPanel, store Store) (*adminpanel.App, error) {
+	app, err := panel.RegisterApp(AdminAppName, "Access Control", &StoreORMIntegrator{Store: store})
+	if err != nil {
+		return nil, err
+	}
+
+	roleModel, err := app.RegisterModel(&RoleRecord{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	groupModel, err := app.RegisterModel(&GroupRecord{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	userModel, err := app.RegisterModel(&UserRecord{}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	validateGrants := func(value interface{}) ([]error, error) {
+		if s, ok := value.(string); ok {
+			if _, err := ParseGrants(s); err != nil {
+				return []error{err}, nil
+			}
+		}
+		return nil, nil
+	}
+	validateRoles := func(value interface{}) ([]error, error) {
+		return validateNames(value, func(name string) error {
+			_, err := store.GetRole(name)
+			return err
+		}, "role")
+	}
+	validateGroups := func(value interface{}) ([]error, error) {
+		return validateNames(value, func(name string) error {
+			_, err := store.GetGroup(name)
+			return err
+		}, "group")
+	}
+
+	registerValidation(roleModel, "Grants", validateGrants)
+	registerValidation(groupModel, "Roles", validateRoles)
+	registerValidation(userModel, "Roles", validateRoles)
+	registerValidation(userModel, "Groups", validateGroups)
+	return app, nil
+}
+
+func registerValidation(model *adminpanel.Model, fieldName string, validation func(interface{}) ([]error, error)) {
+	fieldConfig, ok := model.GetFieldConfig(fieldName)
+	if !ok {
+		return
+	}
+	if fieldConfig.AddFormField != nil {
+		fieldConfig.AddFormField.RegisterValidationFunctions(validation)
+	}
+	if fieldConfig.EditFormField != nil && fieldConfig.EditFormField != fieldConfig.AddFormField {
+		fieldConfig.EditFormField.RegisterValidationFunctions(validation)
+	}
+}
+
+func validateNames(value interface{}, lookup func(string) error, kind string) ([]error, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, nil
+	}
+	var errs []error
+	for _, name := range splitNames(s) {
+		err := lookup(name)
+		if errors.Is(err, ErrNotFound) {
+			errs = append(errs, fmt.Errorf("%s '%s' does not exist", kind, name))
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return errs, nil
+}
+
+func splitNames(s string) []string {
+	names := make([]string, 0)
+	for _, part := range strings.Split(s, ",") {
+		if name := strings.TrimSpace(part); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func newRoleRecord(role *Role) *RoleRecord {
+	return &RoleRecord{Name: role.Name, Description: role.Description, Grants: FormatGrants(role.Grants)}
+}
+
+func (r *RoleRecord) toRole() (*Role, error) {
+	grants, err := ParseGrants(r.Grants)
+	if err != nil {
+		return nil, err
+	}
+	return &Role{Name: r.Name, Description: r.Description, Grants: grants}, nil
+}
+
+func newGroupRecord(group *Group) *GroupRecord {
+	return &GroupRecord{Name: group.Name, Description: group.Description, Roles: strings.Join(group.Roles, ", ")}
+}
+
+func (g *GroupRecord) toGroup() *Group {
+	return &Group{Name: g.Name, Description: g.Description, Roles: splitNames(g.Roles)}
+}
+
+func newUserRecord(user *User) *UserRecord {
+	return &UserRecord{ID: user.ID, Superuser: user.Superuser, Roles: strings.Join(user.Roles, ", "), Groups: strings.Join(user.Groups, ", ")}
+}
+
+func (u *UserRecord) toUser() *User {
+	return &User{ID: u.ID, Superuser: u.Superuser, Roles: splitNames(u.Roles), Groups: splitNames(u.Groups)}
+}
+
+// StoreORMIntegrator is an ORMIntegrator exposing the roles, groups and users of a Store as RoleRecord, GroupRecord
+// and UserRecord instances.
+type StoreORMIntegrator struct {
+	Store Store
+}
+
+// GetPrimaryKeyValue returns the name of roles and groups and the ID of users.
+func (o *StoreORMIntegrator) GetPrimaryKeyValue(instance interface{}) (interface{}, error) {
+	switch record := instance.(type) {
+	case *RoleRecord:
+		return record.Name, nil
+	case *GroupRecord:
+		return record.Name, nil
+	case *UserRecord:
+		return record.ID, nil
+	}
+	return nil, fmt.Errorf("unsupported model %T", instance)
+}
+
+// GetPrimaryKeyField returns the Name field of roles and groups and the ID field of users.
+func (o *StoreORMIntegrator) GetPrimaryKeyField(model interface{}) (string, error) {
+	switch model.(type) {
+	case *RoleRecord, *GroupRecord:
+		return "Name", nil
+	case *UserRecord:
+		return "ID", nil
+	}
+	return "", fmt.Errorf("unsupported model %T", model)
+}
+
+// GetPrimaryKeyType returns the string type, used by every record.
+func (o *StoreORMIntegrator) GetPrimaryKeyType(interface{}) (reflect.Type, error) {
+	return reflect.TypeOf(""), nil
+}
+
+// FetchInstances retrieves every record of the model.
+func (o *StoreORMIntegrator) FetchInstances(model interface{}) (interface{}, error) {
+	switch model.(type) {
+	case *RoleRecord:
+		roles, err := o.Store.ListRoles()
+		if err != nil {
+			return nil, err
+		}
+		records := make([]*RoleRecord, len(roles))
+		for i, role := range roles {
+			records[i] = newRoleRecord(role)
+		}
+		return records, nil
+	case *GroupRecord:
+		groups, err := o.Store.ListGroups()
+		if err != nil {
+			return nil, err
+		}
+		records := make([]*GroupRecord, len(groups))
+		for i, group := range groups {
+			records[i] = newGroupRecord(group)
+		}
+		return records, nil
+	case *UserRecord:
+		users, err := o.Store.ListUsers()
+		if err != nil {
+			return nil, err
+		}
+		records := make([]*UserRecord, len(users))
+		for i, user := range users {
+			records[i] = newUserRecord(user)
+		}
+		return records, nil
+	}
+	return nil, fmt.Errorf("unsupported model %T", model)
+}
+
+// FetchInstancesOnlyFields retrieves every record of the model. Records are small, so every field is returned.
+func (o *StoreORMIntegrator) FetchInstancesOnlyFields(model interface{}, _ []string) (interface{}, error) {
+	return o.FetchInstances(model)
+}
+
+// FetchInstancesOnlyFieldWithSearch retrieves the records whose search fields contain the query, ignoring case.
+func (o *StoreORMIntegrator) FetchInstancesOnlyFieldWithSearch(model interface{}, _ []string, query string, searchFields []string) (interface{}, error) {
+	records, err := o.FetchInstances(model)
+	if err != nil {
+		return nil, err
+	}
+	query = strings.ToLower(query)
+	recordsVal := reflect.ValueOf(records)
+	matching := reflect.MakeSlice(recordsVal.Type(), 0, recordsVal.Len())
+	for i := 0; i < recordsVal.Len(); i++ {
+		record := recordsVal.Index(i)
+		for _, field := range searchFields {
+			value := record.Elem().FieldByName(field)
+			if value.IsValid() && strings.Contains(strings.ToLower(fmt.Sprint(value.Interface())), query) {
+				matching = reflect.Append(matching, record)
+				break
+			}
+		}
+	}
+	return matching.Interface(), nil
+}
+
+// DeleteInstance deletes the record with the given primary key.
+func (o *StoreORMIntegrator) DeleteInstance(model interface{}, id interface{}) error {
+	key := fmt.Sprint(id)
+	switch model.(type) {
+	case *RoleRecord:
+		return o.Store.DeleteRole(key)
+	case *GroupRecord:
+		return o.Store.DeleteGroup(key)
+	case *UserRecord:
+		return o.Store.DeleteUser(key)
+	}
+	return fmt.Errorf("unsupported model %T", model)
+}
+
+// FetchInstanceOnlyFields retrieves the record with the given primary key.
+func (o *StoreORMIntegrator) FetchInstanceOnlyFields(model interface{}, id interface{}, _ []string) (interface{}, error) {
+	return o.FetchInstance(model, id)
+}
+
+// FetchInstance retrieves the record with the given primary key. It returns nil when the record does not exist.
+func (o *StoreORMIntegrator) FetchInstance(model interface{}, id interface{}) (interface{}, error) {
+	key := fmt.Sprint(id)
+	var record interface{}
+	var err error
+	switch model.(type) {
+	case *RoleRecord:
+		var role *Role
+		if role, err = o.Store.GetRole(key); err == nil {
+			record = newRoleRecord(role)
+		}
+	case *GroupRecord:
+		var group *Group
+		if group, err = o.Store.GetGroup(key); err == nil {
+			record = newGroupRecord(group)
+		}
+	case *UserRecord:
+		var user *User
+		if user, err = o.Store.GetUser(key); err == nil {
+			record = newUserRecord(user)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported model %T", model)
+	}
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return record, err
+}
+
+// CreateInstance saves a new record. It fails when a record with the same primary key already exists.
+func (o *StoreORMIntegrator) CreateInstance(instance interface{}) error {
+	id, err := o.GetPrimaryKeyValue(instance)
+	if err != nil {
+		return err
+	}
+	existing, err := o.FetchInstance(instance, id)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("'%v' already exists", id)
+	}
+	return o.save(instance)
+}
+
+// UpdateInstance replaces the record with the given primary key.
+func (o *StoreORMIntegrator) UpdateInstance(instance interface{}, primaryKey interface{}) error {
+	return o.UpdateInstanceOnlyFields(instance, nil, primaryKey)
+}
+
+// CreateInstanceOnlyFields saves a new record. Fields left out keep their zero value.
+func (o *StoreORMIntegrator) CreateInstanceOnlyFields(instance interface{}, _ []string) error {
+	return o.CreateInstance(instance)
+}
+
+// UpdateInstanceOnlyFields updates the given fields of the record with the given primary key. A nil list of fields
+// updates every field.
+func (o *StoreORMIntegrator) UpdateInstanceOnlyFields(instance interface{}, fields []string, primaryKey interface{}) error {
+	existing, err := o.FetchInstance(instance, primaryKey)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("'%v' does not exist", primaryKey)
+	}
+
+	existingVal := reflect.ValueOf(existing).Elem()
+	instanceVal := reflect.ValueOf(instance).Elem()
+	if fields == nil {
+		existingVal.Set(instanceVal)
+	}
+	for _, field := range fields {
+		existingVal.FieldByName(field).Set(instanceVal.FieldByName(field))
+	}
+
+	switch record := existing.(type) {
+	case *RoleRecord:
+		record.Name = fmt.Sprint(primaryKey)
+	case *GroupRecord:
+		record.Name = fmt.Sprint(primaryKey)
+	case *UserRecord:
+		record.ID = fmt.Sprint(primaryKey)
+	}
+	return o.save(existing)
+}
+
+func (o *StoreORMIntegrator) save(instance interface{}) error {
+	switch record := instance.(type) {
+	case *RoleRecord:
+		role, err := record.toRole()
+		if err != nil {
+			return err
+		}
+		return o.Store.SaveRole(role)
+	case *GroupRecord:
+		return o.Store.SaveGroup(record.toGroup())
+	case *UserRecord:
+		return o.Store.SaveUser(record.toUser())
+	}
+	return fmt.Errorf("unsupported model %T", instance)
+}
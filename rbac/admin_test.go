@@ -0,0 +1,149 @@
+package rbac
+
+import (
+	"github.com/go-advanced-admin/admin/internal/adminpanel"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func newTestAdmin(t *testing.T) (*adminpanel.HTTPWebIntegrator, *MemoryStore, *adminpanel.App) {
+	store := newTestStore(t)
+	subject := func(ctx interface{}) (string, error) {
+		return ctx.(*adminpanel.HTTPContext).Request.Header.Get("X-User"), nil
+	}
+	web := adminpanel.NewHTTPWebIntegrator(nil)
+	panel, err := adminpanel.NewAdminPanel(&StoreORMIntegrator{Store: store}, web, NewPermissionFunc(store, subject), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	app, err := RegisterAdminApp(panel, store)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return web, store, app
+}
+
+func serveTestRequest(web http.Handler, method, target, user string, form url.Values) *httptest.ResponseRecorder {
+	var req *http.Request
+	if form != nil {
+		req = httptest.NewRequest(method, target, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		req = httptest.NewRequest(method, target, nil)
+	}
+	req.Header.Set("X-User", user)
+	rec := httptest.NewRecorder()
+	web.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRegisterAdminApp_Access(t *testing.T) {
+	web, _, app := newTestAdmin(t)
+	roleLink := app.Models["Role"].GetFullLink()
+
+	rec := serveTestRequest(web, http.MethodGet, roleLink, "root", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for superuser, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "billing-reader") {
+		t.Error("expected role list to contain billing-reader")
+	}
+
+	rec = serveTestRequest(web, http.MethodGet, roleLink, "alice", nil)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for non-superuser, got %d", rec.Code)
+	}
+}
+
+func TestRegisterAdminApp_ManageRoles(t *testing.T) {
+	web, store, app := newTestAdmin(t)
+	roleModel := app.Models["Role"]
+
+	form := url.Values{"Name": {"auditor"}, "Description": {"Reads logs"}, "Grants": {"*:read, shop.Order#1:update"}}
+	rec := serveTestRequest(web, http.MethodPost, roleModel.GetFullLink()+"/add", "root", form)
+	if rec.Code != http.StatusSeeOther && rec.Code != http.StatusFound {
+		t.Fatalf("expected redirect after creating the role, got %d: %s", rec.Code, rec.Body.String())
+	}
+	role, err := store.GetRole("auditor")
+	if err != nil {
+		t.Fatalf("expected role to be created: %v", err)
+	}
+	if len(role.Grants) != 2 || role.Grants[1].Instance != "1" {
+		t.Errorf("unexpected grants %+v", role.Grants)
+	}
+
+	form = url.Values{"Description": {"Reads everything"}, "Grants": {"*:read"}}
+	rec = serveTestRequest(web, http.MethodPost, roleModel.GetFullLink()+"/auditor/edit", "root", form)
+	if rec.Code != http.StatusSeeOther && rec.Code != http.StatusFound {
+		t.Fatalf("expected redirect after editing the role, got %d: %s", rec.Code, rec.Body.String())
+	}
+	role, _ = store.GetRole("auditor")
+	if role.Description != "Reads everything" || len(role.Grants) != 1 {
+		t.Errorf("unexpected role after edit %+v", role)
+	}
+
+	form = url.Values{"Name": {"broken"}, "Grants": {"billing"}}
+	rec = serveTestRequest(web, http.MethodPost, roleModel.GetFullLink()+"/add", "root", form)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "invalid grant") {
+		t.Errorf("expected form error for invalid grant, got %d", rec.Code)
+	}
+	if _, err = store.GetRole("broken"); err == nil {
+		t.Error("role with an invalid grant should not be saved")
+	}
+}
+
+func TestRegisterAdminApp_ValidatesReferences(t *testing.T) {
+	web, store, app := newTestAdmin(t)
+
+	form := url.Values{"ID": {"carol"}, "Roles": {"billing-reader, ghost"}, "Groups": {"sales"}}
+	rec := serveTestRequest(web, http.MethodPost, app.Models["User"].GetFullLink()+"/add", "root", form)
+	if !strings.Contains(rec.Body.String(), "role &#39;ghost&#39; does not exist") {
+		t.Errorf("expected an unknown role error, got %d", rec.Code)
+	}
+	if _, err := store.GetUser("carol"); err == nil {
+		t.Error("user referencing an unknown role should not be saved")
+	}
+
+	form.Set("Roles", "billing-reader")
+	rec = serveTestRequest(web, http.MethodPost, app.Models["User"].GetFullLink()+"/add", "root", form)
+	user, err := store.GetUser("carol")
+	if err != nil {
+		t.Fatalf("expected user to be created, got %d: %v", rec.Code, err)
+	}
+	if len(user.Roles) != 1 || len(user.Groups) != 1 || user.Groups[0] != "sales" {
+		t.Errorf("unexpected user %+v", user)
+	}
+}
+
+func TestStoreORMIntegrator_Search(t *testing.T) {
+	orm := &StoreORMIntegrator{Store: newTestStore(t)}
+	result, err := orm.FetchInstancesOnlyFieldWithSearch(&UserRecord{}, nil, "ALI", []string{"ID"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	users := result.([]*UserRecord)
+	if len(users) != 1 || users[0].ID != "alice" {
+		t.Errorf("unexpected search result %+v", users)
+	}
+
+	if err = orm.CreateInstance(&UserRecord{ID: "alice"}); err == nil {
+		t.Error("expected an error when creating an existing user")
+	}
+	instance, err := orm.FetchInstance(&RoleRecord{}, "missing")
+	if err != nil || instance != nil {
+		t.Errorf("expected nil instance for missing role, got %v, %v", instance, err)
+	}
+}
+
+func TestStoreORMIntegrator_GetPrimaryKeyField(t *testing.T) {
+	_, _, app := newTestAdmin(t)
+	if field, err := app.Models["Role"].GetPrimaryKeyField(); err != nil || field != "Name" {
+		t.Errorf("expected roles to be keyed by name, got %q, %v", field, err)
+	}
+	if field, err := app.Models["User"].GetPrimaryKeyField(); err != nil || field != "ID" {
+		t.Errorf("expected users to be keyed by ID, got %q, %v", field, err)
+	}
+}
@@ -0,0 +1,153 @@
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/adminpanel"
+)
+
+// AdminAppName is the name of the admin app managing roles, groups and users. Only superusers may access it.
+const AdminAppName = "rbac"
+
+// SubjectFunc returns the ID of the user making the request. An empty ID denotes an anonymous user, who is never
+// granted anything.
+type SubjectFunc = func(ctx interface{}) (string, error)
+
+// SubjectFromUserFetcher creates a SubjectFunc from the panel's user fetcher, formatting the user ID as a string.
+func SubjectFromUserFetcher(fetcher adminpanel.UserFetchFunction) SubjectFunc {
+	return func(ctx interface{}) (string, error) {
+		userID, _, err := fetcher(ctx)
+		if err != nil || userID == nil {
+			return "", err
+		}
+		return fmt.Sprint(userID), nil
+	}
+}
+
+// Authorizer checks permission requests against the grants of the roles assigned to a user, directly or through
+// groups.
+type Authorizer struct {
+	Store   Store
+	Subject SubjectFunc
+}
+
+// NewAuthorizer creates a new Authorizer using the given store and subject function.
+func NewAuthorizer(store Store, subject SubjectFunc) *Authorizer {
+	return &Authorizer{Store: store, Subject: subject}
+}
+
+// NewPermissionFunc creates a PermissionFunc checking requests against the roles and grants of the given store.
+func NewPermissionFunc(store Store, subject SubjectFunc) adminpanel.PermissionFunc {
+	return NewAuthorizer(store, subject).PermissionFunc()
+}
+
+// PermissionFunc returns the authorizer as a PermissionFunc for the admin panel.
+func (a *Authorizer) PermissionFunc() adminpanel.PermissionFunc {
+	return func(request adminpanel.PermissionRequest, ctx interface{}) (bool, error) {
+		userID, err := a.Subject(ctx)
+		if err != nil {
+			return false, err
+		}
+		return a.Allowed(userID, request)
+	}
+}
+
+// Allowed reports whether the user is allowed to perform the request.
+func (a *Authorizer) Allowed(userID string, request adminpanel.PermissionRequest) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+	user, err := a.Store.GetUser(userID)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if user.Superuser {
+		return true, nil
+	}
+
+	grants, err := a.UserGrants(user)
+	if err != nil {
+		return false, err
+	}
+	return allows(user, grants, request), nil
+}
+
+// CheckPermissions implements adminpanel.BatchPermissionChecker, loading the user and their grants once for all the
+// requests.
+func (a *Authorizer) CheckPermissions(requests []adminpanel.PermissionRequest, ctx interface{}) ([]bool, error) {
+	results := make([]bool, len(requests))
+	userID, err := a.Subject(ctx)
+	if err != nil || userID == "" {
+		return results, err
+	}
+	user, err := a.Store.GetUser(userID)
+	if errors.Is(err, ErrNotFound) {
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	grants, err := a.UserGrants(user)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, request := range requests {
+		results[i] = allows(user, grants, request)
+	}
+	return results, nil
+}
+
+// allows reports whether the user holding the grants may perform the request. Only superusers may access the admin
+// app of the package.
+func allows(user *User, grants []Grant, request adminpanel.PermissionRequest) bool {
+	if user.Superuser {
+		return true
+	}
+	if request.AppName != nil && *request.AppName == AdminAppName {
+		return false
+	}
+	for _, grant := range grants {
+		if grant.Matches(request) {
+			return true
+		}
+	}
+	return false
+}
+
+// UserGrants returns the grants of every role assigned to the user, directly or through its groups. Missing roles and
+// groups are ignored.
+func (a *Authorizer) UserGrants(user *User) ([]Grant, error) {
+	roleNames := append([]string(nil), user.Roles...)
+	for _, groupName := range user.Groups {
+		group, err := a.Store.GetGroup(groupName)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		roleNames = append(roleNames, group.Roles...)
+	}
+
+	grants := make([]Grant, 0)
+	seen := make(map[string]bool)
+	for _, roleName := range roleNames {
+		if seen[roleName] {
+			continue
+		}
+		seen[roleName] = true
+		role, err := a.Store.GetRole(roleName)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		grants = append(grants, role.Grants...)
+	}
+	return grants, nil
+}
@@ -0,0 +1,111 @@
+package rbac
+
+import (
+	"errors"
+	"github.com/go-advanced-admin/admin/internal/adminpanel"
+	"testing"
+)
+
+func newTestStore(t *testing.T) *MemoryStore {
+	store := NewMemoryStore()
+	mustParse := func(s string) []Grant {
+		grants, err := ParseGrants(s)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return grants
+	}
+	_ = store.SaveRole(&Role{Name: "billing-reader", Grants: mustParse("billing.*:read")})
+	_ = store.SaveRole(&Role{Name: "order-editor", Grants: mustParse("shop.Order:read, shop.Order:update")})
+	_ = store.SaveGroup(&Group{Name: "sales", Roles: []string{"order-editor", "missing"}})
+	_ = store.SaveUser(&User{ID: "root", Superuser: true})
+	_ = store.SaveUser(&User{ID: "alice", Roles: []string{"billing-reader"}, Groups: []string{"sales", "missing"}})
+	_ = store.SaveUser(&User{ID: "bob"})
+	return store
+}
+
+func TestAuthorizer_Allowed(t *testing.T) {
+	authorizer := NewAuthorizer(newTestStore(t), nil)
+
+	tests := []struct {
+		name     string
+		user     string
+		request  adminpanel.PermissionRequest
+		expected bool
+	}{
+		{"anonymous", "", newRequest("billing", "", nil, adminpanel.ReadAction), false},
+		{"unknown user", "eve", newRequest("billing", "", nil, adminpanel.ReadAction), false},
+		{"superuser", "root", newRequest("shop", "Order", 1, adminpanel.DeleteAction), true},
+		{"superuser rbac app", "root", newRequest(AdminAppName, "Role", nil, adminpanel.UpdateAction), true},
+		{"direct role", "alice", newRequest("billing", "Invoice", 3, adminpanel.ReadAction), true},
+		{"direct role wrong action", "alice", newRequest("billing", "Invoice", 3, adminpanel.UpdateAction), false},
+		{"group role", "alice", newRequest("shop", "Order", 3, adminpanel.UpdateAction), true},
+		{"group role other model", "alice", newRequest("shop", "Product", nil, adminpanel.ReadAction), false},
+		{"rbac app needs superuser", "alice", newRequest(AdminAppName, "", nil, adminpanel.ReadAction), false},
+		{"no roles", "bob", newRequest("", "", nil, adminpanel.ReadAction), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := authorizer.Allowed(tt.user, tt.request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if allowed != tt.expected {
+				t.Errorf("Allowed() = %v, expected %v", allowed, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewPermissionFunc(t *testing.T) {
+	fetcher := func(ctx interface{}) (interface{}, string, error) {
+		if ctx == nil {
+			return nil, "", errors.New("no session")
+		}
+		return ctx, "User", nil
+	}
+	permissionFunc := NewPermissionFunc(newTestStore(t), SubjectFromUserFetcher(fetcher))
+
+	allowed, err := permissionFunc(newRequest("billing", "", nil, adminpanel.ReadAction), "alice")
+	if err != nil || !allowed {
+		t.Errorf("expected alice to be allowed, got %v, %v", allowed, err)
+	}
+	if _, err = permissionFunc(newRequest("billing", "", nil, adminpanel.ReadAction), nil); err == nil {
+		t.Error("expected the fetcher error to be returned")
+	}
+}
+
+func TestAuthorizer_CheckPermissions(t *testing.T) {
+	authorizer := NewAuthorizer(newTestStore(t), func(ctx interface{}) (string, error) { return ctx.(string), nil })
+	requests := []adminpanel.PermissionRequest{
+		newRequest("billing", "Invoice", 1, adminpanel.ReadAction),
+		newRequest("billing", "Invoice", 1, adminpanel.DeleteAction),
+		newRequest("shop", "Order", 2, adminpanel.UpdateAction),
+		newRequest(AdminAppName, "", nil, adminpanel.ReadAction),
+	}
+
+	results, err := authorizer.CheckPermissions(requests, "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, expected := range []bool{true, false, true, false} {
+		if results[i] != expected {
+			t.Errorf("request %d: expected %v, got %v", i, expected, results[i])
+		}
+	}
+
+	results, _ = authorizer.CheckPermissions(requests, "root")
+	for i, allowed := range results {
+		if !allowed {
+			t.Errorf("request %d: expected superuser to be allowed", i)
+		}
+	}
+
+	results, _ = authorizer.CheckPermissions(requests, "")
+	for i, allowed := range results {
+		if allowed {
+			t.Errorf("request %d: expected anonymous user to be denied", i)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package rbac
+
+import (
+	"fmt"
+	"github.com/go-advanced-admin/admin/internal/adminpanel"
+	"path"
+	"strings"
+)
+
+// Wildcard matches any app, model, instance or action in a grant.
+const Wildcard = "*"
+
+// Grant allows an action on an (app, model, instance) resource. Each part is a glob pattern where "*" matches any
+// sequence of characters, so "billing.*:read" allows reading every model of the billing app and "*:*" allows
+// everything.
+type Grant struct {
+	App      string
+	Model    string
+	Instance string
+	Action   string
+}
+
+// ParseGrant parses a grant written as "<app>[.<model>[#<instance>]]:<action>". Omitted models and instances default
+// to the wildcard, so "billing:read" is equivalent to "billing.*#*:read".
+func ParseGrant(s string) (Grant, error) {
+	s = strings.TrimSpace(s)
+	resource, action, found := strings.Cut(s, ":")
+	if !found || resource == "" || action == "" {
+		return Grant{}, fmt.Errorf("invalid grant '%s': expected <app>[.<model>[#<instance>]]:<action>", s)
+	}
+
+	grant := Grant{Model: Wildcard, Instance: Wildcard, Action: action}
+	resource, instance, hasInstance := strings.Cut(resource, "#")
+	app, model, hasModel := strings.Cut(resource, ".")
+	grant.App = app
+	if hasModel {
+		grant.Model = model
+	}
+	if hasInstance {
+		grant.Instance = instance
+	}
+
+	for _, pattern := range []string{grant.App, grant.Model, grant.Instance, grant.Action} {
+		if pattern == "" {
+			return Grant{}, fmt.Errorf("invalid grant '%s': empty pattern", s)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return Grant{}, fmt.Errorf("invalid grant '%s': %w", s, err)
+		}
+	}
+	return grant, nil
+}
+
+// ParseGrants parses a comma or newline separated list of grants. Empty entries are ignored.
+func ParseGrants(s string) ([]Grant, error) {
+	grants := make([]Grant, 0)
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		grant, err := ParseGrant(part)
+		if err != nil {
+			return nil, err
+		}
+		grants = append(grants, grant)
+	}
+	return grants, nil
+}
+
+// FormatGrants formats grants as a comma separated list accepted by ParseGrants.
+func FormatGrants(grants []Grant) string {
+	parts := make([]string, len(grants))
+	for i, grant := range grants {
+		parts[i] = grant.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// String returns the grant in the format accepted by ParseGrant.
+func (g Grant) String() string {
+	resource := g.App
+	if g.Model != Wildcard || g.Instance != Wildcard {
+		resource += "." + g.Model
+	}
+	if g.Instance != Wildcard {
+		resource += "#" + g.Instance
+	}
+	return resource + ":" + g.Action
+}
+
+// Matches reports whether the grant allows the request. Read requests on an enclosing level, such as reading the app
+// of a granted model, match as well so the panel can be navigated down to the granted resources. Other actions on an
+// enclosing level only match grants covering every resource below it, so a grant pinned to an instance never allows
+// creating or updating instances of its model.
+func (g Grant) Matches(request adminpanel.PermissionRequest) bool {
+	if request.Action == nil || !matchPattern(g.Action, string(*request.Action)) {
+		return false
+	}
+	enclosing := *request.Action == adminpanel.ReadAction
+	if request.AppName == nil {
+		return enclosing || g.App == Wildcard && g.Model == Wildcard && g.Instance == Wildcard
+	}
+	if !matchPattern(g.App, *request.AppName) {
+		return false
+	}
+	if request.ModelName == nil {
+		return enclosing || g.Model == Wildcard && g.Instance == Wildcard
+	}
+	if !matchPattern(g.Model, *request.ModelName) {
+		return false
+	}
+	if request.InstanceID == nil {
+		return enclosing || g.Instance == Wildcard
+	}
+	return matchPattern(g.Instance, fmt.Sprint(request.InstanceID))
+}
+
+func matchPattern(pattern, value string) bool {
+	if pattern == Wildcard {
+		return true
+	}
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
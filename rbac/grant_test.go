@@ -0,0 +1,120 @@
+package rbac
+
+import (
+	"github.com/go-advanced-admin/admin/internal/adminpanel"
+	"testing"
+)
+
+func newRequest(app, model string, instance interface{}, action adminpanel.Action) adminpanel.PermissionRequest {
+	request := adminpanel.PermissionRequest{Action: &action, InstanceID: instance}
+	if app != "" {
+		request.AppName = &app
+	}
+	if model != "" {
+		request.ModelName = &model
+	}
+	return request
+}
+
+func TestParseGrant(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Grant
+	}{
+		{"billing:read", Grant{App: "billing", Model: "*", Instance: "*", Action: "read"}},
+		{"billing.*:read", Grant{App: "billing", Model: "*", Instance: "*", Action: "read"}},
+		{"billing.Invoice:update", Grant{App: "billing", Model: "Invoice", Instance: "*", Action: "update"}},
+		{"billing.Invoice#42:delete", Grant{App: "billing", Model: "Invoice", Instance: "42", Action: "delete"}},
+		{" *:* ", Grant{App: "*", Model: "*", Instance: "*", Action: "*"}},
+		{"shop.Order:bulk:*", Grant{App: "shop", Model: "Order", Instance: "*", Action: "bulk:*"}},
+	}
+
+	for _, tt := range tests {
+		grant, err := ParseGrant(tt.input)
+		if err != nil {
+			t.Errorf("ParseGrant(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if grant != tt.expected {
+			t.Errorf("ParseGrant(%q) = %+v, expected %+v", tt.input, grant, tt.expected)
+		}
+	}
+
+	for _, input := range []string{"", "billing", ":read", "billing:", "billing.:read", "billing.[:read"} {
+		if _, err := ParseGrant(input); err == nil {
+			t.Errorf("ParseGrant(%q) expected an error", input)
+		}
+	}
+}
+
+func TestParseGrantsAndFormat(t *testing.T) {
+	grants, err := ParseGrants("billing.*:read,\nshop.Order#7:update, ,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(grants) != 2 {
+		t.Fatalf("expected 2 grants, got %d", len(grants))
+	}
+	formatted := FormatGrants(grants)
+	if formatted != "billing:read, shop.Order#7:update" {
+		t.Errorf("unexpected formatted grants: %s", formatted)
+	}
+	reparsed, err := ParseGrants(formatted)
+	if err != nil || len(reparsed) != 2 || reparsed[0] != grants[0] || reparsed[1] != grants[1] {
+		t.Errorf("formatted grants did not round trip: %+v, %v", reparsed, err)
+	}
+}
+
+func TestGrant_Matches(t *testing.T) {
+	grant, _ := ParseGrant("billing.Inv*#4?:read")
+
+	tests := []struct {
+		name     string
+		request  adminpanel.PermissionRequest
+		expected bool
+	}{
+		{"panel", newRequest("", "", nil, adminpanel.ReadAction), true},
+		{"app", newRequest("billing", "", nil, adminpanel.ReadAction), true},
+		{"model", newRequest("billing", "Invoice", nil, adminpanel.ReadAction), true},
+		{"instance", newRequest("billing", "Invoice", 42, adminpanel.ReadAction), true},
+		{"other instance", newRequest("billing", "Invoice", 7, adminpanel.ReadAction), false},
+		{"other model", newRequest("billing", "Customer", nil, adminpanel.ReadAction), false},
+		{"other app", newRequest("shop", "", nil, adminpanel.ReadAction), false},
+		{"other action", newRequest("billing", "Invoice", 42, adminpanel.UpdateAction), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := grant.Matches(tt.request); got != tt.expected {
+				t.Errorf("Matches() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGrant_Matches_Mutations(t *testing.T) {
+	pinned, _ := ParseGrant("billing.Invoice#42:*")
+	model, _ := ParseGrant("billing.Invoice:create")
+
+	tests := []struct {
+		name     string
+		grant    Grant
+		request  adminpanel.PermissionRequest
+		expected bool
+	}{
+		{"pinned instance", pinned, newRequest("billing", "Invoice", 42, adminpanel.UpdateAction), true},
+		{"pinned model create", pinned, newRequest("billing", "Invoice", nil, adminpanel.CreateAction), false},
+		{"pinned model update", pinned, newRequest("billing", "Invoice", nil, adminpanel.UpdateAction), false},
+		{"pinned model read", pinned, newRequest("billing", "Invoice", nil, adminpanel.ReadAction), true},
+		{"model create", model, newRequest("billing", "Invoice", nil, adminpanel.CreateAction), true},
+		{"app create", model, newRequest("billing", "", nil, adminpanel.CreateAction), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.grant.Matches(tt.request); got != tt.expected {
+				t.Errorf("Matches() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
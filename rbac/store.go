@@ -0,0 +1,210 @@
+package rbac
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrNotFound is returned by stores when a role, group or user does not exist.
+var ErrNotFound = errors.New("not found")
+
+// Role is a named set of grants.
+type Role struct {
+	Name        string
+	Description string
+	Grants      []Grant
+}
+
+// Group is a named set of roles shared by its members.
+type Group struct {
+	Name        string
+	Description string
+	Roles       []string
+}
+
+// User holds the roles and groups assigned to a user. Superusers are allowed every action.
+type User struct {
+	ID        string
+	Superuser bool
+	Roles     []string
+	Groups    []string
+}
+
+// Store persists roles, groups and user assignments. Get methods return ErrNotFound when the entry does not exist and
+// list methods return entries sorted by name or ID.
+type Store interface {
+	GetRole(name string) (*Role, error)
+	ListRoles() ([]*Role, error)
+	SaveRole(role *Role) error
+	DeleteRole(name string) error
+
+	GetGroup(name string) (*Group, error)
+	ListGroups() ([]*Group, error)
+	SaveGroup(group *Group) error
+	DeleteGroup(name string) error
+
+	GetUser(id string) (*User, error)
+	ListUsers() ([]*User, error)
+	SaveUser(user *User) error
+	DeleteUser(id string) error
+}
+
+// MemoryStore is a Store keeping everything in memory. It is safe for concurrent use.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	roles  map[string]Role
+	groups map[string]Group
+	users  map[string]User
+}
+
+// NewMemoryStore creates a new empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		roles:  make(map[string]Role),
+		groups: make(map[string]Group),
+		users:  make(map[string]User),
+	}
+}
+
+// GetRole returns the role with the given name.
+func (s *MemoryStore) GetRole(name string) (*Role, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	role, ok := s.roles[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyRole(role), nil
+}
+
+// ListRoles returns every role sorted by name.
+func (s *MemoryStore) ListRoles() ([]*Role, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	roles := make([]*Role, 0, len(s.roles))
+	for _, role := range s.roles {
+		roles = append(roles, copyRole(role))
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles, nil
+}
+
+// SaveRole creates or replaces the role with the same name.
+func (s *MemoryStore) SaveRole(role *Role) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roles[role.Name] = *copyRole(*role)
+	return nil
+}
+
+// DeleteRole deletes the role with the given name.
+func (s *MemoryStore) DeleteRole(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.roles[name]; !ok {
+		return ErrNotFound
+	}
+	delete(s.roles, name)
+	return nil
+}
+
+// GetGroup returns the group with the given name.
+func (s *MemoryStore) GetGroup(name string) (*Group, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	group, ok := s.groups[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyGroup(group), nil
+}
+
+// ListGroups returns every group sorted by name.
+func (s *MemoryStore) ListGroups() ([]*Group, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	groups := make([]*Group, 0, len(s.groups))
+	for _, group := range s.groups {
+		groups = append(groups, copyGroup(group))
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups, nil
+}
+
+// SaveGroup creates or replaces the group with the same name.
+func (s *MemoryStore) SaveGroup(group *Group) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groups[group.Name] = *copyGroup(*group)
+	return nil
+}
+
+// DeleteGroup deletes the group with the given name.
+func (s *MemoryStore) DeleteGroup(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.groups[name]; !ok {
+		return ErrNotFound
+	}
+	delete(s.groups, name)
+	return nil
+}
+
+// GetUser returns the user with the given ID.
+func (s *MemoryStore) GetUser(id string) (*User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	user, ok := s.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyUser(user), nil
+}
+
+// ListUsers returns every user sorted by ID.
+func (s *MemoryStore) ListUsers() ([]*User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	users := make([]*User, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, copyUser(user))
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+// SaveUser creates or replaces the user with the same ID.
+func (s *MemoryStore) SaveUser(user *User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[user.ID] = *copyUser(*user)
+	return nil
+}
+
+// DeleteUser deletes the user with the given ID.
+func (s *MemoryStore) DeleteUser(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.users, id)
+	return nil
+}
+
+func copyRole(role Role) *Role {
+	role.Grants = append([]Grant(nil), role.Grants...)
+	return &role
+}
+
+func copyGroup(group Group) *Group {
+	group.Roles = append([]string(nil), group.Roles...)
+	return &group
+}
+
+func copyUser(user User) *User {
+	user.Roles = append([]string(nil), user.Roles...)
+	user.Groups = append([]string(nil), user.Groups...)
+	return &user
+}
@@ -0,0 +1,70 @@
+package rbac
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemoryStore_Roles(t *testing.T) {
+	store := NewMemoryStore()
+	if _, err := store.GetRole("viewer"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	role := &Role{Name: "viewer", Grants: []Grant{{App: "*", Model: "*", Instance: "*", Action: "read"}}}
+	if err := store.SaveRole(role); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	role.Grants[0].Action = "delete"
+
+	stored, err := store.GetRole("viewer")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.Grants[0].Action != "read" {
+		t.Errorf("store should keep a copy of the role, got action %s", stored.Grants[0].Action)
+	}
+
+	_ = store.SaveRole(&Role{Name: "admin"})
+	roles, err := store.ListRoles()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(roles) != 2 || roles[0].Name != "admin" || roles[1].Name != "viewer" {
+		t.Errorf("expected roles sorted by name, got %+v", roles)
+	}
+
+	if err = store.DeleteRole("viewer"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = store.DeleteRole("viewer"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStore_GroupsAndUsers(t *testing.T) {
+	store := NewMemoryStore()
+	_ = store.SaveGroup(&Group{Name: "staff", Roles: []string{"viewer"}})
+	_ = store.SaveUser(&User{ID: "1", Groups: []string{"staff"}})
+
+	group, err := store.GetGroup("staff")
+	if err != nil || len(group.Roles) != 1 {
+		t.Fatalf("unexpected group %+v, error %v", group, err)
+	}
+	user, err := store.GetUser("1")
+	if err != nil || len(user.Groups) != 1 {
+		t.Fatalf("unexpected user %+v, error %v", user, err)
+	}
+
+	if err = store.DeleteGroup("staff"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = store.DeleteUser("1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	groups, _ := store.ListGroups()
+	users, _ := store.ListUsers()
+	if len(groups) != 0 || len(users) != 0 {
+		t.Errorf("expected empty store, got %d groups and %d users", len(groups), len(users))
+	}
+}